@@ -0,0 +1,601 @@
+package cmd
+
+import (
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBatchFuncs(t *testing.T) {
+	files := generate(t, "sqlite3", `CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL
+);
+CREATE TABLE books (
+  isbn TEXT PRIMARY KEY,
+  title TEXT NOT NULL,
+  title_len INTEGER GENERATED ALWAYS AS (length(title)) VIRTUAL
+);
+`)
+	tests := []struct {
+		file string
+		exp  []string
+	}{
+		{"db.xo.go", []string{
+			"func batches(count, n int) ([][2]int, error) {\n\tif maxParams < n {\n\t\treturn nil, ErrTooManyParams\n\t}",
+			`ErrRowCount Error = "unexpected number of returned rows"`,
+		}},
+		{"author.xo.go", []string{
+			"ranges, err := batches(len(as), 1)\n\tif err != nil {\n\t\treturn logerror(&ErrInsertFailed{err})\n\t}",
+			"id, err := res.LastInsertId()",
+			"ranges, err := batches(len(as), 2)\n\tif err != nil {\n\t\treturn logerror(&ErrUpsertFailed{err})\n\t}",
+		}},
+		{"book.xo.go", []string{
+			// manual insert refreshes generated fields
+			"valuesList(len(batch), 2) +\n\t\t\t` RETURNING title_len`",
+			"if n != len(batch) {\n\t\t\treturn logerror(&ErrInsertFailed{ErrRowCount})\n\t\t}",
+			// upsert refreshes generated fields
+			"`title = EXCLUDED.title RETURNING title_len`",
+			"if n != len(batch) {\n\t\t\treturn logerror(&ErrUpsertFailed{ErrRowCount})\n\t\t}",
+			"if err := rows.Err(); err != nil {",
+		}},
+	}
+	for _, test := range tests {
+		for _, exp := range test.exp {
+			if !strings.Contains(files[test.file], exp) {
+				t.Errorf("expected %s to contain:\n%s", test.file, exp)
+			}
+		}
+	}
+}
+
+// reverseDDL is a schema with foreign keys across schemas, and an annotated
+// type name.
+const reverseDDL = `CREATE SCHEMA lib;
+CREATE TABLE public.authors (
+  author_id SERIAL PRIMARY KEY
+);
+CREATE TABLE lib.shelves (
+  shelf_id SERIAL PRIMARY KEY
+);
+COMMENT ON TABLE lib.shelves IS 'xo:name=rack';
+CREATE TABLE lib.books (
+  book_id SERIAL PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES public.authors (author_id)
+);
+CREATE TABLE public.favorites (
+  author_id INTEGER NOT NULL REFERENCES public.authors (author_id),
+  shelf_id INTEGER NOT NULL REFERENCES lib.shelves (shelf_id),
+  PRIMARY KEY (author_id, shelf_id)
+);
+`
+
+func TestReverseForeignKeys(t *testing.T) {
+	files := generate(t, "postgres", reverseDDL, "--schema", "public,lib")
+	tests := []struct {
+		file string
+		exp  string
+	}{
+		{"author.xo.go", "func (a *Author) Favorites(ctx context.Context, db DB) ([]*Favorite, error) {"},
+		{"author.xo.go", "func (a *Author) LibBooks(ctx context.Context, db DB) ([]*LibBook, error) {"},
+		{"librack.xo.go", "func (lr *LibRack) Favorites(ctx context.Context, db DB) ([]*Favorite, error) {"},
+		{"favorite.xo.go", "func (f *Favorite) Rack(ctx context.Context, db DB) (*LibRack, error) {"},
+		{"libbook.xo.go", "func (lb *LibBook) Author(ctx context.Context, db DB) (*Author, error) {"},
+	}
+	for _, test := range tests {
+		if !strings.Contains(files[test.file], test.exp) {
+			t.Errorf("expected %s to contain:\n%s", test.file, test.exp)
+		}
+	}
+}
+
+// generate generates code with the go template for the ddl, returning the
+// contents of the generated files.
+func generate(t *testing.T, driver, ddl string, args ...string) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(file, []byte(ddl), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := os.Mkdir(out, 0o755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	args = append([]string{"schema", "--ddl", file, "--dialect", driver, "--out", out}, args...)
+	if err := Run(context.Background(), "xo", "0.0.0-dev", args...); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		buf, err := os.ReadFile(filepath.Join(out, entry.Name()))
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		files[entry.Name()] = string(buf)
+	}
+	return files
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		driver string
+		from   string
+		to     string
+		exp    []string
+	}{
+		{"sqlite3", `CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  nick TEXT
+);
+CREATE TABLE old_tags (
+  tag_id INTEGER PRIMARY KEY,
+  tag TEXT NOT NULL
+);
+CREATE VIEW author_names AS SELECT name FROM authors;
+CREATE VIEW unchanged AS SELECT author_id FROM authors;
+`, `CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  nickname TEXT,
+  born DATE,
+  country TEXT NOT NULL
+);
+CREATE TABLE tags (
+  tag_id INTEGER PRIMARY KEY,
+  tag TEXT NOT NULL
+);
+CREATE VIEW author_names AS SELECT name, nickname FROM authors;
+CREATE VIEW unchanged AS SELECT author_id FROM authors;
+`, []string{
+			"-- NOTE: old_tags is dropped and tags is created with the same columns; if renamed, replace with: ALTER TABLE old_tags RENAME TO tags;",
+			"-- NOTE: authors.nick is dropped and authors.nickname is added with the same definition; if renamed, replace with: ALTER TABLE authors RENAME COLUMN nick TO nickname;",
+			"-- drop view author_names\nDROP VIEW author_names;",
+			"-- drop table old_tags\nDROP TABLE old_tags;",
+			"-- add column authors.born\nALTER TABLE authors ADD COLUMN born DATE;",
+			"-- drop column authors.nick\nALTER TABLE authors DROP COLUMN nick;",
+			"-- view author_names\nCREATE VIEW author_names AS SELECT name, nickname FROM authors;",
+			"-- add column authors.country\nALTER TABLE authors ADD COLUMN country TEXT;\n-- NOTE: authors.country is added as nullable, as sqlite3 cannot add a not null column without a default\n",
+		}},
+		{"postgres", `CREATE TABLE authors (
+  author_id integer PRIMARY KEY
+);
+`, `CREATE TABLE authors (
+  author_id integer PRIMARY KEY,
+  name text NOT NULL,
+  kind text NOT NULL DEFAULT 'person'
+);
+`, []string{
+			"-- add column authors.name\nALTER TABLE authors ADD COLUMN name TEXT;\n-- NOTE: authors.name is added as nullable; set its values before altering it to not null\nALTER TABLE authors ALTER COLUMN name SET NOT NULL;\n",
+			"-- add column authors.kind\nALTER TABLE authors ADD COLUMN kind TEXT DEFAULT 'person' NOT NULL;\n",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.driver, func(t *testing.T) {
+			dir := t.TempDir()
+			from, to := dump(t, dir, "from", test.driver, test.from), dump(t, dir, "to", test.driver, test.to)
+			if err := Run(context.Background(), "xo", "0.0.0-dev", "diff", from, to, "--out", dir); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			buf, err := os.ReadFile(filepath.Join(dir, "xo.xo.diff.sql"))
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			for _, exp := range test.exp {
+				if !strings.Contains(string(buf), exp) {
+					t.Errorf("expected diff to contain:\n%s\ngot:\n%s", exp, buf)
+				}
+			}
+			if strings.Contains(string(buf), "unchanged") {
+				t.Errorf("expected unchanged view to not be in diff, got:\n%s", buf)
+			}
+		})
+	}
+}
+
+// dump dumps the schema for the ddl as json, returning the path of the dumped
+// file.
+func dump(t *testing.T, dir, name, driver, ddl string, args ...string) string {
+	t.Helper()
+	file := filepath.Join(dir, name+".sql")
+	if err := os.WriteFile(file, []byte(ddl), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	out := filepath.Join(dir, name)
+	if err := os.Mkdir(out, 0o755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	args = append([]string{"schema", "--ddl", file, "--dialect", driver, "--template", "json", "--out", out}, args...)
+	if err := Run(context.Background(), "xo", "0.0.0-dev", args...); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return filepath.Join(out, "xo.xo.json")
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		driver string
+		ddl    string
+		args   []string
+		exp    []string
+		notExp []string
+	}{
+		{"postgres", `CREATE TABLE users (
+  user_id SERIAL PRIMARY KEY,
+  settings JSONB NOT NULL,
+  prefs JSON,
+  tags JSONB NOT NULL
+);
+COMMENT ON COLUMN users.settings IS 'xo:type=Settings';
+`, nil, []string{
+			"type JSON[T any] struct {",
+			"Settings JSON[Settings]",
+			"Prefs    NullJSON[json.RawMessage]",
+			"Tags     JSON[[]string]",
+		}, nil},
+		{"mysql", `CREATE TABLE users (
+  user_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+  settings JSON NOT NULL,
+  tags JSON NOT NULL
+);
+`, []string{"--schema", "booktest"}, []string{
+			"type JSON[T any] struct {",
+			"Settings JSON[json.RawMessage]",
+			"Tags     JSON[[]string]",
+		}, nil},
+		{"sqlite3", `CREATE TABLE users (
+  user_id INTEGER PRIMARY KEY,
+  settings JSON NOT NULL,
+  tags JSON NOT NULL
+);
+`, nil, []string{
+			"Settings string",
+			"Tags     []string",
+		}, []string{"JSON["}},
+	}
+	for _, test := range tests {
+		t.Run(test.driver, func(t *testing.T) {
+			args := append([]string{"--go-json", "--go-type", "users.tags=[]string"}, test.args...)
+			files := generate(t, test.driver, test.ddl, args...)
+			s := files["db.xo.go"] + files["user.xo.go"]
+			for _, exp := range test.exp {
+				if !strings.Contains(s, exp) {
+					t.Errorf("expected generated code to contain:\n%s", exp)
+				}
+			}
+			for _, exp := range test.notExp {
+				if strings.Contains(s, exp) {
+					t.Errorf("expected generated code to not contain:\n%s", exp)
+				}
+			}
+		})
+	}
+}
+
+func TestPgxTypes(t *testing.T) {
+	ddl := `CREATE TABLE items (
+  item_id UUID PRIMARY KEY,
+  price NUMERIC(10, 2) NOT NULL,
+  discount NUMERIC,
+  created_at TIMESTAMP(3) WITH TIME ZONE NOT NULL
+);
+`
+	for _, uuid := range []string{"", "github.com/gofrs/uuid/v5"} {
+		files := generate(t, "postgres", ddl, "--go-driver", "pgx", "--go-uuid", uuid)
+		pkg := uuid
+		if pkg == "" {
+			pkg = "github.com/google/uuid"
+		}
+		for _, exp := range []string{
+			strconv.Quote(pkg),
+			"ItemID    uuid.UUID",
+			"Price     pgtype.Numeric",
+			"Discount  pgtype.Numeric",
+			"CreatedAt time.Time",
+		} {
+			if !strings.Contains(files["item.xo.go"], exp) {
+				t.Errorf("expected item.xo.go to contain:\n%s", exp)
+			}
+		}
+	}
+}
+
+func TestNullMode(t *testing.T) {
+	ddl := `CREATE TABLE authors (
+  author_id INTEGER NOT NULL PRIMARY KEY,
+  name TEXT NOT NULL
+);
+CREATE TABLE books (
+  book_id INTEGER PRIMARY KEY,
+  coauthor_id INTEGER REFERENCES authors (author_id),
+  subtitle TEXT
+);
+`
+	tests := []struct {
+		mode string
+		exp  []string
+	}{
+		{"sql", []string{
+			"CoauthorID sql.NullInt64",
+			"Subtitle   sql.NullString",
+			"AuthorByAuthorID(ctx, db, int(b.CoauthorID.Int64))",
+		}},
+		{"pointer", []string{
+			"CoauthorID *int",
+			"Subtitle   *string",
+			"AuthorByAuthorID(ctx, db, deref(b.CoauthorID))",
+		}},
+		{"generic", []string{
+			"CoauthorID sql.Null[int]",
+			"Subtitle   sql.Null[string]",
+			"AuthorByAuthorID(ctx, db, b.CoauthorID.V)",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			files := generate(t, "sqlite3", ddl, "--go-null-mode", test.mode)
+			for _, exp := range test.exp {
+				if !strings.Contains(files["book.xo.go"], exp) {
+					t.Errorf("expected book.xo.go to contain:\n%s", exp)
+				}
+			}
+			if s := "func deref["; test.mode == "pointer" != strings.Contains(files["db.xo.go"], s) {
+				t.Errorf("expected db.xo.go to contain %q only in pointer mode", s)
+			}
+		})
+	}
+}
+
+func TestInsertDefaults(t *testing.T) {
+	tests := []struct {
+		driver string
+		ddl    string
+		args   []string
+		exp    []string
+	}{
+		{"postgres", `CREATE TABLE users (
+  user_id SERIAL PRIMARY KEY,
+  email TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'active',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`, nil, []string{
+			`cols, args := []string{"email"}, []interface{}{u.Email}`,
+			"if !isZero(u.Status) {\n\t\tcols, args = append(cols, \"status\"), append(args, u.Status)\n\t}",
+			"if !isZero(u.CreatedAt) {\n\t\tcols, args = append(cols, \"created_at\"), append(args, u.CreatedAt)\n\t}",
+			`sqlstr := insertSQL("public.users", cols, []string{"user_id", "status", "created_at"})`,
+			".Scan(&u.UserID, &u.Status, &u.CreatedAt)",
+		}},
+		{"sqlite3", `CREATE TABLE users (
+  user_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'active'
+);
+`, nil, []string{
+			`cols, args := []string{"email"}, []interface{}{u.Email}`,
+			`sqlstr := insertSQL("users", cols, []string{"user_id", "status"})`,
+			".Scan(&u.UserID, &u.Status)",
+		}},
+		{"mysql", `CREATE TABLE users (
+  user_id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  email VARCHAR(255) NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'active'
+);
+`, []string{"--schema", "booktest"}, []string{
+			`sqlstr := insertSQL("booktest.users", cols, []string{"user_id", "status"})`,
+			".Scan(&u.UserID, &u.Status)",
+		}},
+		{"sqlserver", `CREATE TABLE users (
+  user_id INT IDENTITY(1, 1) PRIMARY KEY,
+  email NVARCHAR(255) NOT NULL,
+  status NVARCHAR(16) NOT NULL DEFAULT 'active'
+);
+`, nil, []string{
+			`sqlstr := insertSQL("dbo.users", cols, []string{"user_id", "status"})`,
+			".Scan(&u.UserID, &u.Status)",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.driver, func(t *testing.T) {
+			files := generate(t, test.driver, test.ddl, test.args...)
+			if strings.Contains(files["user.xo.go"], "insertSQL(") || strings.Contains(files["db.xo.go"], "func insertSQL(") {
+				t.Errorf("expected no insertSQL without --go-insert-defaults")
+			}
+			files = generate(t, test.driver, test.ddl, append(test.args, "--go-insert-defaults")...)
+			for _, exp := range test.exp {
+				if !strings.Contains(files["user.xo.go"], exp) {
+					t.Errorf("expected user.xo.go to contain:\n%s", exp)
+				}
+			}
+			for _, exp := range []string{"func isZero(", "func insertSQL("} {
+				if !strings.Contains(files["db.xo.go"], exp) {
+					t.Errorf("expected db.xo.go to contain:\n%s", exp)
+				}
+			}
+			// batch inserts insert each row
+			if s := "func InsertManyUsers("; strings.Contains(files["user.xo.go"], s) {
+				if exp := "for _, u := range us {\n\t\tif err := u.Insert(ctx, db); err != nil {"; !strings.Contains(files["user.xo.go"], exp) {
+					t.Errorf("expected user.xo.go to contain:\n%s", exp)
+				}
+			}
+			typecheck(t, files)
+		})
+	}
+}
+
+func TestForeignKeysSameTable(t *testing.T) {
+	files := generate(t, "sqlite3", `CREATE TABLE authors (
+  author_id INTEGER NOT NULL PRIMARY KEY,
+  name TEXT NOT NULL
+);
+CREATE TABLE books (
+  book_id INTEGER NOT NULL PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  editor_id INTEGER NOT NULL REFERENCES authors (author_id)
+);
+`)
+	tests := []struct {
+		file string
+		exp  string
+	}{
+		{"book.xo.go", "func (b *Book) AuthorByAuthorID(ctx context.Context, db DB) (*Author, error) {"},
+		{"book.xo.go", "func (b *Book) AuthorByEditorID(ctx context.Context, db DB) (*Author, error) {"},
+		{"author.xo.go", "func (a *Author) BooksByAuthorID(ctx context.Context, db DB) ([]*Book, error) {"},
+		{"author.xo.go", "func (a *Author) BooksByEditorID(ctx context.Context, db DB) ([]*Book, error) {"},
+	}
+	for _, test := range tests {
+		if !strings.Contains(files[test.file], test.exp) {
+			t.Errorf("expected %s to contain:\n%s", test.file, test.exp)
+		}
+	}
+	typecheck(t, files)
+}
+
+// stdImporter imports packages from source, shared by tests to reuse the
+// imported packages.
+var stdImporter = importer.ForCompiler(token.NewFileSet(), "source", nil)
+
+// typecheck type checks the generated files.
+func typecheck(t *testing.T, files map[string]string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var v []*ast.File
+	for name, src := range files {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		v = append(v, f)
+	}
+	conf := types.Config{Importer: stdImporter}
+	if _, err := conf.Check("models", fset, v, nil); err != nil {
+		t.Errorf("expected generated code to type check, got: %v", err)
+	}
+}
+
+func TestGenerateFrom(t *testing.T) {
+	exp := generate(t, "postgres", reverseDDL, "--schema", "public,lib")
+	dir := t.TempDir()
+	file := dump(t, dir, "set", "postgres", reverseDDL, "--schema", "public,lib")
+	out := filepath.Join(dir, "out")
+	if err := os.Mkdir(out, 0o755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := Run(context.Background(), "xo", "0.0.0-dev", "generate", "--from", file, "--out", out); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(entries) != len(exp) {
+		t.Errorf("expected %d files, got: %d", len(exp), len(entries))
+	}
+	for _, entry := range entries {
+		buf, err := os.ReadFile(filepath.Join(out, entry.Name()))
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if s := string(buf); s != exp[entry.Name()] {
+			t.Errorf("expected %s to be the same as generated directly, got:\n%s", entry.Name(), s)
+		}
+	}
+}
+
+func TestComputedColumns(t *testing.T) {
+	files := generate(t, "sqlserver", `CREATE TABLE authors (
+  author_id INT IDENTITY(1, 1) PRIMARY KEY,
+  name NVARCHAR(255) NOT NULL,
+  name_len AS LEN(name) PERSISTED
+);
+`)
+	if exp := "NameLen  sql.NullInt64"; !strings.Contains(files["author.xo.go"], exp) {
+		t.Errorf("expected author.xo.go to contain:\n%s", exp)
+	}
+	if strings.Contains(files["author.xo.go"], "name_len = ") {
+		t.Errorf("expected name_len to not be updated")
+	}
+	typecheck(t, files)
+}
+
+func TestInsertManyIDs(t *testing.T) {
+	const ddl = `CREATE TABLE authors (
+  author_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL
+);
+`
+	db := sqliteDB(t, ddl+"INSERT INTO authors (author_id, name) VALUES (10, 'existing');")
+	files := generate(t, "sqlite3", ddl, "--go-pkg", "main")
+	files["main.go"] = `package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+	authors := []*Author{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := InsertManyAuthors(context.Background(), db, authors); err != nil {
+		panic(err)
+	}
+	for _, a := range authors {
+		b, err := AuthorByAuthorID(context.Background(), db, a.AuthorID)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%d:%s=%s ", a.AuthorID, a.Name, b.Name)
+	}
+}
+`
+	if s, exp := runGenerated(t, files, db), "11:a=a 12:b=b 13:c=c "; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}
+
+// runGenerated runs the generated files of a main package in the module,
+// returning the output.
+func runGenerated(t *testing.T, files map[string]string, args ...string) string {
+	t.Helper()
+	dir, err := os.MkdirTemp(".", "_xotest")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, s := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(s), 0o644); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	cmd := exec.Command("go", append([]string{"run", "."}, args...)...)
+	cmd.Dir = dir
+	buf, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v\n%s", err, buf)
+	}
+	return string(buf)
+}
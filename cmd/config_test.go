@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		s    string
+		err  string
+	}{
+		{"xo.yaml", `database: postgres://localhost/booktest
+flags:
+  out: models
+  go-initialism: [ISBN]
+schemas:
+  - template: go
+  - template: createdb
+    out: /abs/createdb
+queries:
+  - file: sql/authors.sql
+    out: ../queries
+types:
+  - db_type: jsonb
+    type: encoding/json.RawMessage
+  - column: users.settings
+    type: github.com/acme/types.Settings
+    nullable: '*github.com/acme/types.Settings'
+`, ""},
+		{"xo.toml", `database = "postgres://localhost/booktest"
+[flags]
+out = "models"
+go-initialism = ["ISBN"]
+[[schemas]]
+template = "go"
+[[schemas]]
+template = "createdb"
+out = "/abs/createdb"
+[[queries]]
+file = "sql/authors.sql"
+out = "../queries"
+[[types]]
+db_type = "jsonb"
+type = "encoding/json.RawMessage"
+[[types]]
+column = "users.settings"
+type = "github.com/acme/types.Settings"
+nullable = "*github.com/acme/types.Settings"
+`, ""},
+		{"unknown.yaml", "database: postgres://localhost/booktest\nschemaz:\n  - template: go\n", `unknown field "schemaz"`},
+		{"unknown.toml", "database = \"postgres://localhost/booktest\"\n[[schemaz]]\ntemplate = \"go\"\n", `unknown field "schemaz"`},
+		{"nested.toml", "database = \"postgres://localhost/booktest\"\n[[types]]\ndb_type = \"jsonb\"\ntyp = \"x.T\"\n", `unknown field "types.typ"`},
+		{"types.yaml", "types:\n  - db_type: jsonb\n    column: users.settings\n    type: x.T\n", "types[0]: db_type and column cannot be used together"},
+		{"column.yaml", "types:\n  - column: settings\n    type: x.T\n", `types[0]: column "settings" must be in the form of <table>.<column>`},
+		{"nil.yaml", "flags:\n  out:\n", "flags.out: missing value"},
+		{"nil-entry.yaml", "schemas:\n  - template: go\n    go-initialism: [ISBN, ~]\n", "schemas[0].go-initialism: missing value"},
+		{"map.yaml", "queries:\n  - file: a.sql\n    out: {dir: x}\n", "queries[0].out: invalid value"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name := filepath.Join(dir, test.name)
+			if err := os.WriteFile(name, []byte(test.s), 0o644); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			config, err := LoadConfig(name)
+			switch {
+			case test.err != "" && err == nil:
+				t.Fatalf("expected error %q", test.err)
+			case test.err != "" && !strings.Contains(err.Error(), test.err):
+				t.Fatalf("expected error %q, got: %v", test.err, err)
+			case test.err != "":
+				return
+			case err != nil:
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if s, exp := config.Database, "postgres://localhost/booktest"; s != exp {
+				t.Errorf("expected database %q, got: %q", exp, s)
+			}
+			// relative paths are resolved against the config's directory
+			if s, exp := config.Flags["out"], filepath.Join(dir, "models"); s != exp {
+				t.Errorf("expected flags out %q, got: %q", exp, s)
+			}
+			if s, exp := config.Schemas[1]["out"], "/abs/createdb"; s != exp {
+				t.Errorf("expected schemas[1] out %q, got: %q", exp, s)
+			}
+			if s, exp := config.Queries[0]["file"], filepath.Join(dir, "sql", "authors.sql"); s != exp {
+				t.Errorf("expected queries[0] file %q, got: %q", exp, s)
+			}
+			if s, exp := config.Queries[0]["out"], filepath.Join(filepath.Dir(dir), "queries"); s != exp {
+				t.Errorf("expected queries[0] out %q, got: %q", exp, s)
+			}
+			// types are added to the go-type flag
+			exp := []interface{}{
+				"jsonb=encoding/json.RawMessage",
+				"users.settings=github.com/acme/types.Settings;*github.com/acme/types.Settings",
+			}
+			if v := config.Flags["go-type"]; !reflect.DeepEqual(v, exp) {
+				t.Errorf("expected go-type %v, got: %v", exp, v)
+			}
+		})
+	}
+}
+
+func TestLoadConfigDDL(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "xo.yaml")
+	if err := os.WriteFile(name, []byte("ddl: [schema.sql, /abs/other.sql]\ndialect: sqlite3\n"), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	config, err := LoadConfig(name)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := []string{filepath.Join(dir, "schema.sql"), "/abs/other.sql"}; !reflect.DeepEqual(config.DDL, exp) {
+		t.Errorf("expected ddl %v, got: %v", exp, config.DDL)
+	}
+}
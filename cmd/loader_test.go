@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xo/xo/loader"
+)
+
+func TestParseArg(t *testing.T) {
+	tests := []struct {
+		short, full string
+		args        []string
+		exp         string
+	}{
+		{"-d", "--src", []string{"schema", "-d", "dir"}, "dir"},
+		{"-d", "--src", []string{"schema", "-ddir"}, "dir"},
+		{"-d", "--src", []string{"schema", "--src", "dir"}, "dir"},
+		{"-d", "--src", []string{"schema", "--src=dir"}, "dir"},
+		{"-d", "--src", []string{"schema", "--dialect", "postgres"}, ""},
+		{"-d", "--src", []string{"schema", "-d"}, ""},
+		{"-t", "--template", []string{"schema", "-tjson"}, "json"},
+		{"", "--loader-src", []string{"schema", "--loader-src", "dir"}, "dir"},
+		{"", "--loader-src", []string{"schema", "--loader-src=dir"}, "dir"},
+		{"", "--loader-src", []string{"schema", "--src", "dir"}, ""},
+	}
+	for i, test := range tests {
+		if s := parseArg(test.short, test.full, test.args); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestLoaderSrc(t *testing.T) {
+	// the loader replaces the registered postgres loader
+	l, _ := loader.Lookup("postgres")
+	t.Cleanup(func() {
+		loader.Register("postgres", l)
+	})
+	dir := t.TempDir()
+	src := filepath.Join(dir, "custom")
+	if err := os.Mkdir(src, 0o755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	files := map[string]string{
+		filepath.Join(src, "custom.go"): `//go:build xotpl
+
+package custom
+
+import (
+	"github.com/xo/xo/loader"
+	xo "github.com/xo/xo/types"
+)
+
+func init() {
+	l, _ := loader.Lookup("postgres")
+	l.GoType = func(d xo.Type, schema, itype, utype string) (string, string, error) {
+		if d.Type == "bigint" && !d.Nullable {
+			return "int", "0", nil
+		}
+		return "", "", nil
+	}
+	loader.Register("postgres", l)
+}
+`,
+		filepath.Join(dir, "schema.sql"): `CREATE TABLE items (
+  item_id BIGINT PRIMARY KEY,
+  tags TEXT[] NOT NULL,
+  born DATE
+);
+`,
+		filepath.Join(dir, "xo.yaml"): `ddl: [schema.sql]
+dialect: postgres
+schemas:
+  - out: config
+    go-driver: pgx
+`,
+	}
+	for name, s := range files {
+		if err := os.WriteFile(name, []byte(s), 0o644); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	tests := []struct {
+		args []string
+		out  string
+		exp  string
+	}{
+		{
+			[]string{"schema", "--ddl", filepath.Join(dir, "schema.sql"), "--dialect", "postgres", "--loader-src", src, "--out", filepath.Join(dir, "pq")},
+			"pq",
+			"ItemID int            `json:\"item_id\"` // item_id\n\tTags   pq.StringArray `json:\"tags\"`    // tags\n\tBorn   sql.NullTime   `json:\"born\"`    // born",
+		},
+		{
+			[]string{"schema", "--ddl", filepath.Join(dir, "schema.sql"), "--dialect", "postgres", "--loader-src=" + src, "--go-driver", "pgx", "--out", filepath.Join(dir, "pgx")},
+			"pgx",
+			"ItemID int         `json:\"item_id\"` // item_id\n\tTags   []string    `json:\"tags\"`    // tags\n\tBorn   pgtype.Date `json:\"born\"`    // born",
+		},
+		{
+			[]string{"generate", "--config", filepath.Join(dir, "xo.yaml"), "--loader-src", src},
+			"config",
+			"ItemID int         `json:\"item_id\"` // item_id",
+		},
+	}
+	for i, test := range tests {
+		out := filepath.Join(dir, test.out)
+		if err := os.Mkdir(out, 0o755); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if err := Run(context.Background(), "xo", "0.0.0-dev", test.args...); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		buf, err := os.ReadFile(filepath.Join(out, "item.xo.go"))
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if !strings.Contains(string(buf), test.exp) {
+			t.Errorf("test %d expected item.xo.go to contain:\n%s\ngot:\n%s", i, test.exp, string(buf))
+		}
+	}
+}
@@ -5,60 +5,253 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/xo/xo/loader"
+	"github.com/xo/xo/models"
 	xo "github.com/xo/xo/types"
 )
 
-// LoadQuery loads a query.
+// LoadQuery loads a query, or the annotated queries in the query files of
+// --dir.
 func LoadQuery(ctx context.Context, set *xo.Set, args *Args) error {
+	if args.QueryParams.Dir != "" {
+		return LoadQueryFiles(ctx, set, args)
+	}
+	q, err := loadQuery(ctx, args.QueryParams, "")
+	if err != nil {
+		return err
+	}
+	set.Queries = append(set.Queries, q)
+	return nil
+}
+
+// LoadQueryFiles loads the annotated queries in the .sql files of --dir.
+func LoadQueryFiles(ctx context.Context, set *xo.Set, args *Args) error {
+	files, err := queryFiles(args.QueryParams.Dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		queries, err := ParseQueryFile(file, args.QueryParams)
+		if err != nil {
+			return err
+		}
+		for _, params := range queries {
+			q, err := loadQuery(ctx, params, filepath.Base(file))
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", file, params.Func, err)
+			}
+			set.Queries = append(set.Queries, q)
+		}
+	}
+	return nil
+}
+
+// loadQuery loads a query.
+func loadQuery(ctx context.Context, params QueryParams, file string) (xo.Query, error) {
 	driver, _, _ := xo.DriverDbSchema(ctx)
 	// introspect query if not exec mode
 	query, inspect, comments, fields, err := ParseQuery(
 		ctx,
-		args.QueryParams.Query,
-		args.QueryParams.Delimiter,
-		args.QueryParams.Interpolate,
-		args.QueryParams.Trim,
-		args.QueryParams.Strip,
+		params.Query,
+		params.Delimiter,
+		params.Interpolate,
+		params.Trim,
+		params.Strip,
 	)
 	if err != nil {
-		return err
+		return xo.Query{}, err
+	}
+	// infer untyped params
+	if err := InferParams(ctx, params.Query, params.Delimiter, params.Interpolate, fields); err != nil {
+		return xo.Query{}, err
 	}
 	var typeFields []xo.Field
-	if !args.QueryParams.Exec {
+	if !params.Exec {
 		// build query type
 		typeFields, err = LoadQueryFields(
 			ctx,
 			inspect,
-			args.QueryParams.Fields,
-			args.QueryParams.AllowNulls,
-			args.QueryParams.Flat,
+			params.Fields,
+			params.Introspect,
+			params.AllowNulls,
+			params.Flat,
 		)
 		if err != nil {
-			return err
+			return xo.Query{}, err
+		}
+		if params.Fields == "" {
+			if err := overrideNulls(typeFields, params.Nullable, true); err != nil {
+				return xo.Query{}, err
+			}
+			if err := overrideNulls(typeFields, params.NotNull, false); err != nil {
+				return xo.Query{}, err
+			}
 		}
 	}
-	set.Queries = append(set.Queries, xo.Query{
+	return xo.Query{
 		Driver:       driver,
-		Name:         args.QueryParams.Func,
-		Comment:      args.QueryParams.FuncComment,
-		Exec:         args.QueryParams.Exec,
-		Flat:         args.QueryParams.Flat,
-		One:          args.QueryParams.One,
-		Interpolate:  args.QueryParams.Interpolate,
-		Type:         args.QueryParams.Type,
-		TypeComment:  args.QueryParams.TypeComment,
+		Name:         params.Func,
+		Comment:      params.FuncComment,
+		Exec:         params.Exec,
+		Flat:         params.Flat,
+		One:          params.One,
+		Interpolate:  params.Interpolate,
+		Type:         params.Type,
+		TypeComment:  params.TypeComment,
 		Fields:       typeFields,
-		ManualFields: args.QueryParams.Fields != "",
+		ManualFields: params.Fields != "",
 		Params:       fields,
 		Query:        query,
 		Comments:     comments,
-	})
-	return nil
+		File:         file,
+	}, nil
+}
+
+// queryFiles returns the .sql files in dir, or dir when it is a file.
+func queryFiles(dir string) ([]string, error) {
+	fi, err := os.Stat(dir)
+	switch {
+	case err != nil:
+		return nil, err
+	case !fi.IsDir():
+		return []string{dir}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	switch {
+	case err != nil:
+		return nil, err
+	case len(files) == 0:
+		return nil, fmt.Errorf("no .sql files in %s", dir)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// queryNameRE matches a query name annotation.
+var queryNameRE = regexp.MustCompile(`^--\s*name:\s*(\S+)(?:\s+:(\S+))?\s*$`)
+
+// queryAnnotationRE matches a query type, fields, or column nullability
+// annotation.
+var queryAnnotationRE = regexp.MustCompile(`^--\s*(type|fields|nullable|notnull):\s*(.*?)\s*$`)
+
+// ParseQueryFile parses the annotated queries in a .sql file, using params
+// as the defaults for each query.
+//
+// Each query starts with a name annotation, optionally followed by type,
+// fields, and column nullability annotations, and any comments describing the
+// query:
+//
+//	-- name: AuthorsByName :many
+//	-- type: Author
+//	-- nullable: bio
+//	-- AuthorsByName retrieves authors by name.
+//	SELECT author_id, name, bio FROM authors WHERE name = %%name string%%;
+//
+// The query kind is one of :many (default), :one, or :exec. When not
+// annotated, the type name is the query name with a "Row" suffix. The
+// nullable and notnull annotations override the introspected nullability of
+// the listed result columns.
+func ParseQueryFile(name string, params QueryParams) ([]QueryParams, error) {
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var queries []QueryParams
+	var q *QueryParams
+	var body []string
+	// add adds the accumulated query
+	add := func() {
+		if q == nil {
+			return
+		}
+		q.Query = strings.TrimRight(strings.TrimSpace(strings.Join(body, "\n")), ";")
+		queries = append(queries, *q)
+	}
+	for i, line := range strings.Split(strings.ReplaceAll(string(buf), "\r\n", "\n"), "\n") {
+		s := strings.TrimSpace(line)
+		if m := queryNameRE.FindStringSubmatch(s); m != nil {
+			add()
+			if !isGoIdent(m[1]) {
+				return nil, fmt.Errorf("%s:%d: invalid query name %q", name, i+1, m[1])
+			}
+			q, body = new(QueryParams), nil
+			*q = params
+			q.Func = m[1]
+			switch m[2] {
+			case "", "many":
+			case "one":
+				q.One = true
+			case "exec":
+				q.Exec = true
+			default:
+				return nil, fmt.Errorf("%s:%d: unknown query kind %q (expected :many, :one, or :exec)", name, i+1, ":"+m[2])
+			}
+			if !q.Exec {
+				q.Type = q.Func + "Row"
+			}
+			continue
+		}
+		switch {
+		case q == nil && s != "" && !strings.HasPrefix(s, "--"):
+			return nil, fmt.Errorf("%s:%d: query without name annotation", name, i+1)
+		case q == nil:
+		case len(body) == 0 && strings.HasPrefix(s, "--"):
+			// header annotations and comments
+			if m := queryAnnotationRE.FindStringSubmatch(s); m != nil {
+				switch m[1] {
+				case "type":
+					if !isGoIdent(m[2]) {
+						return nil, fmt.Errorf("%s:%d: invalid type name %q", name, i+1, m[2])
+					}
+					q.Type = m[2]
+				case "fields":
+					q.Fields = m[2]
+				case "nullable":
+					q.Nullable = append(q.Nullable, splitNames(m[2])...)
+				case "notnull":
+					q.NotNull = append(q.NotNull, splitNames(m[2])...)
+				}
+				continue
+			}
+			comment := strings.TrimSpace(strings.TrimPrefix(s, "--"))
+			if q.FuncComment != "" {
+				comment = q.FuncComment + "\n" + comment
+			}
+			q.FuncComment = comment
+		case len(body) == 0 && s == "":
+		default:
+			body = append(body, line)
+		}
+	}
+	add()
+	// check queries
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("%s: no queries", name)
+	}
+	for _, q := range queries {
+		if q.Query == "" {
+			return nil, fmt.Errorf("%s: %s: empty query", name, q.Func)
+		}
+	}
+	return queries, nil
+}
+
+// isGoIdent returns whether s is a valid Go identifier.
+func isGoIdent(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
 }
 
 // ParseQuery parses a query returning the processed query, a query for
@@ -121,7 +314,8 @@ func ParseQuery(ctx context.Context, sqlstr, delimiter string, interpolate, trim
 
 // ParseQueryFields takes a SQL query and looks for strings in the form of
 // "<delim><name> <type>[,<option>,...]<delim>", replacing them with the nth
-// param value.
+// param value. Params without a type ("<delim><name><delim>") have an empty
+// type, to be inferred by InferParams.
 //
 // The modified query is returned, along with any extracted parameters.
 func ParseQueryFields(query, delim string, interpolate, paramInterpolate bool, nth func(int) string) (string, []xo.Field, error) {
@@ -139,8 +333,7 @@ func ParseQueryFields(query, delim string, interpolate, paramInterpolate bool, n
 	for _, m := range matches {
 		// extract parameter info
 		paramStr := query[m[0]+len(delim) : m[1]-len(delim)]
-		p := strings.SplitN(paramStr, " ", 2)
-		name, typ := p[0], p[1]
+		name, typ, _ := strings.Cut(paramStr, " ")
 		field := xo.Field{
 			Name: name,
 			Type: xo.Type{
@@ -196,27 +389,128 @@ func ParseQueryFields(query, delim string, interpolate, paramInterpolate bool, n
 	return sqlstr + query[last:], fields, nil
 }
 
+// InferParams infers the types of params declared without a type by preparing
+// the query, or from the columns the params are compared with, assigned to, or
+// inserted into, setting the database type of each. The types are converted to
+// Go types by the templates, the same as with introspected columns.
+func InferParams(ctx context.Context, sqlstr, delimiter string, interpolate bool, fields []xo.Field) error {
+	var infer bool
+	for _, field := range fields {
+		infer = infer || field.Type.Type == ""
+	}
+	if !infer {
+		return nil
+	}
+	driver, db, _ := xo.DriverDbSchema(ctx)
+	if db == nil {
+		return errors.New("query param type inference requires a database")
+	}
+	nth, err := loader.NthParam(ctx)
+	if err != nil {
+		return err
+	}
+	// build query with a placeholder per param, including interpolated params
+	query, _, err := ParseQueryFields(sqlstr, delimiter, interpolate, false, nth)
+	if err != nil {
+		return err
+	}
+	typs, err := loader.QueryParamTypes(ctx, query)
+	if err != nil {
+		return err
+	}
+	for i, field := range fields {
+		switch {
+		case field.Type.Type != "":
+			continue
+		case len(typs) <= i || typs[i] == "":
+			return fmt.Errorf("unable to infer type of query param %q", field.Name)
+		}
+		d, err := xo.ParseType(typs[i], driver)
+		if err != nil {
+			return err
+		}
+		fields[i].Type, fields[i].Inferred = d, true
+	}
+	return nil
+}
+
 // LoadQueryFields loads the query type fields.
-func LoadQueryFields(ctx context.Context, query []string, fields string, allowNulls, flat bool) ([]xo.Field, error) {
+func LoadQueryFields(ctx context.Context, query []string, fields, strategy string, allowNulls, flat bool) ([]xo.Field, error) {
 	// introspect or use defined user fields
 	f := Introspect
 	if fields != "" {
 		// wrap ...
-		f = func(context.Context, []string, bool, bool) ([]xo.Field, error) {
+		f = func(context.Context, []string, string, bool, bool) ([]xo.Field, error) {
 			return SplitFields(fields)
 		}
 	}
-	return f(ctx, query, allowNulls, flat)
+	return f(ctx, query, strategy, allowNulls, flat)
 }
 
-// Introspect creates a view of a query, introspecting the query's columns and
-// returning as fields.
+// Introspect introspects the query's columns, returning as fields. When
+// allowing nulls, columns are nullable unless reported as not null by the
+// database, or traced to a table column that is not null.
 //
-// Creates a temporary view/table, retrieves its column definitions and
-// dropping the temporary view/table.
-func Introspect(ctx context.Context, query []string, allowNulls, flat bool) ([]xo.Field, error) {
+// The prepare strategy prepares the query, reading the result columns without
+// creating any database objects. The view strategy creates a temporary
+// view/table of the query, retrieving its column definitions and dropping the
+// temporary view/table. The auto strategy uses the prepare strategy when
+// supported by the database, falling back to the view strategy.
+func Introspect(ctx context.Context, query []string, strategy string, allowNulls, flat bool) ([]xo.Field, error) {
+	driver, db, _ := xo.DriverDbSchema(ctx)
+	if db == nil {
+		return nil, errors.New("query introspection requires a database (use --exec or --fields)")
+	}
+	var cols []*models.Column
+	var err error
+	switch strategy {
+	case "prepare":
+		cols, err = loader.QueryColumns(ctx, query)
+	case "view":
+		cols, err = introspectView(ctx, driver, query)
+	default:
+		if cols, err = loader.QueryColumns(ctx, query); err != nil {
+			prepareErr := err
+			if cols, err = introspectView(ctx, driver, query); err != nil {
+				return nil, fmt.Errorf("unable to prepare query (%v), or to create view: %w", prepareErr, err)
+			}
+			if verbose, _ := ctx.Value(verboseKey).(bool); verbose {
+				fmt.Fprintf(os.Stderr, "WARNING: unable to prepare query, introspecting a view instead (%v)\n", prepareErr)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// infer nullability of columns not reported as not null
+	var notNull []bool
+	if allowNulls {
+		if notNull, err = loader.QueryNotNull(ctx, query); err != nil || len(notNull) != len(cols) {
+			notNull = make([]bool, len(cols))
+		}
+	}
+	// process columns
+	var fields []xo.Field
+	for i, col := range cols {
+		// get type
+		d, err := xo.ParseType(col.DataType, driver)
+		if err != nil {
+			return nil, err
+		}
+		if allowNulls {
+			d.Nullable = !col.NotNull && !notNull[i]
+		}
+		fields = append(fields, xo.Field{
+			Name: col.ColumnName,
+			Type: d,
+		})
+	}
+	return fields, nil
+}
+
+// introspectView creates a view of a query, returning the view's columns.
+func introspectView(ctx context.Context, driver string, query []string) ([]*models.Column, error) {
 	// determine prefix
-	driver, _, _ := xo.DriverDbSchema(ctx)
 	prefix := "_xo_"
 	if driver == "oracle" {
 		prefix = "XO$"
@@ -254,28 +548,35 @@ func Introspect(ctx context.Context, query []string, allowNulls, flat bool) ([]x
 	if _, err := loader.ViewDrop(ctx, id); err != nil {
 		return nil, err
 	}
-	// process columns
-	var fields []xo.Field
-	for _, col := range cols {
-		// get type
-		d, err := xo.ParseType(col.DataType, driver)
-		if err != nil {
-			return nil, err
-		}
-		if allowNulls {
-			d.Nullable = !col.NotNull
-		}
-		fields = append(fields, xo.Field{
-			Name: col.ColumnName,
-			Type: d,
-		})
-	}
-	return fields, nil
+	return cols, nil
 }
 
 // letters are used for random IDs.
 const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
 
+// overrideNulls sets the nullability of the named fields.
+func overrideNulls(fields []xo.Field, names []string, nullable bool) error {
+	for _, name := range names {
+		i := index(fields, name)
+		if i == -1 {
+			return fmt.Errorf("unknown result column %q in nullability annotation", name)
+		}
+		fields[i].Type.Nullable = nullable
+	}
+	return nil
+}
+
+// splitNames splits s (comma separated) into names.
+func splitNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // SplitFields splits s (comma separated) into fields.
 func SplitFields(s string) ([]xo.Field, error) {
 	var fields []xo.Field
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xo/xo/loader"
+	"github.com/xo/xo/models"
+	xo "github.com/xo/xo/types"
+)
+
+func TestParseQueryFile(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp []QueryParams
+		err string
+	}{
+		{`-- leading comments are ignored
+
+-- name: AuthorsByName :many
+-- AuthorsByName retrieves authors by name.
+SELECT author_id, name
+FROM authors
+WHERE name = %%name string%%;
+
+-- name: AuthorByID :one
+-- type: Author
+-- fields: AuthorID int, Name string
+SELECT author_id, name FROM authors WHERE author_id = %%authorID int%%;
+
+-- name: DeleteAuthor :exec
+-- DeleteAuthor deletes an author.
+-- Returns no rows.
+DELETE FROM authors WHERE author_id = %%authorID int%%
+
+-- name: Authors
+SELECT author_id FROM authors
+`, []QueryParams{
+			{Func: "AuthorsByName", Type: "AuthorsByNameRow", FuncComment: "AuthorsByName retrieves authors by name.", Query: "SELECT author_id, name\nFROM authors\nWHERE name = %%name string%%"},
+			{Func: "AuthorByID", Type: "Author", One: true, Fields: "AuthorID int, Name string", Query: "SELECT author_id, name FROM authors WHERE author_id = %%authorID int%%"},
+			{Func: "DeleteAuthor", Exec: true, FuncComment: "DeleteAuthor deletes an author.\nReturns no rows.", Query: "DELETE FROM authors WHERE author_id = %%authorID int%%"},
+			{Func: "Authors", Type: "AuthorsRow", Query: "SELECT author_id FROM authors"},
+		}, ""},
+		{"SELECT 1;\n", nil, "q.sql:1: query without name annotation"},
+		{"-- name: 1Authors\nSELECT 1;\n", nil, `q.sql:1: invalid query name "1Authors"`},
+		{"-- name: Authors :all\nSELECT 1;\n", nil, `q.sql:1: unknown query kind ":all" (expected :many, :one, or :exec)`},
+		{"-- name: Authors\n-- type: Author Row\nSELECT 1;\n", nil, `q.sql:2: invalid type name "Author Row"`},
+		{"-- name: Authors\n\n-- name: Books\nSELECT 1;\n", nil, "q.sql: Authors: empty query"},
+		{"-- nothing here\n", nil, "q.sql: no queries"},
+	}
+	dir := t.TempDir()
+	for i, test := range tests {
+		name := filepath.Join(dir, "q.sql")
+		if err := os.WriteFile(name, []byte(test.s), 0o644); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		queries, err := ParseQueryFile(name, QueryParams{})
+		switch {
+		case test.err != "" && err == nil:
+			t.Errorf("test %d expected error %q", i, test.err)
+			continue
+		case test.err != "" && err.Error() != filepath.Join(dir, test.err):
+			t.Errorf("test %d expected error %q, got: %v", i, filepath.Join(dir, test.err), err)
+			continue
+		case test.err != "":
+			continue
+		case err != nil:
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if !reflect.DeepEqual(queries, test.exp) {
+			t.Errorf("test %d expected:\n%+v\ngot:\n%+v", i, test.exp, queries)
+		}
+	}
+}
+
+func TestLoadQueryFiles(t *testing.T) {
+	db := sqliteDB(t, `CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL
+);
+CREATE TABLE books (
+  book_id INTEGER PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  title TEXT NOT NULL
+);
+`)
+	dir := t.TempDir()
+	files := map[string]string{
+		"authors.sql": `-- name: AuthorsByName :many
+SELECT author_id, name FROM authors WHERE name = %%name string%%;
+
+-- name: DeleteAuthor :exec
+DELETE FROM authors WHERE author_id = %%authorID int%%;
+`,
+		"inferred.sql": `-- name: AuthorByID :one
+SELECT author_id, name FROM authors WHERE author_id = %%authorID%%;
+`,
+		"books.sql": `-- name: BookByID :one
+-- type: BookTitle
+SELECT title FROM books WHERE book_id = %%bookID int%%;
+`,
+		"README.md": "not a query file",
+	}
+	for name, s := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(s), 0o644); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	if err := os.Mkdir(out, 0o755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := Run(context.Background(), "xo", "0.0.0-dev", "query", "file:"+db, "--dir", dir, "--out", out); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		file string
+		exp  []string
+	}{
+		{"authors.sql.xo.go", []string{
+			"type AuthorsByNameRow struct {",
+			"func AuthorsByName(ctx context.Context, db DB, name string) ([]*AuthorsByNameRow, error) {",
+			"func DeleteAuthor(ctx context.Context, db DB, authorID int) (sql.Result, error) {",
+		}},
+		{"inferred.sql.xo.go", []string{
+			"func AuthorByID(ctx context.Context, db DB, authorID int) (*AuthorByIDRow, error) {",
+		}},
+		{"books.sql.xo.go", []string{
+			"type BookTitle struct {",
+			"func BookByID(ctx context.Context, db DB, bookID int) (*BookTitle, error) {",
+		}},
+	}
+	for _, test := range tests {
+		buf, err := os.ReadFile(filepath.Join(out, test.file))
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		for _, exp := range test.exp {
+			if !strings.Contains(string(buf), exp) {
+				t.Errorf("expected %s to contain:\n%s", test.file, exp)
+			}
+		}
+	}
+}
+
+func TestParseQueryFieldsUntyped(t *testing.T) {
+	nth := func(i int) string {
+		return "$" + strconv.Itoa(i+1)
+	}
+	query, fields, err := ParseQueryFields("SELECT * FROM authors WHERE author_id = %%authorID%% AND name = %%name string%%", "%%", false, false, nth)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := "SELECT * FROM authors WHERE author_id = $1 AND name = $2"; query != exp {
+		t.Errorf("expected %q, got: %q", exp, query)
+	}
+	var v []string
+	for _, f := range fields {
+		v = append(v, f.Name+":"+f.Type.Type)
+	}
+	if s, exp := strings.Join(v, " "), "authorID: name:string"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}
+
+func TestInferParams(t *testing.T) {
+	db, err := sql.Open("sqlite3", sqliteDB(t, "CREATE TABLE authors (author_id INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL);"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	const sqlstr = "SELECT * FROM authors WHERE author_id = %%authorID%% AND name = %%name string%% AND kind = %%kind%%"
+	tests := []struct {
+		typs []string
+		exp  string
+		err  string
+	}{
+		{[]string{"integer", "text", "book_type"}, "authorID:integer name:string kind:book_type", ""},
+		{[]string{"bigint"}, "", `unable to infer type of query param "kind"`},
+		{nil, "", `unable to infer type of query param "authorID"`},
+	}
+	for i, test := range tests {
+		var query string
+		ctx := context.WithValue(context.Background(), xo.DriverKey, "postgres")
+		ctx = context.WithValue(ctx, xo.DbKey, db)
+		ctx = loader.WithLoader(ctx, loader.Loader{
+			Mask: "$%d",
+			QueryParamTypes: func(_ context.Context, _ *sql.DB, s string) ([]string, error) {
+				query = s
+				return test.typs, nil
+			},
+		})
+		_, fields, err := ParseQueryFields(sqlstr, "%%", false, false, func(int) string { return "?" })
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		err = InferParams(ctx, sqlstr, "%%", false, fields)
+		switch {
+		case test.err != "" && err == nil:
+			t.Errorf("test %d expected error %q", i, test.err)
+			continue
+		case test.err != "" && err.Error() != test.err:
+			t.Errorf("test %d expected error %q, got: %v", i, test.err, err)
+			continue
+		case test.err != "":
+			continue
+		case err != nil:
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if exp := "SELECT * FROM authors WHERE author_id = $1 AND name = $2 AND kind = $3"; query != exp {
+			t.Errorf("test %d expected query %q, got: %q", i, exp, query)
+		}
+		var v []string
+		for _, f := range fields {
+			if f.Inferred != (f.Name != "name") {
+				t.Errorf("test %d expected %s inferred to be %t", i, f.Name, !f.Inferred)
+			}
+			v = append(v, f.Name+":"+f.Type.Type)
+		}
+		if s := strings.Join(v, " "); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	// typed params do not need a database
+	fields := []xo.Field{{Name: "authorID", Type: xo.Type{Type: "int"}}}
+	if err := InferParams(context.Background(), "SELECT %%authorID int%%", "%%", false, fields); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	// untyped params need a database supporting inference
+	fields = []xo.Field{{Name: "authorID"}}
+	ctx := context.WithValue(context.Background(), xo.DriverKey, "sqlite3")
+	if err, exp := InferParams(ctx, "SELECT %%authorID%%", "%%", false, fields), "query param type inference requires a database"; err == nil || err.Error() != exp {
+		t.Errorf("expected error %q, got: %v", exp, err)
+	}
+	// sqlite infers types from the schema
+	ctx = context.WithValue(ctx, xo.DbKey, db)
+	ctx = context.WithValue(ctx, xo.SchemaKey, "main")
+	fields = []xo.Field{{Name: "authorID"}, {Name: "name"}, {Name: "n"}}
+	if err := InferParams(ctx, "SELECT * FROM authors WHERE author_id IN (%%authorID%%, 1) AND name LIKE %%name%% LIMIT %%n%%", "%%", false, fields); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var v []string
+	for _, f := range fields {
+		v = append(v, f.Name+":"+f.Type.Type)
+	}
+	if s, exp := strings.Join(v, " "), "authorID:integer name:text n:integer"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	fields = []xo.Field{{Name: "authorID"}}
+	if err, exp := InferParams(ctx, "SELECT %%authorID%%", "%%", false, fields), `unable to infer type of query param "authorID"`; err == nil || err.Error() != exp {
+		t.Errorf("expected error %q, got: %v", exp, err)
+	}
+}
+
+func TestIntrospectFallback(t *testing.T) {
+	db, err := sql.Open("sqlite3", sqliteDB(t, "CREATE TABLE authors (author_id INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL);"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	l, ok := loader.Lookup("sqlite3")
+	if !ok {
+		t.Fatalf("expected sqlite3 loader")
+	}
+	l.QueryColumns = func(context.Context, *sql.DB, string) ([]*models.Column, error) {
+		return nil, errors.New("prepare failed")
+	}
+	ctx := context.WithValue(context.Background(), xo.DriverKey, "sqlite3")
+	ctx = context.WithValue(ctx, xo.DbKey, db)
+	ctx = context.WithValue(ctx, xo.SchemaKey, "main")
+	// auto falls back to a view
+	fields, err := Introspect(loader.WithLoader(ctx, l), []string{"SELECT author_id, name FROM authors"}, "auto", false, false)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Name != "author_id" || fields[1].Name != "name" {
+		t.Errorf("expected author_id and name fields, got: %v", fields)
+	}
+	// both errors are reported when the view cannot be created
+	l.ViewCreate = func(context.Context, models.DB, string, string, []string) (sql.Result, error) {
+		return nil, errors.New("view failed")
+	}
+	_, err = Introspect(loader.WithLoader(ctx, l), []string{"SELECT author_id, name FROM authors"}, "auto", false, false)
+	if exp := "unable to prepare query (prepare failed), or to create view: view failed"; err == nil || err.Error() != exp {
+		t.Errorf("expected error %q, got: %v", exp, err)
+	}
+	// prepare reports its error
+	_, err = Introspect(loader.WithLoader(ctx, l), []string{"SELECT author_id, name FROM authors"}, "prepare", false, false)
+	if exp := "prepare failed"; err == nil || err.Error() != exp {
+		t.Errorf("expected error %q, got: %v", exp, err)
+	}
+}
@@ -8,47 +8,182 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/gobwas/glob"
 	"github.com/kenshaw/inflector"
 	"github.com/xo/xo/loader"
 	"github.com/xo/xo/models"
 	xo "github.com/xo/xo/types"
 )
 
-// LoadSchema loads a schema from a database.
+// schemasKey is the context key for the names of the schemas to load.
+const schemasKey xo.ContextKey = "schemas"
+
+// LoadSchema loads the schemas from a database, or from the --cache dir when
+// the database schemas are unchanged.
 func LoadSchema(ctx context.Context, set *xo.Set, args *Args) error {
+	file, err := cacheFile(ctx, args)
+	if err != nil {
+		return err
+	}
+	if file != "" {
+		if cached, err := LoadSet(file, args); err == nil {
+			set.Schemas = append(set.Schemas, cached.Schemas...)
+			return nil
+		}
+	}
+	var schemas []xo.Schema
+	for _, name := range schemaNamesFrom(ctx) {
+		ctx := context.WithValue(ctx, xo.SchemaKey, name)
+		schema, err := loadSchema(ctx, args)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, schema)
+	}
+	if file != "" {
+		if err := writeCache(file, schemas); err != nil {
+			return err
+		}
+	}
+	set.Schemas = append(set.Schemas, schemas...)
+	return nil
+}
+
+// loadSchema loads the schema of the context.
+func loadSchema(ctx context.Context, args *Args) (xo.Schema, error) {
 	driver, _, schemaName := xo.DriverDbSchema(ctx)
 	schema := xo.Schema{
 		Driver: driver,
 		Name:   schemaName,
 	}
 	var err error
-	// load enums, procs, tables, views
+	// load enums, domains, composites, procs, tables, views
 	if schema.Enums, err = LoadEnums(ctx, args); err != nil {
-		return err
+		return xo.Schema{}, err
+	}
+	if schema.Domains, err = LoadDomains(ctx, args); err != nil {
+		return xo.Schema{}, err
+	}
+	if schema.Composites, err = LoadComposites(ctx, args); err != nil {
+		return xo.Schema{}, err
 	}
 	if schema.Procs, err = LoadProcs(ctx, args); err != nil {
-		return err
+		return xo.Schema{}, err
 	}
 	if schema.Tables, err = LoadTables(ctx, args, "table"); err != nil {
-		return err
+		return xo.Schema{}, err
 	}
 	if schema.Views, err = LoadTables(ctx, args, "view"); err != nil {
-		return err
+		return xo.Schema{}, err
 	}
 	// fix enums for mysql
-	if driver == "mysql" {
-		for i := 0; i < len(schema.Tables); i++ {
-			for j := 0; j < len(schema.Tables[i].Columns); j++ {
-				if e := schema.EnumByName(schema.Tables[i].Columns[j].Type.Type); e != nil {
-					schema.Tables[i].Columns[j].Type.Enum = e
+	linkEnums(&schema)
+	return schema, nil
+}
+
+// schemaNames resolves the schema names for the --schema value, a comma
+// separated list of schema names or globs. The active schema is used when not
+// specified.
+func schemaNames(ctx context.Context, schema string) ([]string, error) {
+	if schema == "" {
+		name, err := loader.Schema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+	var all []*models.Schema
+	var names []string
+	seen := make(map[string]bool)
+	for _, pattern := range splitSchemas(schema) {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[{") {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema glob %q: %w", pattern, err)
+			}
+			if all == nil {
+				if all, err = loader.Schemas(ctx); err != nil {
+					return nil, err
+				}
+			}
+			matches = nil
+			for _, s := range all {
+				if g.Match(s.SchemaName) {
+					matches = append(matches, s.SchemaName)
 				}
 			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no schemas match %q", pattern)
+			}
+		}
+		for _, name := range matches {
+			if !seen[name] {
+				names = append(names, name)
+			}
+			seen[name] = true
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("invalid schema %q", schema)
+	}
+	return names, nil
+}
+
+// schemaNamesFrom returns the names of the schemas to load from the context.
+func schemaNamesFrom(ctx context.Context) []string {
+	if names, ok := ctx.Value(schemasKey).([]string); ok {
+		return names
+	}
+	_, _, schema := xo.DriverDbSchema(ctx)
+	return []string{schema}
+}
+
+// splitSchemas splits a comma separated list of schema names or globs,
+// ignoring commas inside glob braces (ie, "{a,b}").
+func splitSchemas(schema string) []string {
+	var v []string
+	depth, start := 0, 0
+	for i, c := range schema {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			v, start = append(v, schema[start:i]), i+1
+		}
+	}
+	return append(v, schema[start:])
+}
+
+// contains returns true when v contains s.
+func contains(v []string, s string) bool {
+	for _, z := range v {
+		if z == s {
+			return true
+		}
+	}
+	return false
+}
+
+// linkEnums links mysql column types to their enum.
+func linkEnums(schema *xo.Schema) {
+	if schema.Driver != "mysql" {
+		return
+	}
+	for i := 0; i < len(schema.Tables); i++ {
+		for j := 0; j < len(schema.Tables[i].Columns); j++ {
+			if e := schema.EnumByName(schema.Tables[i].Columns[j].Type.Type); e != nil {
+				schema.Tables[i].Columns[j].Type.Enum = e
+			}
 		}
 	}
-	// emit
-	set.Schemas = append(set.Schemas, schema)
-	return nil
 }
 
 // LoadEnums loads enums.
@@ -95,6 +230,89 @@ func LoadEnumValues(ctx context.Context, args *Args, enum *xo.Enum) error {
 	return nil
 }
 
+// LoadDomains loads domains.
+func LoadDomains(ctx context.Context, args *Args) ([]xo.Domain, error) {
+	driver, _, _ := xo.DriverDbSchema(ctx)
+	domains, err := loader.Domains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].DomainName < domains[j].DomainName
+	})
+	var m []xo.Domain
+	for _, domain := range domains {
+		if !validType(args, false, domain.DomainName) {
+			continue
+		}
+		d, err := xo.ParseType(domain.DataType, driver)
+		if err != nil {
+			return nil, err
+		}
+		d.Nullable = !domain.NotNull
+		checks, err := loader.DomainChecks(ctx, domain.DomainName)
+		if err != nil {
+			return nil, err
+		}
+		var c []xo.Check
+		for _, check := range checks {
+			c = append(c, xo.Check{
+				Name: check.CheckName,
+				Expr: strings.TrimSpace(check.CheckExpr),
+			})
+		}
+		m = append(m, xo.Domain{
+			Name:    domain.DomainName,
+			Type:    d,
+			Default: domain.DefaultValue,
+			Checks:  c,
+			Comment: domain.Comment,
+		})
+	}
+	return m, nil
+}
+
+// LoadComposites loads composite types.
+func LoadComposites(ctx context.Context, args *Args) ([]xo.Composite, error) {
+	driver, _, _ := xo.DriverDbSchema(ctx)
+	composites, err := loader.Composites(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(composites, func(i, j int) bool {
+		return composites[i].CompositeName < composites[j].CompositeName
+	})
+	var m []xo.Composite
+	for _, composite := range composites {
+		if !validType(args, false, composite.CompositeName) {
+			continue
+		}
+		attrs, err := loader.CompositeAttrs(ctx, composite.CompositeName)
+		if err != nil {
+			return nil, err
+		}
+		c := xo.Composite{
+			Name:    composite.CompositeName,
+			Comment: composite.Comment,
+		}
+		for _, attr := range attrs {
+			d, err := xo.ParseType(attr.DataType, driver)
+			if err != nil {
+				return nil, err
+			}
+			// composite attributes are always nullable
+			d.Nullable = true
+			c.Attributes = append(c.Attributes, xo.Field{
+				Name:    attr.ColumnName,
+				Type:    d,
+				Comment: attr.Comment.String,
+			})
+		}
+		m = append(m, c)
+	}
+	return m, nil
+}
+
 // LoadProcs loads stored procedures definitions.
 func LoadProcs(ctx context.Context, args *Args) ([]xo.Proc, error) {
 	driver, _, _ := xo.DriverDbSchema(ctx)
@@ -198,37 +416,112 @@ func LoadTables(ctx context.Context, args *Args, typ string) ([]xo.Table, error)
 		if !validType(args, false, table.TableName) {
 			continue
 		}
+		comment, annotations, err := parseAnnotations(table.Comment)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("%s %q: %w", typ, table.TableName, err)
+		case annotations.skip():
+			continue
+		}
 		// create table
 		t := &xo.Table{
-			Type:       typ,
-			Name:       table.TableName,
-			Manual:     true,
-			Definition: strings.TrimSpace(table.ViewDef),
+			Type:        tableType(typ, table.Type),
+			Name:        table.TableName,
+			Manual:      true,
+			Definition:  strings.TrimSpace(table.ViewDef),
+			Comment:     comment,
+			Annotations: annotations,
 		}
 		// fix multi-line comments
 		if t.Definition != "" {
 			t.Definition = strings.Replace(t.Definition, "\n", " ", -1)
 		}
-
+		m = append(m, *t)
+	}
+	// load columns, indexes, and check constraints
+	jobs := loadJobs(ctx, args)
+	if err := parallel(jobs, len(m), func(i int) error {
 		// process columns
-		if err := LoadColumns(ctx, args, t); err != nil {
-			return nil, err
+		if err := LoadColumns(ctx, args, &m[i]); err != nil {
+			return err
 		}
 		// load indexes
-		if err := LoadTableIndexes(ctx, args, t); err != nil {
-			return nil, err
+		if err := LoadTableIndexes(ctx, args, &m[i]); err != nil {
+			return err
 		}
-		m = append(m, *t)
+		// load check constraints
+		if typ == "table" {
+			if err := LoadTableChecks(ctx, args, &m[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 	// load foreign keys
-	for i, table := range m {
-		if m[i].ForeignKeys, err = LoadTableForeignKeys(ctx, args, m, table); err != nil {
-			return nil, err
-		}
+	fkeys := make([][]xo.ForeignKey, len(m))
+	if err := parallel(jobs, len(m), func(i int) error {
+		var err error
+		fkeys[i], err = LoadTableForeignKeys(ctx, args, m, m[i])
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	for i := range m {
+		m[i].ForeignKeys = fkeys[i]
 	}
 	return m, nil
 }
 
+// loadJobs returns the number of tables to load concurrently. Tables are
+// loaded one at a time when not loading from a database (ie, from DDL files),
+// as the DDL catalog is not synchronized.
+func loadJobs(ctx context.Context, args *Args) int {
+	if _, db, _ := xo.DriverDbSchema(ctx); db == nil {
+		return 1
+	}
+	return args.SchemaParams.Jobs
+}
+
+// parallel calls f for each of n items, running up to jobs calls
+// concurrently. The error of the first failed item is returned.
+func parallel(jobs, n int, f func(int) error) error {
+	if jobs < 1 {
+		jobs = 1
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = f(i)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableType returns the table's type as reported by the loader, falling back
+// to typ ('table' or 'view').
+func tableType(typ, kind string) string {
+	if kind = strings.ToLower(strings.TrimSpace(kind)); kind != "" {
+		return kind
+	}
+	return typ
+}
+
 // LoadColumns loads table/view columns.
 func LoadColumns(ctx context.Context, args *Args, table *xo.Table) error {
 	driver, _, _ := xo.DriverDbSchema(ctx)
@@ -252,6 +545,13 @@ func LoadColumns(ctx context.Context, args *Args, table *xo.Table) error {
 		if !validType(args, true, table.Name, c.ColumnName) {
 			continue
 		}
+		comment, annotations, err := parseAnnotations(c.Comment.String)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%s %q column %q: %w", table.Type, table.Name, c.ColumnName, err)
+		case annotations.skip():
+			continue
+		}
 		// set col info
 		d, err := xo.ParseType(c.DataType, driver)
 		if err != nil {
@@ -263,16 +563,14 @@ func LoadColumns(ctx context.Context, args *Args, table *xo.Table) error {
 			defaultValue = ""
 		}
 		col := xo.Field{
-			Name:       c.ColumnName,
-			Type:       d,
-			Default:    defaultValue,
-			IsPrimary:  c.IsPrimaryKey,
-			IsSequence: sqMap[c.ColumnName],
-			Comment:    strings.TrimSpace(c.Comment.String),
-		}
-		// fix multi-line comments
-		if col.Comment != "" {
-			col.Comment = strings.Replace(col.Comment, "\n", " ", -1)
+			Name:        c.ColumnName,
+			Type:        d,
+			Default:     defaultValue,
+			IsPrimary:   c.IsPrimaryKey,
+			IsSequence:  sqMap[c.ColumnName],
+			IsGenerated: c.IsGenerated,
+			Comment:     comment,
+			Annotations: annotations,
 		}
 
 		table.Columns = append(table.Columns, col)
@@ -301,15 +599,19 @@ func LoadTableIndexes(ctx context.Context, args *Args, table *xo.Table) error {
 		// create index template
 		index := &xo.Index{
 			Name:      index.IndexName,
+			Predicate: trimParens(index.Predicate),
 			IsPrimary: index.IsPrimary,
 			IsUnique:  index.IsUnique,
 		}
 		// load index columns
-		if err := LoadIndexColumns(ctx, args, table, index); err != nil {
+		switch ok, err := LoadIndexColumns(ctx, args, table, index); {
+		case err != nil:
 			return err
+		case !ok:
+			continue
 		}
 		// load index func name
-		index.Func = indexFuncName(*index, table.Name, args.SchemaParams.UseIndexNames)
+		index.Func = indexFuncName(*index, funcTableName(*table), args.SchemaParams.UseIndexNames)
 		table.Indexes = append(table.Indexes, *index)
 	}
 	pkeys := table.PrimaryKeys
@@ -330,7 +632,7 @@ func LoadTableIndexes(ctx context.Context, args *Args, table *xo.Table) error {
 			IsUnique:  true,
 			IsPrimary: true,
 		}
-		index.Func = indexFuncName(index, table.Name, args.SchemaParams.UseIndexNames)
+		index.Func = indexFuncName(index, funcTableName(*table), args.SchemaParams.UseIndexNames)
 		table.Indexes = append(table.Indexes, index)
 	} else if driver == "oracle" && len(table.PrimaryKeys) != 0 {
 	loop:
@@ -347,40 +649,174 @@ func LoadTableIndexes(ctx context.Context, args *Args, table *xo.Table) error {
 	return nil
 }
 
-// LoadIndexColumns loads the index column information.
-func LoadIndexColumns(ctx context.Context, args *Args, table *xo.Table, index *xo.Index) error {
+// LoadIndexColumns loads the index column information, returning false when
+// the index is skipped because one of its columns was not loaded.
+//
+// The field of an expression is the column the expression references, or an
+// empty field when it does not reference exactly one column.
+func LoadIndexColumns(ctx context.Context, args *Args, table *xo.Table, index *xo.Index) (bool, error) {
 	// load index columns
 	cols, err := loader.IndexColumns(ctx, table.Name, index.Name)
 	if err != nil {
-		return err
+		return false, err
+	}
+	skip := func(format string, v ...interface{}) (bool, error) {
+		fmt.Fprintf(os.Stderr, "WARNING: skipping table %q index %q (%s)\n", table.Name, index.Name, fmt.Sprintf(format, v...))
+		return false, nil
 	}
 	// process index columns
+	var exprs []string
 	for _, col := range cols {
 		var field *xo.Field
-		// find field
-		for _, f := range table.Columns {
-			if f.Name == col.ColumnName {
+		expr := trimParens(col.Expr)
+		switch {
+		case expr != "":
+			// expressions that can not be passed as a param of the column's
+			// type have no field, and are skipped by templates generating
+			// funcs for the index
+			field = new(xo.Field)
+			if f, ok := exprField(expr, table.Columns); ok {
 				field = &f
-				break
+			}
+		case col.ColumnName == "":
+			return skip("expressions not supported")
+		default:
+			// find field
+			for _, f := range table.Columns {
+				if f.Name == col.ColumnName {
+					field = &f
+					break
+				}
+			}
+			// no corresponding field found
+			if field == nil {
+				return skip("column %q skipped", col.ColumnName)
 			}
 		}
-		// no corresponding field found
-		if field == nil {
+		index.Fields = append(index.Fields, *field)
+		exprs = append(exprs, expr)
+	}
+	if strings.Join(exprs, "") != "" {
+		index.Exprs = exprs
+	}
+	return true, nil
+}
+
+// exprFields returns the fields referenced by an expression.
+func exprFields(expr string, fields []xo.Field) []xo.Field {
+	var refs []xo.Field
+	for _, m := range exprIdentRE.FindAllStringSubmatch(expr, -1) {
+		name, quoted := m[3], false
+		switch {
+		case m[1] != "":
+			name, quoted = m[1][1:len(m[1])-1], true
+		case name == "" || m[2] != "" || m[4] != "":
+			// string literal, type cast, or function name
 			continue
 		}
-		index.Fields = append(index.Fields, *field)
+		for _, f := range fields {
+			if (name == f.Name || !quoted && strings.EqualFold(name, f.Name)) && !hasField(refs, f.Name) {
+				refs = append(refs, f)
+			}
+		}
+	}
+	return refs
+}
+
+// exprField returns the field for an index expression applying a function
+// that keeps the type of its argument (ie, lower(email)) to a single text
+// column. Returns false for any other expression, as the value of the
+// expression can not be passed as a param of the column's type.
+func exprField(expr string, fields []xo.Field) (xo.Field, bool) {
+	m := exprFuncRE.FindStringSubmatch(expr)
+	if m == nil {
+		return xo.Field{}, false
+	}
+	refs := exprFields(m[1], fields)
+	if len(refs) != 1 || !textTypeRE.MatchString(refs[0].Type.Type) {
+		return xo.Field{}, false
+	}
+	return refs[0], true
+}
+
+// exprFuncRE matches an expression applying a function that keeps the type of
+// its argument to a single, optionally cast, identifier.
+var exprFuncRE = regexp.MustCompile(`(?i)^(?:lower|upper|trim|ltrim|rtrim)\s*\(\s*(\(?\s*(?:"(?:[^"]|"")*"|` + "`[^`]*`" + `|\[[^\]]*\]|[a-z_][a-z0-9_$]*)\s*\)?(?:\s*::\s*(?:text|character varying|varchar|citext)(?:\(\d+\))?)?)\s*\)$`)
+
+// textTypeRE matches text database types.
+var textTypeRE = regexp.MustCompile(`char|text|clob|string`)
+
+// exprName returns a name for an expression, made from the expression's
+// function names and identifiers.
+func exprName(expr string) string {
+	var names []string
+	for _, m := range exprIdentRE.FindAllStringSubmatch(expr, -1) {
+		switch {
+		case m[1] != "":
+			names = append(names, m[1][1:len(m[1])-1])
+		case m[3] != "" && m[2] == "":
+			names = append(names, strings.ToLower(m[3]))
+		}
+	}
+	return strings.Join(names, "_")
+}
+
+// exprIdentRE matches string literals, quoted identifiers, and words in an
+// expression.
+var exprIdentRE = regexp.MustCompile(`'(?:[^']|'')*'|("(?:[^"]|"")*"|` + "`[^`]*`" + `|\[[^\]]*\])|(::\s*)?\b([A-Za-z_][A-Za-z0-9_$]*)(\s*\()?`)
+
+// trimParens trims whitespace and the parentheses enclosing s.
+func trimParens(s string) string {
+	s = strings.TrimSpace(s)
+	for len(s) > 1 && s[0] == '(' && s[len(s)-1] == ')' {
+		depth := 0
+		for i := 0; i < len(s)-1; i++ {
+			switch s[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				return s
+			}
+		}
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+// LoadTableChecks loads check constraint definitions per table.
+func LoadTableChecks(ctx context.Context, args *Args, table *xo.Table) error {
+	checks, err := loader.TableChecks(ctx, table.Name)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].CheckName < checks[j].CheckName
+	})
+	for _, c := range checks {
+		table.Checks = append(table.Checks, xo.Check{
+			Name: c.CheckName,
+			Expr: strings.TrimSpace(c.CheckExpr),
+		})
 	}
 	return nil
 }
 
 // LoadTableForeignKeys loads foreign key definitions per table.
+//
+// Foreign keys referring to a table in another schema are only loaded when
+// the other schema is also being loaded.
 func LoadTableForeignKeys(ctx context.Context, args *Args, tables []xo.Table, table xo.Table) ([]xo.ForeignKey, error) {
+	_, _, schema := xo.DriverDbSchema(ctx)
 	// load foreign keys
 	foreignKeys, err := loader.TableForeignKeys(ctx, table.Name)
 	if err != nil {
 		return nil, err
 	}
 	fkMap := make(map[string]xo.ForeignKey)
+	refTables := make(map[string]xo.Table)
 	// loop over foreign keys for table
 	for _, fkey := range foreignKeys {
 		// if the referenced table is excluded, we don't want to omit it
@@ -388,9 +824,39 @@ func LoadTableForeignKeys(ctx context.Context, args *Args, tables []xo.Table, ta
 			fmt.Fprintf(os.Stderr, "WARNING: skipping table %q foreign key %q (%q previously excluded)\n", table.Name, fkey.ForeignKeyName, fkey.RefTableName)
 			continue
 		}
+		// load referenced table from other schema
+		refSchema, lookup := fkey.RefSchemaName, tables
+		if refSchema == schema {
+			refSchema = ""
+		}
+		if refSchema != "" {
+			if !contains(schemaNamesFrom(ctx), refSchema) {
+				fmt.Fprintf(os.Stderr, "WARNING: skipping table %q foreign key %q (schema %q not loaded)\n", table.Name, fkey.ForeignKeyName, refSchema)
+				continue
+			}
+			key := refSchema + "." + fkey.RefTableName
+			t, ok := refTables[key]
+			if !ok {
+				var err error
+				if t, err = loadRefTable(context.WithValue(ctx, xo.SchemaKey, refSchema), args, fkey.RefTableName); err != nil {
+					return nil, err
+				}
+				refTables[key] = t
+			}
+			lookup = []xo.Table{t}
+		}
+		// skip when the column or referenced table was skipped by annotation
+		switch {
+		case !hasField(table.Columns, fkey.ColumnName):
+			fmt.Fprintf(os.Stderr, "WARNING: skipping table %q foreign key %q (column %q skipped)\n", table.Name, fkey.ForeignKeyName, fkey.ColumnName)
+			continue
+		case !hasTable(lookup, fkey.RefTableName):
+			fmt.Fprintf(os.Stderr, "WARNING: skipping table %q foreign key %q (table %q skipped)\n", table.Name, fkey.ForeignKeyName, fkey.RefTableName)
+			continue
+		}
 		// check foreign key
 		field, refTable, refField := xo.Field{}, xo.Table{}, xo.Field{}
-		if err := checkFk(tables, table, fkey, &field, &refTable, &refField); err != nil {
+		if err := checkFk(lookup, table, fkey, &field, &refTable, &refField); err != nil {
 			return nil, err
 		}
 		// ForeignKeyName should only be empty on SQLite. When this happens, we
@@ -404,7 +870,9 @@ func LoadTableForeignKeys(ctx context.Context, args *Args, tables []xo.Table, ta
 		fkMap[key] = xo.ForeignKey{
 			Name:      fkey.ForeignKeyName,
 			Fields:    append(f.Fields, field),
+			RefSchema: refSchema,
 			RefTable:  refTable.Name,
+			RefName:   refTable.Annotations["name"],
 			RefFields: append(f.RefFields, refField),
 		}
 	}
@@ -419,15 +887,14 @@ func LoadTableForeignKeys(ctx context.Context, args *Args, tables []xo.Table, ta
 			}
 			fkey.Name = table.Name + "_" + strings.Join(names, "_") + "_fkey"
 		}
-		// determine foreign key func name
-		fkey.Func = resolveFkName(fkey, table, args.SchemaParams.FkMode.AsString())
-		// foreign key called func name
-		fkey.RefFunc = indexFuncName(xo.Index{
-			IsUnique: true,
-			Fields:   fkey.RefFields,
-		}, fkey.RefTable, false)
 		fkeys = append(fkeys, fkey)
 	}
+	// resolve func names after all of the table's foreign keys are loaded
+	t := table
+	t.ForeignKeys = fkeys
+	for i := range fkeys {
+		fkeyFuncs(args, t, &fkeys[i])
+	}
 	// sort fkeys
 	sort.Slice(fkeys, func(i, j int) bool {
 		return fkeys[i].Name < fkeys[j].Name
@@ -435,6 +902,84 @@ func LoadTableForeignKeys(ctx context.Context, args *Args, tables []xo.Table, ta
 	return fkeys, nil
 }
 
+// loadRefTable loads the columns and annotations of a table referenced by a
+// foreign key in another schema.
+func loadRefTable(ctx context.Context, args *Args, name string) (xo.Table, error) {
+	t := xo.Table{
+		Type: "table",
+		Name: name,
+	}
+	tables, err := loader.Tables(ctx, "table")
+	if err != nil {
+		return xo.Table{}, err
+	}
+	for _, table := range tables {
+		if table.TableName != name {
+			continue
+		}
+		_, annotations, err := parseAnnotations(table.Comment)
+		switch {
+		case err != nil:
+			return xo.Table{}, fmt.Errorf("table %q: %w", name, err)
+		case annotations.skip():
+			return xo.Table{}, nil
+		}
+		t.Annotations = annotations
+	}
+	if err := LoadColumns(ctx, args, &t); err != nil {
+		return xo.Table{}, err
+	}
+	return t, nil
+}
+
+// fkeyFuncs sets the func names for a foreign key.
+func fkeyFuncs(args *Args, table xo.Table, fkey *xo.ForeignKey) {
+	mode := args.SchemaParams.FkMode.AsString()
+	// determine foreign key and reverse foreign key func names, resolved
+	// against all of the table's foreign keys, so that smart mode can detect
+	// multiple foreign keys referencing the same table
+	fkey.Func = resolveFkName(*fkey, table, mode)
+	fkey.RevFunc = resolveRevFkName(*fkey, table, mode)
+	// foreign key called func name
+	refTable := fkey.RefTable
+	if fkey.RefName != "" {
+		refTable = inflector.Pluralize(fkey.RefName)
+	}
+	fkey.RefFunc = indexFuncName(xo.Index{
+		IsUnique: true,
+		Fields:   fkey.RefFields,
+	}, refTable, false)
+}
+
+// funcTableName returns the table name used to name the table's index funcs,
+// using the table's xo:name annotation when present.
+func funcTableName(table xo.Table) string {
+	if name := table.Annotations["name"]; name != "" {
+		return inflector.Pluralize(name)
+	}
+	return table.Name
+}
+
+// hasField returns whether fields contains the named field.
+func hasField(fields []xo.Field, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTable returns whether tables contains the named table.
+func hasTable(tables []xo.Table, name string) bool {
+	for _, t := range tables {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // validType returns whether the type name given is valid, given the --include
 // and --exclude options provided by the user.
 func validType(args *Args, skipIncludes bool, names ...string) bool {
@@ -499,6 +1044,25 @@ func checkFk(tables []xo.Table, table xo.Table, fkey *models.ForeignKey, field *
 // The function converts all names to snake_case.
 func resolveFkName(fkey xo.ForeignKey, table xo.Table, mode string) string {
 	tableName := singularize(fkey.RefTable)
+	if fkey.RefName != "" {
+		tableName = fkey.RefName
+	}
+	return fkName(tableName, fkey, table, mode)
+}
+
+// resolveRevFkName returns the reverse foreign key name for the passed foreign
+// key, naming the func on the referenced type that retrieves the table's rows
+// referring to it.
+//
+// For example, if you have an `authors` and `books` tables, then the reverse
+// foreign key func will be Author.Books in parent mode.
+func resolveRevFkName(fkey xo.ForeignKey, table xo.Table, mode string) string {
+	return fkName(funcTableName(table), fkey, table, mode)
+}
+
+// fkName returns the foreign key name for the passed foreign key, using
+// tableName for the name of the type being retrieved.
+func fkName(tableName string, fkey xo.ForeignKey, table xo.Table, mode string) string {
 	switch mode {
 	case "parent":
 		// parent causes a foreign key field to be named in the form of
@@ -534,12 +1098,12 @@ func resolveFkName(fkey xo.ForeignKey, table xo.Table, mode string) string {
 		//
 		// inspect all foreign keys and use field if conflict found
 		for _, v := range table.ForeignKeys {
-			if fkey.Name != v.Name && fkey.RefTable == v.RefTable {
-				return resolveFkName(fkey, table, "field")
+			if fkey.Name != v.Name && fkey.RefSchema == v.RefSchema && fkey.RefTable == v.RefTable {
+				return fkName(tableName, fkey, table, "field")
 			}
 		}
 		// no conflict, so use parent mode
-		return resolveFkName(fkey, table, "parent")
+		return fkName(tableName, fkey, table, "parent")
 	}
 	panic(fmt.Sprintf("invalid mode %q", mode))
 }
@@ -555,8 +1119,12 @@ func indexFuncName(index xo.Index, tableName string, useIndexNames bool) string
 		return tableName + "_by_" + name
 	}
 	names := []string{tableName, "by"}
-	// add param names
-	for _, field := range index.Fields {
+	// add param names, or the names of expressions
+	for i, field := range index.Fields {
+		if i < len(index.Exprs) && index.Exprs[i] != "" {
+			names = append(names, exprName(index.Exprs[i]))
+			continue
+		}
 		names = append(names, field.Name)
 	}
 	return strings.Join(names, "_")
@@ -590,3 +1158,38 @@ func singularize(s string) string {
 	}
 	return inflector.Singularize(s)
 }
+
+// annotations are the xo:<name>[=<value>] annotations of a comment.
+type annotations map[string]string
+
+// skip returns whether the xo:skip annotation is present.
+func (a annotations) skip() bool {
+	_, ok := a["skip"]
+	return ok
+}
+
+// annotationRE matches a xo:<name>[=<value>] annotation in a comment.
+var annotationRE = regexp.MustCompile(`\bxo:([a-z_]+)(?:=(\S*))?`)
+
+// parseAnnotations parses the xo annotations in a comment, returning the
+// comment without the annotations and with line breaks removed.
+//
+// The xo:skip annotation omits the table or column from generation, and the
+// xo:name=<name> and xo:type=<type> annotations set the name and type used by
+// the templates.
+func parseAnnotations(comment string) (string, annotations, error) {
+	var a annotations
+	for _, m := range annotationRE.FindAllStringSubmatch(comment, -1) {
+		if (m[1] == "name" || m[1] == "type") && m[2] == "" {
+			return "", nil, fmt.Errorf("annotation xo:%s requires a value", m[1])
+		}
+		if a == nil {
+			a = make(annotations)
+		}
+		a[m[1]] = m[2]
+	}
+	if a != nil {
+		comment = strings.Join(strings.Fields(annotationRE.ReplaceAllString(comment, "")), " ")
+	}
+	return strings.Replace(strings.TrimSpace(comment), "\n", " ", -1), a, nil
+}
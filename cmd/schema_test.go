@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	xo "github.com/xo/xo/types"
+)
+
+func TestLoadIndexExprs(t *testing.T) {
+	set := loadDDL(t, "postgres", `CREATE TABLE events (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  email VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX events_created_at ON events (created_at);
+CREATE INDEX events_created_date ON events ((created_at::date));
+CREATE INDEX events_name_len ON events (length(name));
+CREATE INDEX events_lower_email ON events (lower(email));
+CREATE INDEX events_upper_name ON events (UPPER("name"));
+`)
+	var lines []string
+	for _, index := range set.Schemas[0].Tables[0].Indexes {
+		var fields []string
+		for _, f := range index.Fields {
+			fields = append(fields, f.Name)
+		}
+		lines = append(lines, index.Name+" "+index.Func+" ["+strings.Join(fields, " ")+"]")
+	}
+	exp := []string{
+		"events_created_at events_by_created_at [created_at]",
+		"events_created_date events_by_created_at []",
+		"events_lower_email events_by_lower_email [email]",
+		"events_name_len events_by_length_name []",
+		"events_pkey event_by_id [id]",
+		"events_upper_name events_by_upper_name [name]",
+	}
+	if s, exp := strings.Join(lines, "\n"), strings.Join(exp, "\n"); s != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, s)
+	}
+}
+
+func TestExprField(t *testing.T) {
+	fields := []xo.Field{
+		{Name: "id", Type: xo.Type{Type: "integer"}},
+		{Name: "email", Type: xo.Type{Type: "character varying"}},
+		{Name: "created_at", Type: xo.Type{Type: "timestamp"}},
+	}
+	tests := []struct {
+		expr string
+		exp  string
+	}{
+		{"lower(email)", "email"},
+		{"lower((email)::text)", "email"},
+		{"LOWER(`email`)", "email"},
+		{"upper([email])", "email"},
+		{`trim("email")`, "email"},
+		{"lower(email || 'x')", ""},
+		{"length(email)", ""},
+		{"created_at::date", ""},
+		{"lower(created_at)", ""},
+		{"abs(id)", ""},
+		{"id", ""},
+	}
+	for i, test := range tests {
+		f, ok := exprField(test.expr, fields)
+		if ok != (test.exp != "") || f.Name != test.exp {
+			t.Errorf("test %d %q expected %q, got: %q (%t)", i, test.expr, test.exp, f.Name, ok)
+		}
+	}
+}
+
+func TestForeignKeyFuncs(t *testing.T) {
+	set := loadDDL(t, "postgres", `CREATE TABLE authors (
+  author_id SERIAL PRIMARY KEY
+);
+CREATE TABLE publishers (
+  publisher_id SERIAL PRIMARY KEY
+);
+CREATE TABLE books (
+  book_id SERIAL PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  editor_id INTEGER REFERENCES authors (author_id),
+  publisher_id INTEGER NOT NULL REFERENCES publishers (publisher_id)
+);
+`)
+	exp := []string{
+		"books_author_id_fkey author_by_author_id books_by_author_id author_by_author_id",
+		"books_editor_id_fkey author_by_editor_id books_by_editor_id author_by_author_id",
+		"books_publisher_id_fkey publisher books publisher_by_publisher_id",
+	}
+	var tables []xo.Table
+	for _, table := range set.Schemas[0].Tables {
+		if table.Name == "books" {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) != 1 {
+		t.Fatalf("expected books table")
+	}
+	check := func(name string, fkeys []xo.ForeignKey) {
+		t.Helper()
+		var lines []string
+		for _, fkey := range fkeys {
+			lines = append(lines, fkey.Name+" "+fkey.Func+" "+fkey.RevFunc+" "+fkey.RefFunc)
+		}
+		if s, exp := strings.Join(lines, "\n"), strings.Join(exp, "\n"); s != exp {
+			t.Errorf("%s expected:\n%s\ngot:\n%s", name, exp, s)
+		}
+	}
+	check("load", tables[0].ForeignKeys)
+	// names resolved for a loaded set are the same
+	fkeys := tables[0].ForeignKeys
+	tables[0].ForeignKeys = append([]xo.ForeignKey(nil), fkeys...)
+	for i := range tables[0].ForeignKeys {
+		tables[0].ForeignKeys[i].Func, tables[0].ForeignKeys[i].RevFunc = "", ""
+	}
+	resolveFuncs(NewArgs("go"), set, set.Schemas[0].Name, tables)
+	check("resolve", tables[0].ForeignKeys)
+}
+
+// loadDDL loads the schema from the ddl.
+func loadDDL(t *testing.T, driver, ddl string) *xo.Set {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "schema.sql")
+	if err := os.WriteFile(file, []byte(ddl), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx, err := openDDL(context.Background(), []string{file}, driver, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	set := new(xo.Set)
+	if err := LoadSchema(ctx, set, NewArgs("go")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return set
+}
+
+func TestSchemaNames(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.sql")
+	if err := os.WriteFile(file, []byte(`CREATE TABLE public.authors (
+  author_id SERIAL PRIMARY KEY
+);
+CREATE TABLE lib.books (
+  book_id SERIAL PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES public.authors (author_id)
+);
+CREATE TABLE lib_archive.books (
+  book_id SERIAL PRIMARY KEY
+);
+CREATE TABLE audit.events (
+  event_id SERIAL PRIMARY KEY
+);
+`), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx, err := openDDL(context.Background(), []string{file}, "postgres", "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		schema string
+		exp    string
+		err    string
+	}{
+		{"", "public", ""},
+		{"lib", "lib", ""},
+		{"lib*", "lib,lib_archive", ""},
+		{"public, lib*", "public,lib,lib_archive", ""},
+		{"lib,lib*", "lib,lib_archive", ""},
+		{"{audit,public}", "audit,public", ""},
+		{"{audit,public},lib", "audit,public,lib", ""},
+		{"*", "audit,lib,lib_archive,public", ""},
+		{"missing", "missing", ""},
+		{"nope*", "", `no schemas match "nope*"`},
+		{"lib[", "", `invalid schema glob "lib["`},
+		{" , ", "", `invalid schema " , "`},
+	}
+	for i, test := range tests {
+		names, err := schemaNames(ctx, test.schema)
+		switch {
+		case test.err != "" && err == nil:
+			t.Errorf("test %d expected error %q", i, test.err)
+			continue
+		case test.err != "" && !strings.HasPrefix(err.Error(), test.err):
+			t.Errorf("test %d expected error %q, got: %v", i, test.err, err)
+			continue
+		case test.err != "":
+			continue
+		case err != nil:
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if s := strings.Join(names, ","); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	// cross-schema foreign keys are only loaded with the referenced schema
+	for i, schema := range []string{"lib", "public,lib"} {
+		ctx, err := openDDL(context.Background(), []string{file}, "postgres", schema)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		set := new(xo.Set)
+		if err := LoadSchema(ctx, set, NewArgs("go")); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		lib := set.Schemas[len(set.Schemas)-1]
+		if lib.Name != "lib" || len(lib.Tables) != 1 {
+			t.Fatalf("test %d expected schema lib with 1 table, got: %+v", i, lib)
+		}
+		switch fks := lib.Tables[0].ForeignKeys; {
+		case i == 0 && len(fks) != 0:
+			t.Errorf("test %d expected no foreign keys, got: %+v", i, fks)
+		case i == 1 && (len(fks) != 1 || fks[0].RefSchema != "public" || fks[0].RefTable != "authors"):
+			t.Errorf("test %d expected foreign key to public.authors, got: %+v", i, fks)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/xo/xo/loader"
+	xo "github.com/xo/xo/types"
+)
+
+// LoadSet loads a set previously dumped by the json or yaml templates,
+// restoring the enum links and func names that are not part of the dump.
+func LoadSet(name string, args *Args) (*xo.Set, error) {
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	set := new(xo.Set)
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+		err = json.Unmarshal(buf, set)
+	case ".yaml", ".yml":
+		if err = yaml.Unmarshal(buf, set); err == nil {
+			trimLineEnds(set)
+		}
+	default:
+		return nil, fmt.Errorf("unknown set file extension %q (expected .json, .yaml, or .yml)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load set %s: %w", name, err)
+	}
+	for i := range set.Schemas {
+		linkEnums(&set.Schemas[i])
+		resolveFuncs(args, set, set.Schemas[i].Name, set.Schemas[i].Tables)
+		resolveFuncs(args, set, set.Schemas[i].Name, set.Schemas[i].Views)
+	}
+	return set, nil
+}
+
+// trimLineEnds removes the line ends forced on single line definitions when
+// dumped as yaml (see xo.Table.MarshalYAML).
+func trimLineEnds(set *xo.Set) {
+	trim := func(s string) string {
+		if v := strings.TrimSuffix(s, "\n"); !strings.Contains(v, "\n") {
+			return v
+		}
+		return s
+	}
+	for i := range set.Schemas {
+		schema := &set.Schemas[i]
+		for j := range schema.Procs {
+			schema.Procs[j].Definition = trim(schema.Procs[j].Definition)
+		}
+		for _, tables := range [][]xo.Table{schema.Tables, schema.Views} {
+			for j := range tables {
+				tables[j].Definition = trim(tables[j].Definition)
+			}
+		}
+	}
+}
+
+// resolveFuncs resolves the index and foreign key func names for tables in
+// the named schema of the set.
+func resolveFuncs(args *Args, set *xo.Set, schema string, tables []xo.Table) {
+	for i := range tables {
+		table := tables[i]
+		for j, index := range table.Indexes {
+			table.Indexes[j].Func = indexFuncName(index, funcTableName(table), args.SchemaParams.UseIndexNames)
+		}
+		for j, fkey := range table.ForeignKeys {
+			refSchema := fkey.RefSchema
+			if refSchema == "" {
+				refSchema = schema
+			}
+			table.ForeignKeys[j].RefName = refName(set, refSchema, fkey.RefTable)
+			fkeyFuncs(args, table, &table.ForeignKeys[j])
+		}
+	}
+}
+
+// refName returns the name annotated on the named table of a schema in the
+// set, if any.
+func refName(set *xo.Set, schema, name string) string {
+	for _, s := range set.Schemas {
+		if s.Name != schema {
+			continue
+		}
+		for _, tables := range [][]xo.Table{s.Tables, s.Views} {
+			for _, t := range tables {
+				if t.Name == name {
+					return t.Annotations["name"]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// versionKey is the context key for the xo version.
+const versionKey xo.ContextKey = "version"
+
+// urlKey is the context key for the redacted database url.
+const urlKey xo.ContextKey = "url"
+
+// cacheFile returns the path of the --cache file for the database schemas,
+// named by a hash of the schema fingerprints and the args used to load the
+// schemas. An empty path is returned when caching is disabled or not
+// supported by the database.
+func cacheFile(ctx context.Context, args *Args) (string, error) {
+	if args.SchemaParams.Cache == "" {
+		return "", nil
+	}
+	driver, _, _ := xo.DriverDbSchema(ctx)
+	version, _ := ctx.Value(versionKey).(string)
+	urlstr, _ := ctx.Value(urlKey).(string)
+	key := []interface{}{version, driver, urlstr}
+	for _, name := range schemaNamesFrom(ctx) {
+		fingerprint, err := loader.Fingerprint(context.WithValue(ctx, xo.SchemaKey, name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: not caching schema (%v)\n", err)
+			return "", nil
+		}
+		key = append(key, name, fingerprint)
+	}
+	flags := make(map[xo.ContextKey]interface{})
+	for k, v := range args.LoaderParams.Flags {
+		flags[k] = v.Interface()
+	}
+	// the contents of the loader and template source directories, as both
+	// can change the loaded schemas
+	var srcs []string
+	for _, dir := range []string{args.LoaderParams.Src, args.TemplateParams.Src} {
+		h, err := hashDir(dir)
+		if err != nil {
+			return "", err
+		}
+		srcs = append(srcs, dir, h)
+	}
+	key = append(key,
+		fmt.Sprint(args.SchemaParams.Include.AsGlob()),
+		fmt.Sprint(args.SchemaParams.Exclude.AsGlob()),
+		args.SchemaParams.UseIndexNames,
+		args.TemplateParams.Type.AsString(),
+		srcs,
+		flags,
+	)
+	buf, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(args.SchemaParams.Cache, fmt.Sprintf("%x.xo.json", sha256.Sum256(buf))), nil
+}
+
+// hashDir returns a hash of the names and contents of the files in dir, or an
+// empty string when dir is empty.
+func hashDir(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+	h := sha256.New()
+	if err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case !d.Type().IsRegular():
+			return nil
+		}
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(buf))
+		_, err = h.Write(buf)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// writeCache writes the schemas to the cache file.
+func writeCache(file string, schemas []xo.Schema) error {
+	buf, err := json.Marshal(xo.Set{Schemas: schemas})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	// write to a temporary file and rename, so that concurrent runs do not
+	// read a partially written file
+	f, err := os.CreateTemp(filepath.Dir(file), ".xo-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), file)
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	xo "github.com/xo/xo/types"
+)
+
+func TestParallel(t *testing.T) {
+	for _, jobs := range []int{0, 1, 4} {
+		var mu sync.Mutex
+		var n, max int32
+		called := make([]bool, 20)
+		err := parallel(jobs, len(called), func(i int) error {
+			cur := atomic.AddInt32(&n, 1)
+			defer atomic.AddInt32(&n, -1)
+			mu.Lock()
+			called[i] = true
+			if max < cur {
+				max = cur
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			if i == 7 || i == 3 {
+				return errors.New(string(rune('a' + i)))
+			}
+			return nil
+		})
+		exp := int32(jobs)
+		if exp < 1 {
+			exp = 1
+		}
+		if max > exp {
+			t.Errorf("jobs %d expected at most %d concurrent calls, got: %d", jobs, exp, max)
+		}
+		for i, ok := range called {
+			if !ok {
+				t.Errorf("jobs %d expected item %d to be called", jobs, i)
+			}
+		}
+		// the error of the first failed item is returned
+		if err == nil || err.Error() != "d" {
+			t.Errorf("jobs %d expected error d, got: %v", jobs, err)
+		}
+	}
+}
+
+func TestLoadSchemaJobs(t *testing.T) {
+	file := sqliteDB(t, `CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL
+);
+CREATE TABLE books (
+  book_id INTEGER PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  title TEXT NOT NULL
+);
+CREATE INDEX books_title ON books (title);
+CREATE TABLE tags (
+  book_id INTEGER NOT NULL REFERENCES books (book_id),
+  tag TEXT NOT NULL,
+  PRIMARY KEY (book_id, tag)
+);
+CREATE VIEW book_titles AS SELECT book_id, title FROM books;
+`)
+	var sets []*xo.Set
+	for _, jobs := range []int{1, 8} {
+		ctx, err := open(context.Background(), "file:"+file, "")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		args := NewArgs("go")
+		args.SchemaParams.Jobs = jobs
+		set := new(xo.Set)
+		if err := LoadSchema(ctx, set, args); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		sets = append(sets, set)
+	}
+	if !reflect.DeepEqual(sets[0], sets[1]) {
+		t.Errorf("expected the same schema when loading concurrently")
+	}
+	if n := len(sets[0].Schemas[0].Tables); n != 3 {
+		t.Errorf("expected 3 tables, got: %d", n)
+	}
+}
+
+func TestLoadSchemaCache(t *testing.T) {
+	file := sqliteDB(t, `CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL
+);
+`)
+	dir := t.TempDir()
+	ctx, err := open(context.Background(), "file:"+file, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	args := NewArgs("go")
+	args.SchemaParams.Cache = dir
+	name, err := cacheFile(ctx, args)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	// cached on load
+	set := new(xo.Set)
+	if err := LoadSchema(ctx, set, args); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := os.Stat(name); err != nil {
+		t.Fatalf("expected cache file %s, got: %v", name, err)
+	}
+	// reused when unchanged
+	cached := new(xo.Set)
+	if err := LoadSchema(ctx, cached, args); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(set, cached) {
+		t.Errorf("expected cached schema to be the same")
+	}
+	// key changes with the loader and template sources
+	src := filepath.Join(dir, "src")
+	if err := os.Mkdir(src, 0o755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	names := map[string]bool{name: true}
+	check := func(desc string, f func()) {
+		t.Helper()
+		f()
+		s, err := cacheFile(ctx, args)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", desc, err)
+		}
+		if names[s] {
+			t.Errorf("%s: expected a different cache file", desc)
+		}
+		names[s] = true
+	}
+	write := func(s string) func() {
+		return func() {
+			if err := os.WriteFile(filepath.Join(src, "loader.go"), []byte(s), 0o644); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		}
+	}
+	check("loader src", func() { args.LoaderParams.Src = src })
+	check("loader file", write("package loader"))
+	check("loader file changed", write("package loader // changed"))
+	check("template src", func() { args.LoaderParams.Src, args.TemplateParams.Src = "", src })
+	check("template", func() { args.TemplateParams.Type.Set("json") })
+	// key changes with the database schema
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	check("schema", func() {
+		if _, err := db.Exec(`CREATE TABLE books (book_id INTEGER PRIMARY KEY)`); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+}
+
+// sqliteDB creates a sqlite3 database from the ddl, returning its path.
+func sqliteDB(t *testing.T, ddl string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(ddl); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return file
+}
+
+func TestLoadSet(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(file, []byte(`CREATE TABLE authors (
+  author_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  kind ENUM('fiction', 'nonfiction') NOT NULL
+);
+CREATE TABLE books (
+  book_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+  author_id INTEGER NOT NULL,
+  coauthor_id INTEGER,
+  title VARCHAR(255) NOT NULL,
+  FOREIGN KEY (author_id) REFERENCES authors (author_id),
+  FOREIGN KEY (coauthor_id) REFERENCES authors (author_id)
+);
+CREATE INDEX books_title_idx ON books (title);
+CREATE VIEW book_titles AS SELECT book_id, title FROM books;
+`), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx, err := openDDL(context.Background(), []string{file}, "mysql", "booktest")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := new(xo.Set)
+	if err := LoadSchema(ctx, exp, NewArgs("go")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for _, typ := range []string{"json", "yaml"} {
+		t.Run(typ, func(t *testing.T) {
+			out := filepath.Join(dir, typ)
+			if err := os.Mkdir(out, 0o755); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if err := Run(context.Background(), "xo", "0.0.0-dev", "schema", "--ddl", file, "--dialect", "mysql", "--schema", "booktest", "--template", typ, "--out", out); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			set, err := LoadSet(filepath.Join(out, "xo.xo."+typ), NewArgs("go"))
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if !reflect.DeepEqual(set, exp) {
+				t.Errorf("expected loaded set to equal the original set")
+			}
+			// enum columns are linked to the schema's enum
+			if e := set.Schemas[0].Tables[0].Columns[2].Type.Enum; e == nil || e.Name != "kind" {
+				t.Errorf("expected authors.kind to be linked to enum kind, got: %v", e)
+			}
+		})
+	}
+	if _, err := LoadSet(file, NewArgs("go")); err == nil || !strings.Contains(err.Error(), `unknown set file extension ".sql"`) {
+		t.Errorf("expected unknown extension error, got: %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build duckdb
+
+package main
+
+// The DuckDB driver requires cgo, and is only included when building with the
+// duckdb tag.
+import (
+	_ "github.com/marcboeker/go-duckdb"
+)
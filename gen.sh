@@ -4,6 +4,7 @@ PGDB=pg://
 MYDB=my://localhost/mysql
 MSDB=ms://
 SQDB=sq:xo.db
+DKDB=dk:xo.duckdb
 ORDB=or://localhost/free
 
 DEST=$1
@@ -22,7 +23,7 @@ XOBIN=$(realpath $XOBIN)
 set -ex
 
 mkdir -p $DEST
-rm -f *.db
+rm -f *.db *.duckdb
 rm -rf $DEST/*.xo.go
 
 # postgres view create query
@@ -50,6 +51,13 @@ $XOBIN query $PGDB -M -B -X -F PostgresViewDrop --func-comment "$COMMENT" --sing
 DROP VIEW %%id string,interpolate%%
 ENDSQL
 
+# postgres format type query
+COMMENT='{{ . }} formats a type oid and type modifier as a type name.'
+$XOBIN query $PGDB -M -B -l -F PostgresFormatType --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  format_type(%%oid int64%%, %%typmod int64%%)::varchar AS data_type
+ENDSQL
+
 # postgres schema query
 COMMENT='{{ . }} retrieves the schema.'
 $XOBIN query $PGDB -M -B -l -F PostgresSchema --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
@@ -57,6 +65,34 @@ SELECT
   CURRENT_SCHEMA()::varchar AS schema_name
 ENDSQL
 
+# postgres fingerprint query
+COMMENT='{{ . }} retrieves the schema fingerprint.'
+$XOBIN query $PGDB -M -B -l -F PostgresFingerprint --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  concat_ws(':',
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_class),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_attribute),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_attrdef),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_constraint),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_index),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_type),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_enum),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_proc),
+    (SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_description)
+  )::varchar AS fingerprint
+ENDSQL
+
+# postgres schema list query
+COMMENT='{{ . }} is a schema.'
+$XOBIN query $PGDB -M -B -2 -T Schema -F PostgresSchemas --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
+SELECT
+  n.nspname::varchar AS schema_name
+FROM pg_namespace n
+WHERE n.nspname NOT LIKE 'pg_%'
+  AND n.nspname <> 'information_schema'
+ORDER BY n.nspname
+ENDSQL
+
 # postgres enum list query
 COMMENT='{{ . }} is a enum.'
 $XOBIN query $PGDB -M -B -2 -T Enum -F PostgresEnums --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
@@ -81,6 +117,37 @@ WHERE n.nspname = %%schema string%%
   AND t.typname = %%enum string%%
 ENDSQL
 
+# postgres domain list query
+COMMENT='{{ . }} is a domain.'
+$XOBIN query $PGDB -M -B -2 -T Domain -F PostgresDomains --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
+SELECT
+  t.typname::varchar AS domain_name,
+  format_type(t.typbasetype, t.typtypmod)::varchar AS data_type,
+  t.typnotnull::boolean AS not_null,
+  COALESCE(t.typdefault, '')::varchar AS default_value,
+  COALESCE(obj_description(t.oid, 'pg_type'), '')::varchar AS comment
+FROM pg_type t
+  JOIN ONLY pg_namespace n ON n.oid = t.typnamespace
+WHERE t.typtype = 'd'
+  AND n.nspname = %%schema string%%
+ORDER BY t.typname
+ENDSQL
+
+# postgres composite type list query
+COMMENT='{{ . }} is a composite type.'
+$XOBIN query $PGDB -M -B -2 -T Composite -F PostgresComposites --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
+SELECT
+  t.typname::varchar AS composite_name,
+  COALESCE(obj_description(t.oid, 'pg_type'), '')::varchar AS comment
+FROM pg_type t
+  JOIN ONLY pg_namespace n ON n.oid = t.typnamespace
+  JOIN ONLY pg_class c ON c.oid = t.typrelid
+WHERE t.typtype = 'c'
+  AND c.relkind = 'c'
+  AND n.nspname = %%schema string%%
+ORDER BY t.typname
+ENDSQL
+
 # postgres proc list query
 COMMENT='{{ . }} is a stored procedure.'
 $XOBIN query $PGDB -M -B -2 -T Proc -F PostgresProcs --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
@@ -152,27 +219,48 @@ $XOBIN query $PGDB -M -B -2 -T Table -F PostgresTables --type-comment "$COMMENT"
 SELECT
   (CASE c.relkind
     WHEN 'r' THEN 'table'
+    WHEN 'p' THEN 'partitioned table'
+    WHEN 'f' THEN 'foreign table'
     WHEN 'v' THEN 'view'
+    WHEN 'm' THEN 'materialized view'
   END)::varchar AS type,
   c.relname::varchar AS table_name,
   false::boolean AS manual_pk,
   CASE c.relkind
-    WHEN 'r' THEN COALESCE(obj_description(c.relname::regclass), '')
+    WHEN 'p' THEN pg_get_partkeydef(c.oid)
+    WHEN 'f' THEN (
+      SELECT 'SERVER ' || quote_ident(s.srvname) || COALESCE(' OPTIONS (' || (
+        SELECT string_agg(quote_ident(o.option_name) || ' ' || quote_literal(o.option_value), ', ')
+        FROM pg_options_to_table(f.ftoptions) o
+      ) || ')', '')
+      FROM pg_foreign_table f
+        JOIN pg_foreign_server s ON s.oid = f.ftserver
+      WHERE f.ftrelid = c.oid
+    )
     WHEN 'v' THEN v.definition
-  END AS view_def
+    WHEN 'm' THEN m.definition
+    ELSE ''
+  END AS view_def,
+  COALESCE(obj_description(c.oid, 'pg_class'), '')::varchar AS comment
 FROM pg_class c
   JOIN ONLY pg_namespace n ON n.oid = c.relnamespace
   LEFT JOIN pg_views v ON n.nspname = v.schemaname
     AND v.viewname = c.relname
+  LEFT JOIN pg_matviews m ON n.nspname = m.schemaname
+    AND m.matviewname = c.relname
 WHERE n.nspname = %%schema string%%
+  AND NOT c.relispartition
   AND (CASE c.relkind
     WHEN 'r' THEN 'table'
+    WHEN 'p' THEN 'table'
+    WHEN 'f' THEN 'table'
     WHEN 'v' THEN 'view'
+    WHEN 'm' THEN 'view'
   END) = LOWER(%%typ string%%)
 ENDSQL
 
 # postgres table column list query
-FIELDS='FieldOrdinal int,ColumnName string,DataType string,NotNull bool,DefaultValue sql.NullString,IsPrimaryKey bool,Comment sql.NullString'
+FIELDS='FieldOrdinal int,ColumnName string,DataType string,NotNull bool,DefaultValue sql.NullString,IsPrimaryKey bool,IsGenerated bool,Comment sql.NullString'
 COMMENT='{{ . }} is a column.'
 $XOBIN query $PGDB -M -B -2 -T Column -F PostgresTableColumns -Z "$FIELDS" --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
 SELECT
@@ -182,6 +270,7 @@ SELECT
   a.attnotnull::boolean AS not_null,
   COALESCE(pg_get_expr(ad.adbin, ad.adrelid), '')::varchar AS default_value,
   COALESCE(ct.contype = 'p', false)::boolean AS is_primary_key,
+  (a.attgenerated <> '')::boolean AS is_generated,
   d.description::varchar as comment
 FROM pg_attribute a
   JOIN ONLY pg_class c ON c.oid = a.attrelid
@@ -221,6 +310,7 @@ $XOBIN query $PGDB -M -B -2 -T ForeignKey -F PostgresTableForeignKeys --type-com
 SELECT
   tc.constraint_name::varchar AS foreign_key_name,
   kcu.column_name::varchar AS column_name,
+  ccu.table_schema::varchar AS ref_schema_name,
   ccu.table_name::varchar AS ref_table_name,
   ccu.column_name::varchar AS ref_column_name,
   0::integer AS key_id
@@ -240,6 +330,7 @@ FROM information_schema.table_constraints tc
       table_schema,
       table_name,
       column_name,
+      constraint_schema,
       constraint_name
     FROM (
       SELECT
@@ -247,30 +338,60 @@ FROM information_schema.table_constraints tc
         table_schema,
         table_name,
         column_name,
+        constraint_schema,
         constraint_name
       FROM information_schema.constraint_column_usage
     ) t
   ) AS ccu ON ccu.constraint_name = tc.constraint_name
-    AND ccu.table_schema = tc.table_schema
+    AND ccu.constraint_schema = tc.constraint_schema
     AND ccu.ordinal_position = kcu.ordinal_position
 WHERE tc.constraint_type = 'FOREIGN KEY'
   AND tc.table_schema = %%schema string%%
   AND tc.table_name = %%table string%%
 ENDSQL
 
+# postgres table check constraint list query
+COMMENT='{{ . }} is a check constraint.'
+$XOBIN query $PGDB -M -B -2 -T Check -F PostgresTableChecks --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
+SELECT
+  c.conname::varchar AS check_name,
+  pg_get_expr(c.conbin, c.conrelid)::varchar AS check_expr
+FROM pg_constraint c
+  JOIN ONLY pg_class t ON t.oid = c.conrelid
+  JOIN ONLY pg_namespace n ON n.oid = t.relnamespace
+WHERE c.contype = 'c'
+  AND n.nspname = %%schema string%%
+  AND t.relname = %%table string%%
+ORDER BY c.conname
+ENDSQL
+
+# postgres domain check constraint list query
+$XOBIN query $PGDB -M -B -2 -T Check -F PostgresDomainChecks -a -o $DEST $@ << ENDSQL
+SELECT
+  c.conname::varchar AS check_name,
+  pg_get_expr(c.conbin, 0)::varchar AS check_expr
+FROM pg_constraint c
+  JOIN ONLY pg_type t ON t.oid = c.contypid
+  JOIN ONLY pg_namespace n ON n.oid = t.typnamespace
+WHERE c.contype = 'c'
+  AND n.nspname = %%schema string%%
+  AND t.typname = %%domain string%%
+ORDER BY c.conname
+ENDSQL
+
 # postgres table index list query
 COMMENT='{{ . }} is a index.'
 $XOBIN query $PGDB -M -B -2 -T Index -F PostgresTableIndexes --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
 SELECT
   DISTINCT ic.relname::varchar AS index_name,
   i.indisunique::boolean AS is_unique,
-  i.indisprimary::boolean AS is_primary
+  i.indisprimary::boolean AS is_primary,
+  COALESCE(pg_get_expr(i.indpred, i.indrelid, true), '')::varchar AS predicate
 FROM pg_index i
   JOIN ONLY pg_class c ON c.oid = i.indrelid
   JOIN ONLY pg_namespace n ON n.oid = c.relnamespace
   JOIN ONLY pg_class ic ON ic.oid = i.indexrelid
-WHERE i.indkey <> '0'
-  AND n.nspname = %%schema string%%
+WHERE n.nspname = %%schema string%%
   AND c.relname = %%table string%%
 ENDSQL
 
@@ -278,32 +399,26 @@ ENDSQL
 COMMENT='{{ . }} is a index column.'
 $XOBIN query $PGDB -M -B -2 -T IndexColumn -F PostgresIndexColumns --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
 SELECT
-  (row_number() over())::integer AS seq_no,
-  a.attnum::integer AS cid,
-  a.attname::varchar AS column_name
+  k.n::integer AS seq_no,
+  k.attnum::integer AS cid,
+  COALESCE(a.attname, '')::varchar AS column_name,
+  (CASE
+    WHEN k.attnum = 0 THEN pg_get_indexdef(i.indexrelid, k.n::integer, true)
+    ELSE ''
+  END)::varchar AS expr
 FROM pg_index i
   JOIN ONLY pg_class c ON c.oid = i.indrelid
   JOIN ONLY pg_namespace n ON n.oid = c.relnamespace
   JOIN ONLY pg_class ic ON ic.oid = i.indexrelid
+  CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, n)
   LEFT JOIN pg_attribute a ON i.indrelid = a.attrelid
-    AND a.attnum = ANY(i.indkey)
+    AND a.attnum = k.attnum
     AND a.attisdropped = false
-WHERE i.indkey <> '0'
+WHERE k.n <= i.indnkeyatts
   AND n.nspname = %%schema string%%
+  AND c.relname = %%table string%%
   AND ic.relname = %%index string%%
-ENDSQL
-
-# postgres index column order query
-COMMENT='{{ . }} is a index column order.'
-$XOBIN query $PGDB -M -B -1 -2 -T PostgresColOrder -F PostgresGetColOrder --type-comment "$COMMENT" -o $DEST $@ << ENDSQL
-SELECT
-  i.indkey::varchar AS ord
-FROM pg_index i
-  JOIN ONLY pg_class c ON c.oid = i.indrelid
-  JOIN ONLY pg_namespace n ON n.oid = c.relnamespace
-  JOIN ONLY pg_class ic ON ic.oid = i.indexrelid
-WHERE n.nspname = %%schema string%%
-  AND ic.relname = %%index string%%
+ORDER BY k.n
 ENDSQL
 
 # mysql view create query
@@ -327,6 +442,29 @@ SELECT
   SCHEMA() AS schema_name
 ENDSQL
 
+# mysql fingerprint query
+COMMENT='{{ . }} retrieves the schema fingerprint.'
+$XOBIN query $MYDB -M -B -l -F MysqlFingerprint --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  CONCAT_WS(':',
+    (SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, table_type, create_time, table_comment))), 0)) FROM information_schema.tables WHERE table_schema = p.schema_name),
+    (SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, column_name, ordinal_position, column_type, is_nullable, column_default, extra, column_comment))), 0)) FROM information_schema.columns WHERE table_schema = p.schema_name),
+    (SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, index_name, seq_in_index, column_name, non_unique))), 0)) FROM information_schema.statistics WHERE table_schema = p.schema_name),
+    (SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, constraint_name, column_name, referenced_table_name, referenced_column_name))), 0)) FROM information_schema.key_column_usage WHERE table_schema = p.schema_name),
+    (SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', routine_name, routine_type, created, last_altered))), 0)) FROM information_schema.routines WHERE routine_schema = p.schema_name)
+  ) AS fingerprint
+FROM (SELECT %%schema string%% AS schema_name) p
+ENDSQL
+
+# mysql schema list query
+$XOBIN query $MYDB -M -B -2 -T Schema -F MysqlSchemas -a -o $DEST $@ << ENDSQL
+SELECT
+  schema_name
+FROM information_schema.schemata
+WHERE schema_name NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+ORDER BY schema_name
+ENDSQL
+
 # mysql enum list query
 $XOBIN query $MYDB -M -B -2 -T Enum -F MysqlEnums -a -o $DEST $@ << ENDSQL
 SELECT
@@ -388,7 +526,8 @@ SELECT
   CASE t.table_type
     WHEN 'BASE TABLE' THEN ''
     WHEN 'VIEW' then v.view_definition
-  END AS view_def
+  END AS view_def,
+  IF(t.table_type = 'VIEW', '', t.table_comment) AS comment
 FROM information_schema.tables t
   LEFT JOIN information_schema.views v ON t.table_schema = v.table_schema
     AND t.table_name = v.table_name
@@ -406,8 +545,9 @@ SELECT
   column_name,
   IF(data_type = 'enum', column_name, column_type) AS data_type,
   IF(is_nullable = 'YES', false, true) AS not_null,
-  column_default AS default_value,
+  IF(extra IN ('STORED GENERATED', 'VIRTUAL GENERATED'), generation_expression, column_default) AS default_value,
   IF(column_key = 'PRI', true, false) AS is_primary_key,
+  IF(extra IN ('STORED GENERATED', 'VIRTUAL GENERATED'), true, false) AS is_generated,
   column_comment AS comment
 FROM information_schema.columns
 WHERE table_schema = %%schema string%%
@@ -430,6 +570,7 @@ $XOBIN query $MYDB -M -B -2 -T ForeignKey -F MysqlTableForeignKeys -a -o $DEST $
 SELECT
   constraint_name AS foreign_key_name,
   column_name AS column_name,
+  referenced_table_schema AS ref_schema_name,
   referenced_table_name AS ref_table_name,
   referenced_column_name AS ref_column_name
 FROM information_schema.key_column_usage
@@ -438,6 +579,20 @@ WHERE referenced_table_name IS NOT NULL
   AND table_name = %%table string%%
 ENDSQL
 
+# mysql table check constraint list query
+$XOBIN query $MYDB -M -B -2 -T Check -F MysqlTableChecks -a -o $DEST $@ << ENDSQL
+SELECT
+  tc.constraint_name AS check_name,
+  cc.check_clause AS check_expr
+FROM information_schema.table_constraints tc
+  JOIN information_schema.check_constraints cc ON cc.constraint_schema = tc.constraint_schema
+    AND cc.constraint_name = tc.constraint_name
+WHERE tc.constraint_type = 'CHECK'
+  AND tc.table_schema = %%schema string%%
+  AND tc.table_name = %%table string%%
+ORDER BY tc.constraint_name
+ENDSQL
+
 # mysql table index list query
 $XOBIN query $MYDB -M -B -2 -T Index -F MysqlTableIndexes -a -o $DEST $@ << ENDSQL
 SELECT
@@ -453,7 +608,7 @@ ENDSQL
 $XOBIN query $MYDB -M -B -2 -T IndexColumn -F MysqlIndexColumns -a -o $DEST $@ << ENDSQL
 SELECT
   seq_in_index AS seq_no,
-  column_name
+  COALESCE(column_name, '') AS column_name
 FROM information_schema.statistics
 WHERE index_schema = %%schema string%%
   AND table_name = %%table string%%
@@ -483,6 +638,37 @@ SELECT
 FROM pragma_database_list()
 ENDSQL
 
+# sqlite3 fingerprint query
+COMMENT='{{ . }} retrieves the schema fingerprint.'
+$XOBIN query $SQDB -M -B -l -F Sqlite3Fingerprint --func-comment "$COMMENT" --single=models.xo.go -I -a -o $DEST $@ << ENDSQL
+/* %%schema string,interpolate%% */
+SELECT
+  COALESCE(GROUP_CONCAT(type || ' ' || name || ' ' || COALESCE(sql, ''), ';'), '') AS fingerprint
+FROM sqlite_master
+ENDSQL
+
+# sqlite3 table sql query
+COMMENT='{{ . }} retrieves the definition for a table.'
+$XOBIN query $SQDB -M -B -l -F Sqlite3TableSQL --func-comment "$COMMENT" --single=models.xo.go -I -a -o $DEST $@ << ENDSQL
+/* %%schema string,interpolate%% */
+SELECT
+  sql AS table_sql
+FROM sqlite_master
+WHERE type = 'table'
+  AND tbl_name = %%table string%%
+ENDSQL
+
+# sqlite3 index sql query
+COMMENT='{{ . }} retrieves the definition for an index.'
+$XOBIN query $SQDB -M -B -l -F Sqlite3IndexSQL --func-comment "$COMMENT" --single=models.xo.go -I -a -o $DEST $@ << ENDSQL
+/* %%schema string,interpolate%% */
+SELECT
+  COALESCE(sql, '') AS index_sql
+FROM sqlite_master
+WHERE type = 'index'
+  AND name = %%index string%%
+ENDSQL
+
 # sqlite3 table list query
 $XOBIN query $SQDB -M -B -2 -T Table -F Sqlite3Tables -I -a -o $DEST $@ << ENDSQL
 /* %%schema string,interpolate%% */
@@ -492,7 +678,8 @@ SELECT
   CASE LOWER(type)
     WHEN 'table' THEN ''
     WHEN 'view' THEN sql
-  END AS view_def
+  END AS view_def,
+  '' AS comment
 FROM sqlite_master
 WHERE tbl_name NOT LIKE 'sqlite_%'
   AND LOWER(type) = LOWER(%%typ string%%)
@@ -507,8 +694,10 @@ SELECT
   type AS data_type,
   "notnull" AS not_null,
   dflt_value AS default_value,
-  CAST(pk <> 0 AS boolean) AS is_primary_key
-FROM pragma_table_info(%%table string%%)
+  CAST(pk <> 0 AS boolean) AS is_primary_key,
+  CAST(hidden IN (2, 3) AS boolean) AS is_generated
+FROM pragma_table_xinfo(%%table string%%)
+WHERE hidden <> 1
 ENDSQL
 
 # sqlite3 sequence list query
@@ -572,10 +761,254 @@ $XOBIN query $SQDB -M -B -2 -T IndexColumn -F Sqlite3IndexColumns -I -a -o $DEST
 SELECT
   seqno AS seq_no,
   cid,
-  name AS column_name
+  COALESCE(name, '') AS column_name
 FROM pragma_index_info(%%index string%%)
 ENDSQL
 
+# duckdb view create query
+COMMENT='{{ . }} creates a view for introspection.'
+$XOBIN query $DKDB -M -B -X -F DuckdbViewCreate --func-comment "$COMMENT" --single=models.xo.go -I -a -o $DEST $@ << ENDSQL
+/* %%schema string,interpolate%% */
+CREATE TEMPORARY VIEW %%id string,interpolate%% AS %%query []string,interpolate,join%%
+ENDSQL
+
+# duckdb view schema query
+COMMENT='{{ . }} retrieves the schema for a view created for introspection.'
+$XOBIN query $DKDB -M -B -l -F DuckdbViewSchema --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  schema_name
+FROM duckdb_views()
+WHERE temporary
+  AND view_name = %%id string%%
+ENDSQL
+
+# duckdb view drop query
+COMMENT='{{ . }} drops a view created for introspection.'
+$XOBIN query $DKDB -M -B -X -F DuckdbViewDrop --func-comment "$COMMENT" --single=models.xo.go -I -a -o $DEST $@ << ENDSQL
+/* %%schema string,interpolate%% */
+DROP VIEW %%id string,interpolate%%
+ENDSQL
+
+# duckdb schema query
+COMMENT='{{ . }} retrieves the schema.'
+$XOBIN query $DKDB -M -B -l -F DuckdbSchema --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  CURRENT_SCHEMA() AS schema_name
+ENDSQL
+
+# duckdb fingerprint query
+COMMENT='{{ . }} retrieves the schema fingerprint.'
+$XOBIN query $DKDB -M -B -l -F DuckdbFingerprint --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  MD5(CONCAT_WS(':',
+    (SELECT STRING_AGG(table_name || ' ' || sql || ' ' || COALESCE(comment, ''), ';' ORDER BY table_name) FROM duckdb_tables() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name),
+    (SELECT STRING_AGG(table_name || ' ' || column_name || ' ' || comment, ';' ORDER BY table_name, column_index) FROM duckdb_columns() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name AND comment IS NOT NULL),
+    (SELECT STRING_AGG(view_name || ' ' || sql || ' ' || COALESCE(comment, ''), ';' ORDER BY view_name) FROM duckdb_views() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name),
+    (SELECT STRING_AGG(index_name || ' ' || sql, ';' ORDER BY index_name) FROM duckdb_indexes() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name)
+  )) AS fingerprint
+FROM (SELECT CAST(%%schema string%% AS VARCHAR) AS schema_name) p
+ENDSQL
+
+# duckdb table sql query
+COMMENT='{{ . }} retrieves the definition for a table.'
+$XOBIN query $DKDB -M -B -l -F DuckdbTableSQL --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  sql AS table_sql
+FROM duckdb_tables()
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND table_name = %%table string%%
+ENDSQL
+
+# duckdb index sql query
+COMMENT='{{ . }} retrieves the definition for an index.'
+$XOBIN query $DKDB -M -B -l -F DuckdbIndexSQL --func-comment "$COMMENT" --single=models.xo.go -a -o $DEST $@ << ENDSQL
+SELECT
+  COALESCE(sql, '') AS index_sql
+FROM duckdb_indexes()
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND index_name = %%index string%%
+ENDSQL
+
+# duckdb schema list query
+$XOBIN query $DKDB -M -B -2 -T Schema -F DuckdbSchemas -a -o $DEST $@ << ENDSQL
+SELECT
+  schema_name
+FROM duckdb_schemas()
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name NOT IN ('information_schema', 'pg_catalog')
+ORDER BY schema_name
+ENDSQL
+
+# duckdb table list query
+$XOBIN query $DKDB -M -B -2 -T Table -F DuckdbTables -a -o $DEST $@ << ENDSQL
+SELECT
+  type,
+  table_name,
+  view_def,
+  comment
+FROM (
+  SELECT
+    'table' AS type,
+    schema_name,
+    table_name,
+    '' AS view_def,
+    COALESCE(comment, '') AS comment
+  FROM duckdb_tables()
+  WHERE database_name = CURRENT_DATABASE()
+    AND NOT internal
+    AND NOT temporary
+  UNION ALL
+  SELECT
+    'view' AS type,
+    schema_name,
+    view_name AS table_name,
+    sql AS view_def,
+    COALESCE(comment, '') AS comment
+  FROM duckdb_views()
+  WHERE database_name = CURRENT_DATABASE()
+    AND NOT internal
+    AND NOT temporary
+) t
+WHERE schema_name = %%schema string%%
+  AND type = LOWER(%%typ string%%)
+ORDER BY table_name
+ENDSQL
+
+# duckdb table column list query
+$XOBIN query $DKDB -M -B -2 -T Column -F DuckdbTableColumns -a -o $DEST $@ << ENDSQL
+SELECT
+  c.column_index AS field_ordinal,
+  c.column_name,
+  c.data_type,
+  NOT c.is_nullable AS not_null,
+  c.column_default AS default_value,
+  COALESCE(LIST_CONTAINS(p.constraint_column_names, c.column_name), false) AS is_primary_key,
+  false AS is_generated,
+  c.comment
+FROM duckdb_columns() c
+  LEFT JOIN duckdb_constraints() p ON p.database_name = c.database_name
+    AND p.schema_name = c.schema_name
+    AND p.table_name = c.table_name
+    AND p.constraint_type = 'PRIMARY KEY'
+WHERE c.database_name IN (CURRENT_DATABASE(), 'temp')
+  AND c.schema_name = %%schema string%%
+  AND c.table_name = %%table string%%
+ORDER BY c.column_index
+ENDSQL
+
+# duckdb sequence list query
+$XOBIN query $DKDB -M -B -2 -T Sequence -F DuckdbTableSequences -a -o $DEST $@ << ENDSQL
+SELECT
+  column_name
+FROM duckdb_columns()
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND table_name = %%table string%%
+  AND column_default LIKE 'nextval(%'
+ORDER BY column_index
+ENDSQL
+
+# duckdb table foreign key list query
+$XOBIN query $DKDB -M -B -2 -T ForeignKey -F DuckdbTableForeignKeys -a -o $DEST $@ << ENDSQL
+SELECT
+  table_name || '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_fkey' AS foreign_key_name,
+  UNNEST(constraint_column_names) AS column_name,
+  schema_name AS ref_schema_name,
+  TRIM(CASE
+    WHEN STARTS_WITH(ref_table, schema_name || '.') THEN SUBSTRING(ref_table, LENGTH(schema_name) + 2)
+    ELSE ref_table
+  END, '"') AS ref_table_name,
+  UNNEST(LIST_TRANSFORM(STRING_SPLIT(ref_cols, ', '), c -> TRIM(c, '"'))) AS ref_column_name,
+  constraint_index AS key_id
+FROM (
+  SELECT
+    *,
+    REGEXP_EXTRACT(constraint_text, 'REFERENCES (.+)\((.*)\)$', 1) AS ref_table,
+    REGEXP_EXTRACT(constraint_text, 'REFERENCES (.+)\((.*)\)$', 2) AS ref_cols
+  FROM duckdb_constraints()
+  WHERE constraint_type = 'FOREIGN KEY'
+) k
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND table_name = %%table string%%
+ORDER BY constraint_index
+ENDSQL
+
+# duckdb table check constraint list query
+$XOBIN query $DKDB -M -B -2 -T Check -F DuckdbTableChecks -a -o $DEST $@ << ENDSQL
+SELECT
+  table_name || '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_check' AS check_name,
+  expression AS check_expr
+FROM duckdb_constraints()
+WHERE constraint_type = 'CHECK'
+  AND database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND table_name = %%table string%%
+ORDER BY constraint_index
+ENDSQL
+
+# duckdb table index list query
+$XOBIN query $DKDB -M -B -2 -T Index -F DuckdbTableIndexes -a -o $DEST $@ << ENDSQL
+SELECT
+  index_name,
+  is_unique,
+  is_primary
+FROM (
+  SELECT
+    database_name,
+    schema_name,
+    table_name,
+    table_name || CASE constraint_type
+      WHEN 'PRIMARY KEY' THEN '_pkey'
+      ELSE '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_key'
+    END AS index_name,
+    true AS is_unique,
+    constraint_type = 'PRIMARY KEY' AS is_primary
+  FROM duckdb_constraints()
+  WHERE constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+  UNION ALL
+  SELECT
+    database_name,
+    schema_name,
+    table_name,
+    index_name,
+    is_unique,
+    is_primary
+  FROM duckdb_indexes()
+) i
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND table_name = %%table string%%
+ENDSQL
+
+# duckdb index column list query
+$XOBIN query $DKDB -M -B -2 -T IndexColumn -F DuckdbIndexColumns -a -o $DEST $@ << ENDSQL
+SELECT
+  seq_no,
+  column_name
+FROM (
+  SELECT
+    database_name,
+    schema_name,
+    table_name,
+    table_name || CASE constraint_type
+      WHEN 'PRIMARY KEY' THEN '_pkey'
+      ELSE '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_key'
+    END AS index_name,
+    UNNEST(RANGE(1, LEN(constraint_column_names) + 1)) AS seq_no,
+    UNNEST(constraint_column_names) AS column_name
+  FROM duckdb_constraints()
+  WHERE constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+) c
+WHERE database_name = CURRENT_DATABASE()
+  AND schema_name = %%schema string%%
+  AND table_name = %%table string%%
+  AND index_name = %%index string%%
+ORDER BY seq_no
+ENDSQL
+
 # sqlserver view create query
 COMMENT='{{ . }} creates a view for introspection.'
 $XOBIN query $MSDB -M -B -X -F SqlserverViewCreate --func-comment "$COMMENT" --single=models.xo.go -I -a -o $DEST $@ << ENDSQL
@@ -597,6 +1030,17 @@ SELECT
   SCHEMA_NAME() AS schema_name
 ENDSQL
 
+# sqlserver schema list query
+$XOBIN query $MSDB -M -B -2 -T Schema -F SqlserverSchemas -a -o $DEST $@ << ENDSQL
+SELECT
+  s.name AS schema_name
+FROM sys.schemas s
+  INNER JOIN sys.database_principals p ON p.principal_id = s.principal_id
+WHERE p.is_fixed_role = 0
+  AND s.name NOT IN ('guest', 'INFORMATION_SCHEMA', 'sys')
+ORDER BY s.name
+ENDSQL
+
 # sqlserver proc list query
 $XOBIN query $MSDB -M -B -2 -T Proc -F SqlserverProcs -a -o $DEST $@ << ENDSQL
 SELECT
@@ -642,18 +1086,23 @@ ENDSQL
 # sqlserver table list query
 $XOBIN query $MSDB -M -B -2 -T Table -F SqlserverTables -a -o $DEST $@ << ENDSQL
 SELECT
-  (CASE xtype
+  (CASE o.xtype
     WHEN 'U' THEN 'table'
     WHEN 'V' THEN 'view'
   END) AS type,
-  name AS table_name,
-  CASE xtype
+  o.name AS table_name,
+  CASE o.xtype
     WHEN 'U' THEN ''
-    WHEN 'V' THEN OBJECT_DEFINITION(id)
-  END AS view_def
-FROM sysobjects
-WHERE SCHEMA_NAME(uid) = %%schema string%%
-  AND (CASE xtype
+    WHEN 'V' THEN OBJECT_DEFINITION(o.id)
+  END AS view_def,
+  COALESCE(CAST(p.value AS NVARCHAR(4000)), '') AS comment
+FROM sysobjects o
+  LEFT JOIN sys.extended_properties p ON p.major_id = o.id
+    AND p.minor_id = 0
+    AND p.class = 1
+    AND p.name = 'MS_Description'
+WHERE SCHEMA_NAME(o.uid) = %%schema string%%
+  AND (CASE o.xtype
     WHEN 'U' THEN 'table'
     WHEN 'V' THEN 'view'
   END) = LOWER(%%typ string%%)
@@ -666,7 +1115,7 @@ SELECT
   c.name AS column_name,
   TYPE_NAME(c.xtype)+IIF(c.prec > 0, '('+CAST(c.prec AS varchar)+IIF(c.scale > 0,','+CAST(c.scale AS varchar),'')+')', '') AS data_type,
   IIF(c.isnullable=1, 0, 1) AS not_null,
-  x.text AS default_value,
+  COALESCE(cc.definition, x.text) AS default_value,
   IIF(COALESCE((
     SELECT COUNT(z.colid)
     FROM sysindexes i
@@ -675,12 +1124,15 @@ SELECT
         AND z.colid = c.colid
     WHERE i.id = o.id
       AND i.name = k.name
-  ), 0) > 0, 1, 0) AS is_primary_key
+  ), 0) > 0, 1, 0) AS is_primary_key,
+  IIF(cc.column_id IS NULL, 0, 1) AS is_generated
 FROM syscolumns c
   JOIN sysobjects o ON o.id = c.id
   LEFT JOIN sysobjects k ON k.xtype = 'PK'
     AND k.parent_obj = o.id
   LEFT JOIN syscomments x ON x.id = c.cdefault
+  LEFT JOIN sys.computed_columns cc ON cc.object_id = c.id
+    AND cc.column_id = c.colid
 WHERE o.type IN('U', 'V')
   AND SCHEMA_NAME(o.uid) = %%schema string%%
   AND o.name = %%table string%%
@@ -704,6 +1156,7 @@ $XOBIN query $MSDB -M -B -2 -T ForeignKey -F SqlserverTableForeignKeys -a -o $DE
 SELECT
   fk.name AS foreign_key_name,
   col.name AS column_name,
+  schema_name(pk_tab.schema_id) AS ref_schema_name,
   pk_tab.name AS ref_table_name,
   pk_col.name AS ref_column_name
 FROM sys.tables tab
@@ -719,12 +1172,25 @@ WHERE schema_name(tab.schema_id) = %%schema string%%
   AND fk.object_id IS NOT NULL
 ENDSQL
 
+# sqlserver table check constraint list query
+$XOBIN query $MSDB -M -B -2 -T Check -F SqlserverTableChecks -a -o $DEST $@ << ENDSQL
+SELECT
+  cc.name AS check_name,
+  cc.definition AS check_expr
+FROM sys.check_constraints cc
+  JOIN sys.tables t ON t.object_id = cc.parent_object_id
+WHERE schema_name(t.schema_id) = %%schema string%%
+  AND t.name = %%table string%%
+ORDER BY cc.name
+ENDSQL
+
 # sqlserver table index list query
 $XOBIN query $MSDB -M -B -2 -T Index -F SqlserverTableIndexes -a -o $DEST $@ << ENDSQL
 SELECT
   i.name AS index_name,
   i.is_primary_key AS is_primary,
-  i.is_unique
+  i.is_unique,
+  COALESCE(i.filter_definition, '') AS predicate
 FROM sys.indexes i
   INNER JOIN sysobjects o ON i.object_id = o.id
 WHERE i.name IS NOT NULL
@@ -781,6 +1247,15 @@ SELECT
 FROM dual
 ENDSQL
 
+# oracle schema list query
+$XOBIN query $ORDB -M -B -2 -T Schema -F OracleSchemas -a -o $DEST $@ << ENDSQL
+SELECT
+  LOWER(username) AS schema_name
+FROM all_users
+WHERE oracle_maintained = 'N'
+ORDER BY username
+ENDSQL
+
 # oracle proc list query
 $XOBIN query $ORDB -M -B -2 -T Proc -F OracleProcs -a -o $DEST $@ << ENDSQL
 SELECT
@@ -840,10 +1315,13 @@ SELECT
   CASE o.object_type
     WHEN 'TABLE' THEN ' '
     WHEN 'VIEW' THEN v.text_vc
-  END AS view_def
+  END AS view_def,
+  NVL(c.comments, ' ') AS comment
 FROM all_objects o
   LEFT JOIN all_views v ON o.owner = v.owner
     AND o.object_name = v.view_name
+  LEFT JOIN all_tab_comments c ON o.owner = c.owner
+    AND o.object_name = c.table_name
 WHERE o.object_name NOT LIKE '%$%'
   AND o.object_name NOT LIKE 'LOGMNR%_%'
   AND o.object_name NOT LIKE 'REDO_%'
@@ -868,8 +1346,9 @@ SELECT
     WHEN 'RAW' THEN 'RAW(' || c.data_length || ')'
     ELSE c.data_type END) AS data_type,
   CASE WHEN c.nullable = 'N' THEN '1' ELSE '0' END AS not_null,
-  CASE WHEN p.column_id IS NOT NULL THEN '1' ELSE '0' END as is_primary_key
-FROM all_tab_columns c
+  CASE WHEN p.column_id IS NOT NULL THEN '1' ELSE '0' END as is_primary_key,
+  CASE WHEN c.virtual_column = 'YES' THEN '1' ELSE '0' END AS is_generated
+FROM all_tab_cols c
   LEFT JOIN (
     SELECT distinct c.column_id FROM all_tab_columns c
     JOIN all_cons_columns l ON l.owner = c.owner
@@ -883,6 +1362,7 @@ FROM all_tab_columns c
   ) p on p.column_id = c.column_id
 WHERE c.owner = UPPER(%%schema string%%)
   AND c.table_name = UPPER(%%table string%%)
+  AND c.hidden_column = 'NO'
 ORDER BY c.column_id
 ENDSQL
 
@@ -901,6 +1381,7 @@ $XOBIN query $ORDB -M -B -2 -T ForeignKey -F OracleTableForeignKeys -a -o $DEST
 SELECT
   LOWER(a.constraint_name) AS foreign_key_name,
   LOWER(a.column_name) AS column_name,
+  LOWER(c_pk.owner) AS ref_schema_name,
   LOWER(c_pk.table_name) AS ref_table_name,
   LOWER(b.column_name) AS ref_column_name
 FROM user_cons_columns a
@@ -915,6 +1396,19 @@ WHERE c.constraint_type = 'R'
   AND a.table_name = UPPER(%%table string%%)
 ENDSQL
 
+# oracle table check constraint list query
+$XOBIN query $ORDB -M -B -2 -T Check -F OracleTableChecks -a -o $DEST $@ << ENDSQL
+SELECT
+  LOWER(constraint_name) AS check_name,
+  search_condition_vc AS check_expr
+FROM user_constraints
+WHERE constraint_type = 'C'
+  AND search_condition_vc NOT LIKE '"%" IS NOT NULL'
+  AND owner = UPPER(%%schema string%%)
+  AND table_name = UPPER(%%table string%%)
+ORDER BY constraint_name
+ENDSQL
+
 # oracle table index list query
 $XOBIN query $ORDB -M -B -2 -T Index -F OracleTableIndexes -a -o $DEST $@ << ENDSQL
 SELECT
@@ -3,6 +3,7 @@
 set -ex
 
 yaegi extract github.com/xo/xo/loader
+yaegi extract github.com/xo/xo/models
 yaegi extract github.com/xo/xo/types
 yaegi extract os/exec
 yaegi extract github.com/gobwas/glob
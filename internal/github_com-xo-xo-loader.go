@@ -10,38 +10,67 @@ import (
 func init() {
 	Symbols["github.com/xo/xo/loader/loader"] = map[string]reflect.Value{
 		// function, constant and variable definitions
-		"EnumValues":           reflect.ValueOf(loader.EnumValues),
-		"Enums":                reflect.ValueOf(loader.Enums),
-		"Flags":                reflect.ValueOf(loader.Flags),
-		"IndexColumns":         reflect.ValueOf(loader.IndexColumns),
-		"MysqlEnumValues":      reflect.ValueOf(loader.MysqlEnumValues),
-		"MysqlGoType":          reflect.ValueOf(loader.MysqlGoType),
-		"NthParam":             reflect.ValueOf(loader.NthParam),
-		"OracleGoType":         reflect.ValueOf(loader.OracleGoType),
-		"PQPostgresGoType":     reflect.ValueOf(loader.PQPostgresGoType),
-		"PostgresFlags":        reflect.ValueOf(loader.PostgresFlags),
-		"PostgresGoType":       reflect.ValueOf(loader.PostgresGoType),
-		"PostgresIndexColumns": reflect.ValueOf(loader.PostgresIndexColumns),
-		"PostgresTableColumns": reflect.ValueOf(loader.PostgresTableColumns),
-		"PostgresViewStrip":    reflect.ValueOf(loader.PostgresViewStrip),
-		"ProcParams":           reflect.ValueOf(loader.ProcParams),
-		"Procs":                reflect.ValueOf(loader.Procs),
-		"Register":             reflect.ValueOf(loader.Register),
-		"Schema":               reflect.ValueOf(loader.Schema),
-		"Sqlite3GoType":        reflect.ValueOf(loader.Sqlite3GoType),
-		"SqlserverGoType":      reflect.ValueOf(loader.SqlserverGoType),
-		"SqlserverViewStrip":   reflect.ValueOf(loader.SqlserverViewStrip),
-		"StdlibPostgresGoType": reflect.ValueOf(loader.StdlibPostgresGoType),
-		"TableColumns":         reflect.ValueOf(loader.TableColumns),
-		"TableForeignKeys":     reflect.ValueOf(loader.TableForeignKeys),
-		"TableIndexes":         reflect.ValueOf(loader.TableIndexes),
-		"TableSequences":       reflect.ValueOf(loader.TableSequences),
-		"Tables":               reflect.ValueOf(loader.Tables),
-		"ViewCreate":           reflect.ValueOf(loader.ViewCreate),
-		"ViewDrop":             reflect.ValueOf(loader.ViewDrop),
-		"ViewSchema":           reflect.ValueOf(loader.ViewSchema),
-		"ViewStrip":            reflect.ValueOf(loader.ViewStrip),
-		"ViewTruncate":         reflect.ValueOf(loader.ViewTruncate),
+		"CompositeAttrs":          reflect.ValueOf(loader.CompositeAttrs),
+		"Composites":              reflect.ValueOf(loader.Composites),
+		"DDL":                     reflect.ValueOf(loader.DDL),
+		"DSN":                     reflect.ValueOf(loader.DSN),
+		"DomainChecks":            reflect.ValueOf(loader.DomainChecks),
+		"Domains":                 reflect.ValueOf(loader.Domains),
+		"DuckdbGoType":            reflect.ValueOf(loader.DuckdbGoType),
+		"DuckdbIndexColumns":      reflect.ValueOf(loader.DuckdbIndexColumns),
+		"DuckdbQueryColumns":      reflect.ValueOf(loader.DuckdbQueryColumns),
+		"DuckdbTableColumns":      reflect.ValueOf(loader.DuckdbTableColumns),
+		"EnumValues":              reflect.ValueOf(loader.EnumValues),
+		"Enums":                   reflect.ValueOf(loader.Enums),
+		"Fingerprint":             reflect.ValueOf(loader.Fingerprint),
+		"Flags":                   reflect.ValueOf(loader.Flags),
+		"GoType":                  reflect.ValueOf(loader.GoType),
+		"IndexColumns":            reflect.ValueOf(loader.IndexColumns),
+		"Lookup":                  reflect.ValueOf(loader.Lookup),
+		"MaxParams":               reflect.ValueOf(loader.MaxParams),
+		"MysqlEnumValues":         reflect.ValueOf(loader.MysqlEnumValues),
+		"MysqlGoType":             reflect.ValueOf(loader.MysqlGoType),
+		"NthParam":                reflect.ValueOf(loader.NthParam),
+		"OracleGoType":            reflect.ValueOf(loader.OracleGoType),
+		"PQPostgresGoType":        reflect.ValueOf(loader.PQPostgresGoType),
+		"PgxPostgresGoType":       reflect.ValueOf(loader.PgxPostgresGoType),
+		"PostgresFingerprint":     reflect.ValueOf(loader.PostgresFingerprint),
+		"PostgresFlags":           reflect.ValueOf(loader.PostgresFlags),
+		"PostgresGoType":          reflect.ValueOf(loader.PostgresGoType),
+		"PostgresQueryColumns":    reflect.ValueOf(loader.PostgresQueryColumns),
+		"PostgresQueryParamTypes": reflect.ValueOf(loader.PostgresQueryParamTypes),
+		"PostgresTableColumns":    reflect.ValueOf(loader.PostgresTableColumns),
+		"PostgresViewStrip":       reflect.ValueOf(loader.PostgresViewStrip),
+		"ProcParams":              reflect.ValueOf(loader.ProcParams),
+		"Procs":                   reflect.ValueOf(loader.Procs),
+		"QueryColumns":            reflect.ValueOf(loader.QueryColumns),
+		"QueryNotNull":            reflect.ValueOf(loader.QueryNotNull),
+		"QueryParamTypes":         reflect.ValueOf(loader.QueryParamTypes),
+		"Register":                reflect.ValueOf(loader.Register),
+		"Schema":                  reflect.ValueOf(loader.Schema),
+		"Schemas":                 reflect.ValueOf(loader.Schemas),
+		"Sqlite3GoType":           reflect.ValueOf(loader.Sqlite3GoType),
+		"Sqlite3IndexColumns":     reflect.ValueOf(loader.Sqlite3IndexColumns),
+		"Sqlite3QueryColumns":     reflect.ValueOf(loader.Sqlite3QueryColumns),
+		"Sqlite3TableChecks":      reflect.ValueOf(loader.Sqlite3TableChecks),
+		"Sqlite3TableColumns":     reflect.ValueOf(loader.Sqlite3TableColumns),
+		"Sqlite3TableIndexes":     reflect.ValueOf(loader.Sqlite3TableIndexes),
+		"SqlserverGoType":         reflect.ValueOf(loader.SqlserverGoType),
+		"SqlserverViewStrip":      reflect.ValueOf(loader.SqlserverViewStrip),
+		"StdlibPostgresGoType":    reflect.ValueOf(loader.StdlibPostgresGoType),
+		"TableChecks":             reflect.ValueOf(loader.TableChecks),
+		"TableColumns":            reflect.ValueOf(loader.TableColumns),
+		"TableForeignKeys":        reflect.ValueOf(loader.TableForeignKeys),
+		"TableIndexes":            reflect.ValueOf(loader.TableIndexes),
+		"TableSequences":          reflect.ValueOf(loader.TableSequences),
+		"Tables":                  reflect.ValueOf(loader.Tables),
+		"ViewCreate":              reflect.ValueOf(loader.ViewCreate),
+		"ViewDrop":                reflect.ValueOf(loader.ViewDrop),
+		"ViewSchema":              reflect.ValueOf(loader.ViewSchema),
+		"ViewStrip":               reflect.ValueOf(loader.ViewStrip),
+		"ViewTruncate":            reflect.ValueOf(loader.ViewTruncate),
+		"WithDSN":                 reflect.ValueOf(loader.WithDSN),
+		"WithLoader":              reflect.ValueOf(loader.WithLoader),
 
 		// type definitions
 		"Loader": reflect.ValueOf((*loader.Loader)(nil)),
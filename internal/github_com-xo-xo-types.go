@@ -23,7 +23,10 @@ func init() {
 		"SingleKey":      reflect.ValueOf(types.SingleKey),
 
 		// type definitions
+		"Check":        reflect.ValueOf((*types.Check)(nil)),
+		"Composite":    reflect.ValueOf((*types.Composite)(nil)),
 		"ContextKey":   reflect.ValueOf((*types.ContextKey)(nil)),
+		"Domain":       reflect.ValueOf((*types.Domain)(nil)),
 		"Enum":         reflect.ValueOf((*types.Enum)(nil)),
 		"Field":        reflect.ValueOf((*types.Field)(nil)),
 		"Flag":         reflect.ValueOf((*types.Flag)(nil)),
@@ -0,0 +1,802 @@
+package loader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xo/xo/models"
+	xo "github.com/xo/xo/types"
+)
+
+// loaderKey is the context key for a loader that overrides the registered
+// loader for the context's driver.
+const loaderKey xo.ContextKey = "loader"
+
+// WithLoader returns a context that uses the loader in place of the
+// registered loader for the context's driver.
+func WithLoader(ctx context.Context, l Loader) context.Context {
+	return context.WithValue(ctx, loaderKey, l)
+}
+
+// dsnKey is the context key for the dsn of the database.
+const dsnKey xo.ContextKey = "dsn"
+
+// WithDSN returns a context with the dsn the database was opened with, used by
+// loaders that open their own connection to the database.
+func WithDSN(ctx context.Context, dsn string) context.Context {
+	return context.WithValue(ctx, dsnKey, dsn)
+}
+
+// DSN returns the dsn the context's database was opened with.
+func DSN(ctx context.Context) string {
+	dsn, _ := ctx.Value(dsnKey).(string)
+	return dsn
+}
+
+// DDL creates a loader for the driver that loads schema information from the
+// SQL DDL statements in the files instead of a database, using the registered
+// loader's mask, flags, and Go type mapping.
+//
+// The files' statements are parsed in order, with CREATE TABLE, CREATE INDEX, CREATE
+// VIEW, CREATE TYPE, CREATE DOMAIN, ALTER TABLE, DROP and COMMENT ON statements
+// applied to an in-memory catalog. Other statements are ignored.
+func DDL(driver string, files ...string) (Loader, error) {
+	l, ok := loaders[driver]
+	if !ok {
+		return Loader{}, fmt.Errorf("no database loader available for %q", driver)
+	}
+	cat := &ddlCatalog{
+		driver: driver,
+	}
+	for _, file := range files {
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			return Loader{}, err
+		}
+		if err := cat.parse(string(buf)); err != nil {
+			return Loader{}, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return Loader{
+		Type:             l.Type,
+		Mask:             l.Mask,
+		MaxParams:        l.MaxParams,
+		Flags:            l.Flags,
+		Schema:           cat.Schema,
+		Schemas:          cat.Schemas,
+		Enums:            cat.Enums,
+		EnumValues:       cat.EnumValues,
+		Domains:          cat.Domains,
+		DomainChecks:     cat.TableChecks,
+		Composites:       cat.Composites,
+		CompositeAttrs:   cat.TableColumns,
+		Tables:           cat.Tables,
+		TableColumns:     cat.TableColumns,
+		TableSequences:   cat.TableSequences,
+		TableForeignKeys: cat.TableForeignKeys,
+		TableIndexes:     cat.TableIndexes,
+		TableChecks:      cat.TableChecks,
+		IndexColumns:     cat.IndexColumns,
+		GoType:           l.GoType,
+	}, nil
+}
+
+// ddlCatalog is an in-memory schema catalog built from DDL statements.
+//
+// Objects with an empty schema were declared without a schema qualifier, and
+// belong to the default schema.
+type ddlCatalog struct {
+	driver string
+	def    string
+	enums  []*ddlEnum
+	tables []*ddlTable
+	load   func(string, string) *ddlTable // loads tables not in the catalog
+}
+
+// ddlEnum is a catalog enum.
+type ddlEnum struct {
+	schema string
+	name   string
+	values []string
+}
+
+// ddlTable is a catalog table, view, composite type, or domain.
+type ddlTable struct {
+	schema  string
+	name    string
+	typ     string
+	def     string
+	comment string
+	columns []*ddlColumn
+	indexes []*ddlIndex
+	fkeys   []*ddlForeignKey
+	checks  []*ddlCheck
+}
+
+// ddlColumn is a catalog column.
+type ddlColumn struct {
+	name      string
+	typ       string
+	notNull   bool
+	def       sql.NullString
+	primary   bool
+	sequence  bool
+	generated bool
+	comment   string
+}
+
+// ddlIndex is a catalog index.
+type ddlIndex struct {
+	name    string
+	cols    []string
+	exprs   []string // expression of each element, nil when all are columns
+	where   string   // partial index predicate
+	unique  bool
+	primary bool
+}
+
+// ddlForeignKey is a catalog foreign key.
+type ddlForeignKey struct {
+	name      string
+	cols      []string
+	refSchema string
+	refTable  string
+	refCols   []string
+}
+
+// ddlCheck is a catalog check constraint.
+type ddlCheck struct {
+	name string
+	expr string
+}
+
+// parse parses the DDL statements in src into the catalog.
+func (cat *ddlCatalog) parse(src string) error {
+	toks, err := ddlLex(cat.driver, src)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range ddlSplit(toks) {
+		p := &ddlParser{
+			cat:  cat,
+			src:  src,
+			toks: stmt,
+		}
+		if err := p.statement(); err != nil {
+			return fmt.Errorf("line %d: %w", ddlLine(src, stmt[0].pos), err)
+		}
+	}
+	return nil
+}
+
+// in returns true when an object in the object schema is in the schema.
+func (cat *ddlCatalog) in(objSchema, schema string) bool {
+	return objSchema == schema || objSchema == "" && (cat.def == "" || cat.def == schema)
+}
+
+// table returns the named table or view.
+func (cat *ddlCatalog) table(schema, name string) *ddlTable {
+	for _, t := range cat.tables {
+		if t.name == name && (schema == "" || t.schema == "" || t.schema == schema) {
+			return t
+		}
+	}
+	if cat.load != nil {
+		if t := cat.load(schema, name); t != nil {
+			cat.tables = append(cat.tables, t)
+			return t
+		}
+	}
+	return nil
+}
+
+// dropTable removes the named table or view.
+func (cat *ddlCatalog) dropTable(schema, name string) {
+	if t := cat.table(schema, name); t != nil {
+		for i := range cat.tables {
+			if cat.tables[i] == t {
+				cat.tables = append(cat.tables[:i], cat.tables[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// enum returns the named enum.
+func (cat *ddlCatalog) enum(schema, name string) *ddlEnum {
+	for _, e := range cat.enums {
+		if e.name == name && (schema == "" || e.schema == "" || e.schema == schema) {
+			return e
+		}
+	}
+	return nil
+}
+
+// dropEnum removes the named enum.
+func (cat *ddlCatalog) dropEnum(schema, name string) {
+	if e := cat.enum(schema, name); e != nil {
+		for i := range cat.enums {
+			if cat.enums[i] == e {
+				cat.enums = append(cat.enums[:i], cat.enums[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// lookup returns the table in the schema.
+func (cat *ddlCatalog) lookup(schema, name string) (*ddlTable, error) {
+	for _, t := range cat.tables {
+		if t.name == name && cat.in(t.schema, schema) {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("table %q not defined", name)
+}
+
+// Schema returns the default schema for the catalog.
+func (cat *ddlCatalog) Schema(context.Context, models.DB) (string, error) {
+	if cat.def != "" {
+		return cat.def, nil
+	}
+	// use the sole schema qualifier used by all objects
+	schemas := make(map[string]bool)
+	for _, t := range cat.tables {
+		schemas[t.schema] = true
+	}
+	for _, e := range cat.enums {
+		schemas[e.schema] = true
+	}
+	if len(schemas) == 1 && !schemas[""] {
+		for schema := range schemas {
+			return schema, nil
+		}
+	}
+	switch cat.driver {
+	case "postgres":
+		return "public", nil
+	case "sqlserver":
+		return "dbo", nil
+	case "sqlite3", "duckdb":
+		return "main", nil
+	}
+	return "", fmt.Errorf("unable to determine schema name for %s ddl (specify with --schema)", cat.driver)
+}
+
+// Schemas returns the catalog's schemas.
+func (cat *ddlCatalog) Schemas(ctx context.Context, db models.DB) ([]*models.Schema, error) {
+	def, err := cat.Schema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, t := range cat.tables {
+		seen[t.schema] = true
+	}
+	for _, e := range cat.enums {
+		seen[e.schema] = true
+	}
+	if seen[""] {
+		delete(seen, "")
+		seen[def] = true
+	}
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var schemas []*models.Schema
+	for _, name := range names {
+		schemas = append(schemas, &models.Schema{SchemaName: name})
+	}
+	return schemas, nil
+}
+
+// Enums returns the catalog's enums.
+func (cat *ddlCatalog) Enums(_ context.Context, _ models.DB, schema string) ([]*models.Enum, error) {
+	var enums []*models.Enum
+	for _, e := range cat.enums {
+		if cat.in(e.schema, schema) {
+			enums = append(enums, &models.Enum{
+				EnumName: e.name,
+			})
+		}
+	}
+	return enums, nil
+}
+
+// EnumValues returns the catalog's enum values.
+func (cat *ddlCatalog) EnumValues(_ context.Context, _ models.DB, schema, enum string) ([]*models.EnumValue, error) {
+	for _, e := range cat.enums {
+		if e.name != enum || !cat.in(e.schema, schema) {
+			continue
+		}
+		var values []*models.EnumValue
+		for i, v := range e.values {
+			values = append(values, &models.EnumValue{
+				EnumValue:  v,
+				ConstValue: i + 1,
+			})
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("enum %q not defined", enum)
+}
+
+// Domains returns the catalog's domains.
+func (cat *ddlCatalog) Domains(_ context.Context, _ models.DB, schema string) ([]*models.Domain, error) {
+	var domains []*models.Domain
+	for _, t := range cat.tables {
+		if t.typ == "domain" && cat.in(t.schema, schema) {
+			c := t.columns[0]
+			domains = append(domains, &models.Domain{
+				DomainName:   t.name,
+				DataType:     c.typ,
+				NotNull:      c.notNull,
+				DefaultValue: c.def.String,
+				Comment:      t.comment,
+			})
+		}
+	}
+	return domains, nil
+}
+
+// Composites returns the catalog's composite types.
+func (cat *ddlCatalog) Composites(_ context.Context, _ models.DB, schema string) ([]*models.Composite, error) {
+	var composites []*models.Composite
+	for _, t := range cat.tables {
+		if t.typ == "composite type" && cat.in(t.schema, schema) {
+			composites = append(composites, &models.Composite{
+				CompositeName: t.name,
+				Comment:       t.comment,
+			})
+		}
+	}
+	return composites, nil
+}
+
+// Tables returns the catalog's tables or views.
+func (cat *ddlCatalog) Tables(_ context.Context, _ models.DB, schema, typ string) ([]*models.Table, error) {
+	var tables []*models.Table
+	for _, t := range cat.tables {
+		if strings.HasSuffix(t.typ, typ) && cat.in(t.schema, schema) {
+			tables = append(tables, &models.Table{
+				Type:      t.typ,
+				TableName: t.name,
+				ViewDef:   t.def,
+				Comment:   t.comment,
+			})
+		}
+	}
+	return tables, nil
+}
+
+// TableColumns returns the catalog's table columns.
+func (cat *ddlCatalog) TableColumns(_ context.Context, _ models.DB, schema, table string) ([]*models.Column, error) {
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	var cols []*models.Column
+	for i, c := range t.columns {
+		ordinal := i + 1
+		if cat.driver == "sqlite3" {
+			ordinal = i
+		}
+		cols = append(cols, &models.Column{
+			FieldOrdinal: ordinal,
+			ColumnName:   c.name,
+			DataType:     c.typ,
+			NotNull:      c.notNull,
+			DefaultValue: c.def,
+			IsPrimaryKey: c.primary,
+			IsGenerated:  c.generated,
+			Comment:      sql.NullString{String: c.comment, Valid: c.comment != ""},
+		})
+	}
+	return cols, nil
+}
+
+// TableSequences returns the catalog's table sequences.
+func (cat *ddlCatalog) TableSequences(_ context.Context, _ models.DB, schema, table string) ([]*models.Sequence, error) {
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	var seqs []*models.Sequence
+	for _, c := range t.columns {
+		if c.sequence {
+			seqs = append(seqs, &models.Sequence{
+				ColumnName: c.name,
+			})
+		}
+	}
+	return seqs, nil
+}
+
+// TableForeignKeys returns the catalog's table foreign keys.
+func (cat *ddlCatalog) TableForeignKeys(_ context.Context, _ models.DB, schema, table string) ([]*models.ForeignKey, error) {
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	var fkeys []*models.ForeignKey
+	for i, fk := range t.fkeys {
+		// unqualified references are to the default schema, if any
+		refSchema := fk.refSchema
+		if refSchema == "" {
+			refSchema = cat.def
+		}
+		refCols := fk.refCols
+		// default to the referenced table's primary key
+		if refCols == nil {
+			if ref := cat.table(fk.refSchema, fk.refTable); ref != nil {
+				for _, index := range ref.indexes {
+					if index.primary {
+						refCols = index.cols
+					}
+				}
+			}
+		}
+		for j, col := range fk.cols {
+			var refCol string
+			if j < len(refCols) {
+				refCol = refCols[j]
+			}
+			fkeys = append(fkeys, &models.ForeignKey{
+				ForeignKeyName: fk.name,
+				ColumnName:     col,
+				RefSchemaName:  refSchema,
+				RefTableName:   fk.refTable,
+				RefColumnName:  refCol,
+				KeyID:          i,
+			})
+		}
+	}
+	return fkeys, nil
+}
+
+// TableIndexes returns the catalog's table indexes.
+func (cat *ddlCatalog) TableIndexes(_ context.Context, _ models.DB, schema, table string) ([]*models.Index, error) {
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []*models.Index
+	for _, index := range t.allIndexes(cat) {
+		switch {
+		case index.primary && cat.driver == "mysql":
+			continue
+		case index.primary && cat.driver == "sqlite3" && t.rowid():
+			continue
+		}
+		indexes = append(indexes, &models.Index{
+			IndexName: index.name,
+			IsUnique:  index.unique,
+			IsPrimary: index.primary && cat.driver != "oracle",
+			Predicate: index.where,
+		})
+	}
+	return indexes, nil
+}
+
+// TableChecks returns the catalog's table check constraints.
+func (cat *ddlCatalog) TableChecks(_ context.Context, _ models.DB, schema, table string) ([]*models.Check, error) {
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	var checks []*models.Check
+	for _, c := range t.checks {
+		checks = append(checks, &models.Check{
+			CheckName: c.name,
+			CheckExpr: c.expr,
+		})
+	}
+	return checks, nil
+}
+
+// IndexColumns returns the catalog's index columns.
+func (cat *ddlCatalog) IndexColumns(_ context.Context, _ models.DB, schema, table, index string) ([]*models.IndexColumn, error) {
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, ix := range t.allIndexes(cat) {
+		if ix.name != index {
+			continue
+		}
+		var cols []*models.IndexColumn
+		for i, name := range ix.cols {
+			cid := -1
+			for j, c := range t.columns {
+				if c.name == name {
+					cid = j + 1
+				}
+			}
+			col := &models.IndexColumn{
+				SeqNo:      i + 1,
+				Cid:        cid,
+				ColumnName: name,
+			}
+			if ix.exprs != nil {
+				col.Expr = ix.exprs[i]
+			}
+			cols = append(cols, col)
+		}
+		return cols, nil
+	}
+	return nil, fmt.Errorf("table %q index %q not defined", table, index)
+}
+
+// column returns the named column.
+func (t *ddlTable) column(name string) *ddlColumn {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// index returns the named index.
+func (t *ddlTable) index(name string) *ddlIndex {
+	for _, index := range t.indexes {
+		if index.name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// rowid returns true when the table's primary key is a sqlite3 rowid alias.
+func (t *ddlTable) rowid() bool {
+	for _, index := range t.indexes {
+		if index.primary {
+			return t.rowidCols(index.cols)
+		}
+	}
+	return false
+}
+
+// rowidCols returns true when the primary key columns are a sqlite3 rowid
+// alias.
+func (t *ddlTable) rowidCols(cols []string) bool {
+	if len(cols) != 1 {
+		return false
+	}
+	c := t.column(cols[0])
+	return c != nil && strings.EqualFold(c.typ, "integer")
+}
+
+// setPrimary sets the table's primary key.
+func (t *ddlTable) setPrimary(cat *ddlCatalog, name string, cols []string) {
+	for _, col := range cols {
+		if c := t.column(col); c != nil {
+			c.primary = true
+			c.notNull = c.notNull || cat.driver != "sqlite3"
+		}
+	}
+	t.addIndex(cat, name, cols, nil, true, true)
+}
+
+// addIndex adds an index to the table, naming it the way the driver's
+// database names implicit indexes when no name was provided.
+func (t *ddlTable) addIndex(cat *ddlCatalog, name string, cols, exprs []string, unique, primary bool) *ddlIndex {
+	switch {
+	case cat.driver == "mysql" && primary:
+		name = "PRIMARY"
+	case cat.driver == "mysql" && name == "" && cols[0] == "":
+		name = t.uniqueName("functional_index", "_")
+	case cat.driver == "mysql" && name == "":
+		name = t.uniqueName(cols[0], "_")
+	case cat.driver == "sqlite3" && primary && t.rowidCols(cols):
+		// rowid aliases are not indexed
+		name = t.name + "_pkey"
+	case cat.driver == "sqlite3" && (primary || unique):
+		var n int
+		for _, index := range t.indexes {
+			if strings.HasPrefix(index.name, "sqlite_autoindex_") {
+				n++
+			}
+		}
+		if name == "" || unique {
+			name = "sqlite_autoindex_" + t.name + "_" + strconv.Itoa(n+1)
+		}
+	case name == "" && primary:
+		name = t.uniqueName(t.name+"_pkey", "")
+	case name == "" && unique:
+		name = t.uniqueName(t.name+"_"+strings.Join(ddlIndexNames(cols, exprs), "_")+"_key", "")
+	case name == "":
+		name = t.uniqueName(t.name+"_"+strings.Join(ddlIndexNames(cols, exprs), "_")+"_idx", "")
+	}
+	index := &ddlIndex{
+		name:    name,
+		cols:    cols,
+		unique:  unique,
+		primary: primary,
+	}
+	if strings.Join(exprs, "") != "" {
+		index.exprs = exprs
+	}
+	t.indexes = append(t.indexes, index)
+	return index
+}
+
+// ddlIndexNames returns the names postgres uses for index elements when
+// naming an index, being the column name, or the function name of an
+// expression.
+func ddlIndexNames(cols, exprs []string) []string {
+	if exprs == nil {
+		return cols
+	}
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		switch m := ddlFuncRE.FindStringSubmatch(exprs[i]); {
+		case col != "":
+			names[i] = col
+		case m != nil:
+			names[i] = strings.ToLower(m[1])
+		default:
+			names[i] = "expr"
+		}
+	}
+	return names
+}
+
+// ddlFuncRE matches a function call expression.
+var ddlFuncRE = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+
+// uniqueName returns a unique index name based on name.
+func (t *ddlTable) uniqueName(name, sep string) string {
+	if t.index(name) == nil {
+		return name
+	}
+	start := 1
+	if sep != "" {
+		start = 2
+	}
+	for i := start; ; i++ {
+		if s := name + sep + strconv.Itoa(i); t.index(s) == nil {
+			return s
+		}
+	}
+}
+
+// allIndexes returns the table's indexes, including the implicit indexes
+// that mysql creates for foreign keys.
+func (t *ddlTable) allIndexes(cat *ddlCatalog) []*ddlIndex {
+	if cat.driver != "mysql" {
+		return t.indexes
+	}
+	indexes := t.indexes
+loop:
+	for _, fk := range t.fkeys {
+		// skip when an existing index starts with the foreign key columns
+		for _, index := range indexes {
+			if len(index.cols) >= len(fk.cols) && strings.Join(index.cols[:len(fk.cols)], ",") == strings.Join(fk.cols, ",") {
+				continue loop
+			}
+		}
+		name := fk.name
+		if strings.HasPrefix(name, t.name+"_ibfk_") {
+			name = fk.cols[0]
+		}
+		indexes = append(indexes, &ddlIndex{
+			name: name,
+			cols: fk.cols,
+		})
+	}
+	return indexes
+}
+
+// addForeignKey adds a foreign key to the table, naming it the way the
+// driver's database names foreign keys when no name was provided.
+func (t *ddlTable) addForeignKey(cat *ddlCatalog, fk *ddlForeignKey) {
+	if fk.name == "" {
+		switch cat.driver {
+		case "mysql":
+			fk.name = t.name + "_ibfk_" + strconv.Itoa(len(t.fkeys)+1)
+		case "sqlite3":
+		default:
+			fk.name = t.name + "_" + strings.Join(fk.cols, "_") + "_fkey"
+		}
+	}
+	t.fkeys = append(t.fkeys, fk)
+}
+
+// addCheck adds a check constraint to the table, naming it the way the
+// driver's database names check constraints when no name was provided.
+func (t *ddlTable) addCheck(cat *ddlCatalog, name, col, expr string) {
+	if name == "" {
+		switch cat.driver {
+		case "postgres":
+			name = t.name + "_check"
+			if col != "" {
+				name = t.name + "_" + col + "_check"
+			}
+			for i := 1; t.check(name) != nil; i++ {
+				name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+			}
+		case "mysql":
+			name = t.name + "_chk_" + strconv.Itoa(len(t.checks)+1)
+		}
+	}
+	t.checks = append(t.checks, &ddlCheck{
+		name: name,
+		expr: expr,
+	})
+}
+
+// check returns the named check constraint.
+func (t *ddlTable) check(name string) *ddlCheck {
+	for _, c := range t.checks {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// dropConstraint removes the named index, foreign key, or check constraint.
+func (t *ddlTable) dropConstraint(name string) {
+	for i, index := range t.indexes {
+		if index.name == name {
+			t.indexes = append(t.indexes[:i], t.indexes[i+1:]...)
+			return
+		}
+	}
+	for i, fk := range t.fkeys {
+		if fk.name == name {
+			t.fkeys = append(t.fkeys[:i], t.fkeys[i+1:]...)
+			return
+		}
+	}
+	for i, c := range t.checks {
+		if c.name == name {
+			t.checks = append(t.checks[:i], t.checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// dropColumn removes the named column.
+func (t *ddlTable) dropColumn(name string) {
+	for i, c := range t.columns {
+		if c.name == name {
+			t.columns = append(t.columns[:i], t.columns[i+1:]...)
+			return
+		}
+	}
+}
+
+// setDefault sets the column's default value as reported by the driver's
+// catalog. Postgres nextval defaults mark the column as a sequence.
+func (c *ddlColumn) setDefault(driver string, toks []ddlToken, def string) {
+	switch {
+	case driver == "postgres" && len(toks) == 1 && toks[0].typ == ddlString:
+		// postgres casts string literals to the column type
+		def += "::" + ddlArgsRE.ReplaceAllString(c.typ, "")
+	case driver == "sqlite3" && len(toks) > 1 && toks[0].val == "(" && toks[len(toks)-1].val == ")":
+		// sqlite3 strips the parens of default expressions
+		def = strings.TrimSpace(def[1 : len(def)-1])
+	}
+	c.def = sql.NullString{String: def, Valid: !strings.EqualFold(def, "NULL")}
+	if strings.HasPrefix(strings.ToLower(def), "nextval(") {
+		c.sequence = true
+	}
+}
+
+// ddlArgsRE matches the arguments of a data type.
+var ddlArgsRE = regexp.MustCompile(`\([^)]*\)`)
+
+// ddlWarn writes a warning to stderr.
+func ddlWarn(s string, v ...interface{}) {
+	fmt.Fprintf(os.Stderr, "WARNING: "+s+"\n", v...)
+}
@@ -0,0 +1,543 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xo/xo/models"
+	xo "github.com/xo/xo/types"
+)
+
+func TestDDL(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		ddl    string
+		exp    []string
+	}{
+		{
+			name:   "postgres",
+			driver: "postgres",
+			ddl: `CREATE TYPE book_type AS ENUM ('FICTION', 'NONFICTION');
+CREATE DOMAIN isbn AS text NOT NULL CHECK (VALUE <> '');
+COMMENT ON DOMAIN isbn IS 'Book numbers.';
+CREATE TYPE price AS (amount numeric(10, 2), currency char(3));
+CREATE TABLE authors (
+  author_id SERIAL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL DEFAULT '' CHECK (name <> ''),
+  CONSTRAINT authors_name_len CHECK (length(name) < 100)
+);
+CREATE INDEX authors_name_idx ON authors (name);
+CREATE UNIQUE INDEX ON authors USING btree (lower(name) DESC) WHERE name <> '';
+COMMENT ON TABLE authors IS 'Book authors.';
+CREATE TABLE public.books (
+  book_id integer GENERATED ALWAYS AS IDENTITY,
+  author_id INTEGER NOT NULL REFERENCES authors,
+  isbn text UNIQUE,
+  isbn_upper text GENERATED ALWAYS AS (upper(isbn)) STORED,
+  book_type book_type,
+  available TIMESTAMPTZ(3) DEFAULT now(),
+  tags VARCHAR[]
+);
+ALTER TABLE ONLY public.books ADD CONSTRAINT books_pkey PRIMARY KEY (book_id);
+CREATE VIEW book_authors AS
+  SELECT b.book_id, a.name AS author, count(*)::integer AS n
+  FROM (books b JOIN authors a ON a.author_id = b.author_id);
+CREATE TABLE events (
+  event_id bigint NOT NULL,
+  created_at timestamptz NOT NULL
+) PARTITION BY RANGE (created_at);
+CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+CREATE FOREIGN TABLE remote_authors (
+  author_id integer NOT NULL
+) SERVER remote OPTIONS (table_name 'authors');
+CREATE MATERIALIZED VIEW author_names AS
+  SELECT author_id, name FROM authors
+WITH NO DATA;
+CREATE UNIQUE INDEX ON author_names (author_id);`,
+			exp: []string{
+				"schema public",
+				"enum book_type FICTION:1 NONFICTION:2",
+				"domain isbn text not null -- Book numbers.",
+				"  check isbn_check VALUE <> ''",
+				"composite type price",
+				"  column amount numeric(10,2)",
+				"  column currency character(3)",
+				"table authors -- Book authors.",
+				"  column author_id integer not null pk",
+				"  column name character varying(255) not null default ''::character varying",
+				"  sequence author_id",
+				"  index authors_pkey [author_id] unique primary",
+				"  index authors_name_idx [name]",
+				"  index authors_lower_idx [(lower(name))] unique where name <> ''",
+				"  check authors_name_check name <> ''",
+				"  check authors_name_len length(name) < 100",
+				"table books",
+				"  column book_id integer not null pk",
+				"  column author_id integer not null",
+				"  column isbn text",
+				"  column isbn_upper text default upper(isbn) generated",
+				"  column book_type book_type",
+				"  column available timestamp(3) with time zone default now()",
+				"  column tags character varying[]",
+				"  sequence book_id",
+				"  index books_isbn_key [isbn] unique",
+				"  index books_pkey [book_id] unique primary",
+				"  fk books_author_id_fkey author_id authors.author_id",
+				"partitioned table events RANGE (created_at)",
+				"  column event_id bigint not null",
+				"  column created_at timestamp with time zone not null",
+				"foreign table remote_authors SERVER remote OPTIONS (table_name 'authors')",
+				"  column author_id integer not null",
+				"view book_authors",
+				"  column book_id integer",
+				"  column author character varying(255)",
+				"  column n integer",
+				"materialized view author_names",
+				"  column author_id integer",
+				"  column name character varying(255)",
+				"  index author_names_author_id_idx [author_id] unique",
+			},
+		},
+		{
+			name:   "mysql",
+			driver: "mysql",
+			ddl: "USE booktest;\n" +
+				"CREATE TABLE `authors` (\n" +
+				"  `author_id` INTEGER AUTO_INCREMENT NOT NULL PRIMARY KEY,\n" +
+				"  `name` VARCHAR(255) DEFAULT '' NOT NULL,\n" +
+				"  INDEX ((lower(`name`)))\n" +
+				") ENGINE=InnoDB COMMENT='Book authors.';\n" +
+				"CREATE TABLE `books` (\n" +
+				"  `book_id` INT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"  `author_id` INTEGER NOT NULL,\n" +
+				"  `book_type` ENUM('FICTION', 'NONFICTION') DEFAULT 'FICTION' NOT NULL,\n" +
+				"  `author_id2` INTEGER AS (`author_id` * 2) VIRTUAL,\n" +
+				"  PRIMARY KEY (`book_id`),\n" +
+				"  CHECK (`author_id` > 0),\n" +
+				"  UNIQUE KEY (`author_id`, `book_type`),\n" +
+				"  FOREIGN KEY (`author_id`) REFERENCES `authors` (`author_id`)\n" +
+				") ENGINE=InnoDB;\n",
+			exp: []string{
+				"schema booktest",
+				"enum book_type FICTION:1 NONFICTION:2",
+				"table authors -- Book authors.",
+				"  column author_id int not null pk",
+				"  column name varchar(255) not null default ''",
+				"  sequence author_id",
+				"  index functional_index [(lower(`name`))]",
+				"table books",
+				"  column book_id int unsigned not null pk",
+				"  column author_id int not null",
+				"  column book_type book_type not null default 'FICTION'",
+				"  column author_id2 int default `author_id` * 2 generated",
+				"  sequence book_id",
+				"  index author_id [author_id book_type] unique",
+				"  fk books_ibfk_1 author_id authors.author_id",
+				"  check books_chk_1 `author_id` > 0",
+			},
+		},
+		{
+			name:   "sqlite3",
+			driver: "sqlite3",
+			ddl: `CREATE TABLE authors (
+  author_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(255) DEFAULT (UPPER('')) NOT NULL
+);
+CREATE TABLE books (
+  isbn TEXT PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  title TEXT UNIQUE CHECK (length(title) > 0)
+);
+CREATE UNIQUE INDEX books_lower_title ON books (lower(title) COLLATE NOCASE) WHERE author_id > 0;`,
+			exp: []string{
+				"schema main",
+				"table authors",
+				"  column author_id INTEGER not null pk",
+				"  column name VARCHAR(255) not null default UPPER('')",
+				"  sequence author_id",
+				"table books",
+				"  column isbn TEXT pk",
+				"  column author_id INTEGER not null",
+				"  column title TEXT",
+				"  index sqlite_autoindex_books_1 [isbn] unique primary",
+				"  index sqlite_autoindex_books_2 [title] unique",
+				"  index books_lower_title [(lower(title))] unique where author_id > 0",
+				"  fk  author_id authors.author_id",
+				"  check  length(title) > 0",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "schema.sql")
+			if err := os.WriteFile(file, []byte(test.ddl), 0o644); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			l, err := DDL(test.driver, file)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			lines, err := ddlDump(l, nil)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if s, exp := strings.Join(lines, "\n"), strings.Join(test.exp, "\n"); s != exp {
+				t.Errorf("expected:\n%s\ngot:\n%s", exp, s)
+			}
+		})
+	}
+}
+
+// ddlDump dumps the schema of the loader, skipping the kinds of objects the
+// loader does not support.
+func ddlDump(l Loader, db models.DB) ([]string, error) {
+	ctx := context.Background()
+	if l.Enums == nil {
+		l.Enums = func(context.Context, models.DB, string) ([]*models.Enum, error) { return nil, nil }
+	}
+	if l.Domains == nil {
+		l.Domains = func(context.Context, models.DB, string) ([]*models.Domain, error) { return nil, nil }
+	}
+	if l.Composites == nil {
+		l.Composites = func(context.Context, models.DB, string) ([]*models.Composite, error) { return nil, nil }
+	}
+	schema, err := l.Schema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{"schema " + schema}
+	enums, err := l.Enums(ctx, db, schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, enum := range enums {
+		values, err := l.EnumValues(ctx, db, schema, enum.EnumName)
+		if err != nil {
+			return nil, err
+		}
+		s := "enum " + enum.EnumName
+		for _, v := range values {
+			s += fmt.Sprintf(" %s:%d", v.EnumValue, v.ConstValue)
+		}
+		lines = append(lines, s)
+	}
+	domains, err := l.Domains(ctx, db, schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		s := "domain " + domain.DomainName + " " + domain.DataType
+		if domain.NotNull {
+			s += " not null"
+		}
+		if domain.DefaultValue != "" {
+			s += " default " + domain.DefaultValue
+		}
+		if domain.Comment != "" {
+			s += " -- " + domain.Comment
+		}
+		lines = append(lines, s)
+		checks, err := l.DomainChecks(ctx, db, schema, domain.DomainName)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range checks {
+			lines = append(lines, fmt.Sprintf("  check %s %s", c.CheckName, c.CheckExpr))
+		}
+	}
+	composites, err := l.Composites(ctx, db, schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, composite := range composites {
+		lines = append(lines, "composite type "+composite.CompositeName)
+		attrs, err := l.CompositeAttrs(ctx, db, schema, composite.CompositeName)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attrs {
+			lines = append(lines, "  column "+a.ColumnName+" "+a.DataType)
+		}
+	}
+	for _, typ := range []string{"table", "view"} {
+		tables, err := l.Tables(ctx, db, schema, typ)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			s := table.Type + " " + table.TableName
+			if typ == "table" && table.ViewDef != "" {
+				s += " " + table.ViewDef
+			}
+			if table.Comment != "" {
+				s += " -- " + table.Comment
+			}
+			lines = append(lines, s)
+			cols, err := l.TableColumns(ctx, db, schema, table.TableName)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range cols {
+				s := "  column " + c.ColumnName + " " + c.DataType
+				if c.NotNull {
+					s += " not null"
+				}
+				if c.DefaultValue.Valid {
+					s += " default " + c.DefaultValue.String
+				}
+				if c.IsPrimaryKey {
+					s += " pk"
+				}
+				if c.IsGenerated {
+					s += " generated"
+				}
+				lines = append(lines, s)
+			}
+			seqs, err := l.TableSequences(ctx, db, schema, table.TableName)
+			if err != nil {
+				return nil, err
+			}
+			for _, seq := range seqs {
+				lines = append(lines, "  sequence "+seq.ColumnName)
+			}
+			indexes, err := l.TableIndexes(ctx, db, schema, table.TableName)
+			if err != nil {
+				return nil, err
+			}
+			for _, index := range indexes {
+				cols, err := l.IndexColumns(ctx, db, schema, table.TableName, index.IndexName)
+				if err != nil {
+					return nil, err
+				}
+				var names []string
+				for _, c := range cols {
+					name := c.ColumnName
+					if c.Expr != "" {
+						name = "(" + c.Expr + ")"
+					}
+					names = append(names, name)
+				}
+				s := fmt.Sprintf("  index %s %v", index.IndexName, names)
+				if index.IsUnique {
+					s += " unique"
+				}
+				if index.IsPrimary {
+					s += " primary"
+				}
+				if index.Predicate != "" {
+					s += " where " + index.Predicate
+				}
+				lines = append(lines, s)
+			}
+			fkeys, err := l.TableForeignKeys(ctx, db, schema, table.TableName)
+			if err != nil {
+				return nil, err
+			}
+			for _, fk := range fkeys {
+				lines = append(lines, fmt.Sprintf("  fk %s %s %s.%s", fk.ForeignKeyName, fk.ColumnName, fk.RefTableName, fk.RefColumnName))
+			}
+			checks, err := l.TableChecks(ctx, db, schema, table.TableName)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range checks {
+				lines = append(lines, fmt.Sprintf("  check %s %s", c.CheckName, c.CheckExpr))
+			}
+		}
+	}
+	return lines, nil
+}
+
+func TestQueryNotNull(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.sql")
+	ddl := `CREATE TABLE authors (
+  author_id INTEGER NOT NULL PRIMARY KEY,
+  name TEXT NOT NULL,
+  bio TEXT
+);
+CREATE TABLE books (
+  book_id INTEGER NOT NULL PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  title TEXT NOT NULL
+);`
+	if err := os.WriteFile(file, []byte(ddl), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	l, err := DDL("sqlite3", file)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), xo.DriverKey, "sqlite3")
+	ctx = WithLoader(ctx, l)
+	tests := []struct {
+		query string
+		exp   []bool
+	}{
+		{`SELECT author_id, bio, a.name AS n FROM authors a`, []bool{true, false, true}},
+		{`SELECT * FROM authors`, []bool{true, true, false}},
+		{`SELECT a.name, b.title FROM authors a LEFT JOIN books b ON b.author_id = a.author_id`, []bool{true, false}},
+		{`SELECT a.name, b.title FROM authors a RIGHT OUTER JOIN books b ON b.author_id = a.author_id`, []bool{false, true}},
+		{`SELECT a.name, b.title FROM authors a FULL JOIN books b USING (author_id)`, []bool{false, false}},
+		{`SELECT a.name FROM (authors a JOIN books b ON b.author_id = a.author_id)`, []bool{true}},
+		{`SELECT b.title FROM authors a LEFT JOIN (books b JOIN authors c ON c.author_id = b.author_id) ON b.author_id = a.author_id`, []bool{false}},
+		{`SELECT count(*) AS n, 1 AS one, COALESCE(bio, '') AS bio, (name) AS x, bio || name AS y, CAST(name AS TEXT) AS z FROM authors`, []bool{true, true, true, true, false, true}},
+		{`INSERT INTO authors (name) VALUES ('a') RETURNING author_id, bio`, []bool{true, false}},
+		{`UPDATE authors AS a SET bio = NULL RETURNING a.name`, []bool{true}},
+	}
+	for i, test := range tests {
+		notNull, err := QueryNotNull(ctx, []string{test.query})
+		switch {
+		case err != nil:
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		case fmt.Sprint(notNull) != fmt.Sprint(test.exp):
+			t.Errorf("test %d expected %v, got: %v", i, test.exp, notNull)
+		}
+	}
+	if _, err := QueryNotNull(ctx, []string{`SELECT name FROM authors UNION SELECT title FROM books`}); err == nil {
+		t.Errorf("expected error for UNION")
+	}
+}
+
+func TestQueryParamTypes(t *testing.T) {
+	tests := []struct {
+		driver string
+		ddl    string
+		query  string
+		exp    []string
+	}{
+		{"sqlite3", "CREATE TABLE authors (author_id INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL, born DATE);", `SELECT * FROM authors a WHERE a.author_id = $1 AND $2 <> name AND born BETWEEN $3 AND $4 LIMIT $5 OFFSET $6`, []string{"INTEGER", "TEXT", "DATE", "DATE", "integer", "integer"}},
+		{"sqlite3", "CREATE TABLE authors (author_id INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL, born DATE);", `SELECT * FROM authors WHERE author_id NOT IN ($1, $2) AND name NOT LIKE $3 AND lower(name) = $4 AND born = CAST($5 AS TEXT)`, []string{"INTEGER", "INTEGER", "TEXT", "", "TEXT"}},
+		{"sqlite3", "CREATE TABLE authors (author_id INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL, born DATE);", `INSERT INTO authors (name, born) VALUES ($1, $2), ($3, date($4))`, []string{"TEXT", "DATE", "TEXT", ""}},
+		{"sqlite3", "CREATE TABLE authors (author_id INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL, born DATE);", `UPDATE authors SET name = $2, born = NULL WHERE author_id = $1`, []string{"INTEGER", "TEXT"}},
+		{"mysql", "CREATE TABLE books (book_id INT NOT NULL PRIMARY KEY, title VARCHAR(255) NOT NULL, kind ENUM('fiction', 'other') NOT NULL);", `SELECT * FROM books b WHERE b.kind = ? AND title = ? LIMIT ?, ?`, []string{"kind", "varchar(255)", "int", "int"}},
+		{"mysql", "CREATE TABLE books (book_id INT NOT NULL PRIMARY KEY, title VARCHAR(255) NOT NULL, kind ENUM('fiction', 'other') NOT NULL);", `INSERT INTO books VALUES (?, ?, ?)`, []string{"int", "varchar(255)", "kind"}},
+		{"mysql", "CREATE TABLE books (book_id INT NOT NULL PRIMARY KEY, title VARCHAR(255) NOT NULL, kind ENUM('fiction', 'other') NOT NULL);", `DELETE FROM books WHERE book_id IN (SELECT book_id FROM sales WHERE region = ?)`, []string{""}},
+	}
+	for i, test := range tests {
+		file := filepath.Join(t.TempDir(), "schema.sql")
+		if err := os.WriteFile(file, []byte(test.ddl), 0o644); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		l, err := DDL(test.driver, file)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		ctx := context.WithValue(context.Background(), xo.DriverKey, test.driver)
+		ctx = WithLoader(ctx, l)
+		typs, err := QueryParamTypes(ctx, test.query)
+		switch {
+		case err != nil:
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		case strings.Join(typs, ",") != strings.Join(test.exp, ","):
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, typs)
+		}
+	}
+}
+
+func TestDDLComputedColumns(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.sql")
+	ddl := `CREATE TABLE authors (
+  author_id INT IDENTITY(1, 1) PRIMARY KEY,
+  name NVARCHAR(255) NOT NULL,
+  name_len AS LEN(name) PERSISTED,
+  name_upper AS (UPPER(name)),
+  id_copy AS author_id,
+  id_big AS CAST(author_id AS BIGINT) PERSISTED NOT NULL,
+  id_text AS CONVERT(VARCHAR(20), author_id),
+  unknown AS author_id * 2,
+  born DATE
+);`
+	if err := os.WriteFile(file, []byte(ddl), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	l, err := DDL("sqlserver", file)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	lines, err := ddlDump(l, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := []string{
+		"schema dbo",
+		"table authors",
+		"  column author_id int(10) not null pk",
+		"  column name nvarchar(255) not null",
+		"  column name_len int(10) default LEN(name) generated",
+		"  column name_upper nvarchar default (UPPER(name)) generated",
+		"  column id_copy int(10) default author_id generated",
+		"  column id_big bigint(19) not null default CAST(author_id AS BIGINT) generated",
+		"  column id_text varchar(20) default CONVERT(VARCHAR(20), author_id) generated",
+		"  column born date",
+		"  sequence author_id",
+		"  index authors_pkey [author_id] unique primary",
+	}
+	if s, exp := strings.Join(lines, "\n"), strings.Join(exp, "\n"); s != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, s)
+	}
+}
+
+func TestDDLAlterType(t *testing.T) {
+	tests := []struct {
+		ddl string
+		exp []string
+		err string
+	}{
+		{`CREATE TYPE book_type AS ENUM ('FICTION', 'NONFICTION');
+ALTER TYPE book_type ADD VALUE 'POETRY';
+ALTER TYPE book_type ADD VALUE IF NOT EXISTS 'POETRY';
+ALTER TYPE public.book_type ADD VALUE 'ESSAY' BEFORE 'NONFICTION';
+ALTER TYPE book_type ADD VALUE 'DRAMA' AFTER 'FICTION';
+ALTER TYPE book_type RENAME VALUE 'NONFICTION' TO 'NON_FICTION';
+CREATE TABLE books (
+  book_id SERIAL PRIMARY KEY,
+  book_type book_type NOT NULL
+);
+ALTER TYPE book_type RENAME TO genre;
+ALTER TYPE genre OWNER TO postgres;`, []string{
+			"schema public",
+			"enum genre FICTION:1 DRAMA:2 ESSAY:3 NON_FICTION:4 POETRY:5",
+			"table books",
+			"  column book_id integer not null pk",
+			"  column book_type genre not null",
+			"  sequence book_id",
+			"  index books_pkey [book_id] unique primary",
+		}, ""},
+		{`ALTER TYPE book_type ADD VALUE 'POETRY';`, nil, `unknown enum "book_type"`},
+		{`CREATE TYPE book_type AS ENUM ('FICTION');
+ALTER TYPE book_type ADD VALUE 'FICTION';`, nil, `enum "book_type" value "FICTION" already exists`},
+		{`CREATE TYPE book_type AS ENUM ('FICTION');
+ALTER TYPE book_type ADD VALUE 'POETRY' AFTER 'DRAMA';`, nil, `enum "book_type" has no value "DRAMA"`},
+	}
+	for i, test := range tests {
+		file := filepath.Join(t.TempDir(), "schema.sql")
+		if err := os.WriteFile(file, []byte(test.ddl), 0o644); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		l, err := DDL("postgres", file)
+		switch {
+		case test.err != "" && err == nil:
+			t.Errorf("test %d expected error %q", i, test.err)
+			continue
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("test %d expected error %q, got: %v", i, test.err, err)
+			continue
+		case test.err != "":
+			continue
+		case err != nil:
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		lines, err := ddlDump(l, nil)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if s, exp := strings.Join(lines, "\n"), strings.Join(test.exp, "\n"); s != exp {
+			t.Errorf("test %d expected:\n%s\ngot:\n%s", i, exp, s)
+		}
+	}
+}
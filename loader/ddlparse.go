@@ -0,0 +1,2243 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ddlTokenType is a ddl token type.
+type ddlTokenType int
+
+// ddl token types.
+const (
+	ddlEOF ddlTokenType = iota
+	ddlWord
+	ddlIdent
+	ddlString
+	ddlNumber
+	ddlPunct
+)
+
+// ddlToken is a lexed ddl token.
+type ddlToken struct {
+	typ ddlTokenType
+	val string
+	pos int
+	end int
+}
+
+// ddlLex lexes src into tokens, dropping whitespace and comments.
+func ddlLex(driver, src string) ([]ddlToken, error) {
+	var toks []ddlToken
+	for i := 0; i < len(src); {
+		c, next := src[i], byte(0)
+		if i+1 < len(src) {
+			next = src[i+1]
+		}
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case c == '-' && next == '-', c == '#' && driver == "mysql":
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && next == '*':
+			n := strings.Index(src[i+2:], "*/")
+			if n == -1 {
+				return nil, fmt.Errorf("line %d: unterminated comment", ddlLine(src, start))
+			}
+			i += n + 4
+			continue
+		case c == '\'':
+			s, n, err := ddlQuoted(driver, src[i:], '\'')
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", ddlLine(src, start), err)
+			}
+			i += n
+			toks = append(toks, ddlToken{typ: ddlString, val: s, pos: start, end: i})
+		case c == '"', c == '`', c == '[' && (driver == "sqlserver" || driver == "sqlite3"):
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			s, n, err := ddlQuoted("", src[i:], end)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", ddlLine(src, start), err)
+			}
+			i += n
+			toks = append(toks, ddlToken{typ: ddlIdent, val: s, pos: start, end: i})
+		case c == '$' && ddlDollarTag(src[i:]) != "":
+			tag := ddlDollarTag(src[i:])
+			n := strings.Index(src[i+len(tag):], tag)
+			if n == -1 {
+				return nil, fmt.Errorf("line %d: unterminated dollar quoted string", ddlLine(src, start))
+			}
+			i += 2*len(tag) + n
+			toks = append(toks, ddlToken{typ: ddlString, val: src[start+len(tag) : i-len(tag)], pos: start, end: i})
+		case ddlIsDigit(c), c == '.' && ddlIsDigit(next):
+			for i < len(src) && (ddlIsDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && ddlIsDigit(src[i]) {
+					i++
+				}
+			}
+			toks = append(toks, ddlToken{typ: ddlNumber, val: src[start:i], pos: start, end: i})
+		case ddlIsWord(c):
+			for i < len(src) && (ddlIsWord(src[i]) || ddlIsDigit(src[i]) || src[i] == '$') {
+				i++
+			}
+			toks = append(toks, ddlToken{typ: ddlWord, val: src[start:i], pos: start, end: i})
+		case c == ':' && next == ':':
+			i += 2
+			toks = append(toks, ddlToken{typ: ddlPunct, val: "::", pos: start, end: i})
+		default:
+			i++
+			toks = append(toks, ddlToken{typ: ddlPunct, val: string(c), pos: start, end: i})
+		}
+	}
+	return toks, nil
+}
+
+// ddlQuoted reads a quoted string or identifier from the start of s, returning
+// the unquoted value and the number of bytes read. Backslash escapes are
+// honored for mysql strings.
+func ddlQuoted(driver, s string, end byte) (string, int, error) {
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && driver == "mysql" && i+1 < len(s):
+			i++
+			sb.WriteByte(s[i])
+		case c == end && i+1 < len(s) && s[i+1] == end:
+			i++
+			sb.WriteByte(c)
+		case c == end:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string or identifier")
+}
+
+// ddlDollarTag returns the postgres dollar quote tag at the start of s.
+func ddlDollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1]
+		case !ddlIsWord(c) && (i == 1 || !ddlIsDigit(c)):
+			return ""
+		}
+	}
+	return ""
+}
+
+// ddlIsDigit returns true when c is a digit.
+func ddlIsDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// ddlIsWord returns true when c can start an unquoted identifier.
+func ddlIsWord(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+// ddlLine returns the line number of pos in src.
+func ddlLine(src string, pos int) int {
+	return strings.Count(src[:pos], "\n") + 1
+}
+
+// ddlSplit splits tokens into statements on top-level semicolons. Semicolons
+// inside the BEGIN ... END blocks of triggers, functions and procedures do not
+// end a statement.
+func ddlSplit(toks []ddlToken) [][]ddlToken {
+	var stmts [][]ddlToken
+	start, depth, block := 0, 0, false
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		if i == start {
+			block, depth = ddlIsBlock(toks[i:]), 0
+		}
+		switch {
+		case t.typ == ddlPunct && t.val == ";" && depth <= 0:
+			if i > start {
+				stmts = append(stmts, toks[start:i])
+			}
+			start = i + 1
+		case block && t.typ == ddlWord && (strings.EqualFold(t.val, "BEGIN") || strings.EqualFold(t.val, "CASE")):
+			depth++
+		case block && t.typ == ddlWord && strings.EqualFold(t.val, "END"):
+			if i+1 < len(toks) && toks[i+1].typ == ddlWord && ddlIn(toks[i+1].val, "IF", "LOOP", "WHILE", "REPEAT") {
+				i++
+				continue
+			}
+			depth--
+		}
+	}
+	if start < len(toks) {
+		stmts = append(stmts, toks[start:])
+	}
+	return stmts
+}
+
+// ddlIsBlock returns true when the statement creates a trigger, function, or
+// procedure.
+func ddlIsBlock(toks []ddlToken) bool {
+	if len(toks) == 0 || toks[0].typ != ddlWord || !strings.EqualFold(toks[0].val, "CREATE") {
+		return false
+	}
+	for _, t := range toks[1:] {
+		switch {
+		case t.typ == ddlPunct && t.val == "(":
+			return false
+		case t.typ == ddlWord && ddlIn(t.val, "TRIGGER", "FUNCTION", "PROCEDURE"):
+			return true
+		}
+	}
+	return false
+}
+
+// ddlIn returns true when s is case-insensitively equal to one of v.
+func ddlIn(s string, v ...string) bool {
+	for _, z := range v {
+		if strings.EqualFold(s, z) {
+			return true
+		}
+	}
+	return false
+}
+
+// ddlParser parses a single ddl statement.
+type ddlParser struct {
+	cat  *ddlCatalog
+	src  string
+	toks []ddlToken
+	i    int
+}
+
+// sub returns a parser for the tokens.
+func (p *ddlParser) sub(toks []ddlToken) *ddlParser {
+	return &ddlParser{cat: p.cat, src: p.src, toks: toks}
+}
+
+// more returns true when there are remaining tokens.
+func (p *ddlParser) more() bool {
+	return p.i < len(p.toks)
+}
+
+// peek returns the token n tokens ahead.
+func (p *ddlParser) peek(n int) ddlToken {
+	if p.i+n < len(p.toks) {
+		return p.toks[p.i+n]
+	}
+	return ddlToken{typ: ddlEOF}
+}
+
+// is returns true when the upcoming tokens are the words.
+func (p *ddlParser) is(words ...string) bool {
+	for n, word := range words {
+		if t := p.peek(n); t.typ != ddlWord || !strings.EqualFold(t.val, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAny returns true when the next token is one of the words.
+func (p *ddlParser) isAny(words ...string) bool {
+	t := p.peek(0)
+	return t.typ == ddlWord && ddlIn(t.val, words...)
+}
+
+// isPunct returns true when the next token is the punctuation.
+func (p *ddlParser) isPunct(s string) bool {
+	t := p.peek(0)
+	return t.typ == ddlPunct && t.val == s
+}
+
+// accept consumes the words when they are the upcoming tokens.
+func (p *ddlParser) accept(words ...string) bool {
+	if !p.is(words...) {
+		return false
+	}
+	p.i += len(words)
+	return true
+}
+
+// acceptAny consumes the next token when it is one of the words.
+func (p *ddlParser) acceptAny(words ...string) bool {
+	if !p.isAny(words...) {
+		return false
+	}
+	p.i++
+	return true
+}
+
+// acceptPunct consumes the next token when it is the punctuation.
+func (p *ddlParser) acceptPunct(s string) bool {
+	if !p.isPunct(s) {
+		return false
+	}
+	p.i++
+	return true
+}
+
+// expect consumes the words, returning an error when not present.
+func (p *ddlParser) expect(words ...string) error {
+	if !p.accept(words...) {
+		return fmt.Errorf("expected %s, got %s", strings.Join(words, " "), p.desc())
+	}
+	return nil
+}
+
+// desc returns a description of the next token for use in errors.
+func (p *ddlParser) desc() string {
+	if !p.more() {
+		return "end of statement"
+	}
+	return fmt.Sprintf("%q", p.src[p.toks[p.i].pos:p.toks[p.i].end])
+}
+
+// raw returns the source text for the tokens in [i, j).
+func (p *ddlParser) raw(i, j int) string {
+	if i >= j || i >= len(p.toks) {
+		return ""
+	}
+	return p.src[p.toks[i].pos:p.toks[j-1].end]
+}
+
+// name consumes an identifier, folding unquoted identifiers to the case used
+// by the database's catalog.
+func (p *ddlParser) name() (string, error) {
+	t := p.peek(0)
+	switch t.typ {
+	case ddlIdent:
+		p.i++
+		return t.val, nil
+	case ddlWord:
+		p.i++
+		if p.cat.driver == "postgres" || p.cat.driver == "oracle" {
+			return strings.ToLower(t.val), nil
+		}
+		return t.val, nil
+	}
+	return "", fmt.Errorf("expected identifier, got %s", p.desc())
+}
+
+// qualified consumes a schema qualified name.
+func (p *ddlParser) qualified() (string, string, error) {
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return "", "", err
+		}
+		names = append(names, name)
+		if !p.acceptPunct(".") {
+			break
+		}
+	}
+	// drop database qualifier (ie, db.schema.table)
+	if len(names) > 2 {
+		names = names[len(names)-2:]
+	}
+	if len(names) == 2 {
+		return names[0], names[1], nil
+	}
+	return "", names[0], nil
+}
+
+// group consumes a parenthesized group, returning its items split on
+// top-level commas.
+func (p *ddlParser) group() ([][]ddlToken, error) {
+	if !p.acceptPunct("(") {
+		return nil, fmt.Errorf("expected (, got %s", p.desc())
+	}
+	var items [][]ddlToken
+	start, depth := p.i, 0
+	for ; p.more(); p.i++ {
+		t := p.toks[p.i]
+		if t.typ != ddlPunct {
+			continue
+		}
+		switch {
+		case t.val == "(" || t.val == "[":
+			depth++
+		case (t.val == ")" || t.val == "]") && depth != 0:
+			depth--
+		case t.val == ")":
+			if p.i > start {
+				items = append(items, p.toks[start:p.i])
+			}
+			p.i++
+			return items, nil
+		case t.val == "," && depth == 0:
+			items = append(items, p.toks[start:p.i])
+			start = p.i + 1
+		}
+	}
+	return nil, fmt.Errorf("unterminated (")
+}
+
+// skipGroup consumes a parenthesized group when present.
+func (p *ddlParser) skipGroup() error {
+	if !p.isPunct("(") {
+		return nil
+	}
+	_, err := p.group()
+	return err
+}
+
+// split splits the remaining tokens on top-level commas.
+func (p *ddlParser) split() [][]ddlToken {
+	var items [][]ddlToken
+	start, depth := p.i, 0
+	for ; p.more(); p.i++ {
+		switch t := p.toks[p.i]; {
+		case t.typ != ddlPunct:
+		case t.val == "(":
+			depth++
+		case t.val == ")":
+			depth--
+		case t.val == "," && depth == 0:
+			items = append(items, p.toks[start:p.i])
+			start = p.i + 1
+		}
+	}
+	if start < len(p.toks) {
+		items = append(items, p.toks[start:])
+	}
+	return items
+}
+
+// expr consumes an expression, stopping at the first top-level word in stop
+// that is not the expression's first token.
+func (p *ddlParser) expr(stop ...string) string {
+	start := p.i
+	for p.more() {
+		switch t := p.peek(0); {
+		case p.i != start && t.typ == ddlWord && ddlIn(t.val, stop...):
+			return p.raw(start, p.i)
+		case t.typ == ddlPunct && t.val == "(":
+			if err := p.skipGroup(); err != nil {
+				p.i = len(p.toks)
+			}
+		default:
+			p.i++
+		}
+	}
+	return p.raw(start, p.i)
+}
+
+// columnList consumes a parenthesized list of column names.
+func (p *ddlParser) columnList() ([]string, error) {
+	items, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	var cols []string
+	for _, item := range items {
+		name, err := p.sub(item).name()
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, name)
+	}
+	return cols, nil
+}
+
+// indexColumns consumes a parenthesized list of index elements, returning the
+// column names and the expressions of the elements. Expression elements have
+// an empty column name, and plain column references an empty expression.
+func (p *ddlParser) indexColumns() ([]string, []string, error) {
+	items, err := p.group()
+	if err != nil {
+		return nil, nil, err
+	}
+	cols, exprs := make([]string, len(items)), make([]string, len(items))
+	for i, item := range items {
+		if (item[0].typ != ddlWord && item[0].typ != ddlIdent) ||
+			len(item) > 2 && item[1].typ == ddlPunct && item[1].val == "(" && item[2].typ != ddlNumber ||
+			len(item) > 1 && item[1].typ == ddlPunct && item[1].val != "(" {
+			exprs[i] = p.indexExpr(item)
+			continue
+		}
+		if cols[i], err = p.sub(item).name(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cols, exprs, nil
+}
+
+// indexExpr returns the expression of an index element, dropping any
+// trailing collation, operator class, or ordering, and the parentheses
+// around the expression.
+func (p *ddlParser) indexExpr(item []ddlToken) string {
+	// the expression ends at its last top-level closing parenthesis
+	end, depth := len(item), 0
+	for i, t := range item {
+		switch {
+		case t.typ != ddlPunct:
+		case t.val == "(":
+			depth++
+		case t.val == ")":
+			depth--
+			if depth == 0 {
+				end = i + 1
+			}
+		}
+	}
+	item = item[:end]
+	// strip enclosing parentheses
+	for len(item) > 2 && item[0].typ == ddlPunct && item[0].val == "(" {
+		sub := p.sub(item)
+		if _, err := sub.group(); err != nil || sub.more() {
+			break
+		}
+		item = item[1 : len(item)-1]
+	}
+	return p.sub(item).raw(0, len(item))
+}
+
+// typeWords are the words that can continue a multi-word data type.
+var typeWords = []string{
+	"VARYING", "PRECISION", "WITHOUT", "TIME", "ZONE", "LOCAL", "UNSIGNED",
+	"SIGNED", "ZEROFILL", "RAW",
+}
+
+// intervalWords are the words that can continue an interval data type.
+var intervalWords = []string{
+	"YEAR", "MONTH", "DAY", "HOUR", "MINUTE", "SECOND", "TO",
+}
+
+// dataType consumes a column data type, returning the type as reported by
+// the database's catalog, any enum values, and whether the type is a postgres
+// serial type.
+func (p *ddlParser) dataType() (string, []string, bool, error) {
+	start := p.i
+	schema, name, err := p.qualified()
+	if err != nil {
+		return "", nil, false, err
+	}
+	words, args, array := []string{strings.ToLower(name)}, "", false
+	var values []string
+loop:
+	for p.more() {
+		t := p.peek(0)
+		switch {
+		case t.typ == ddlWord && ddlIn(t.val, typeWords...),
+			t.typ == ddlWord && strings.EqualFold(t.val, "WITH") && p.peek(1).typ == ddlWord && ddlIn(p.peek(1).val, "TIME", "LOCAL"),
+			t.typ == ddlWord && words[0] == "interval" && ddlIn(t.val, intervalWords...):
+			words = append(words, strings.ToLower(t.val))
+			p.i++
+		case t.typ == ddlPunct && t.val == "(" && args == "" && values == nil:
+			items, err := p.group()
+			if err != nil {
+				return "", nil, false, err
+			}
+			if words[0] == "enum" || words[0] == "set" {
+				for _, item := range items {
+					values = append(values, item[0].val)
+				}
+				continue
+			}
+			var v []string
+			for _, item := range items {
+				v = append(v, strings.ToLower(strings.Join(strings.Fields(p.sub(item).raw(0, len(item))), "")))
+			}
+			args = "(" + strings.Join(v, ",") + ")"
+		case t.typ == ddlPunct && t.val == "[":
+			for p.more() && !p.isPunct("]") {
+				p.i++
+			}
+			p.i++
+			array = true
+		case t.typ == ddlWord && strings.EqualFold(t.val, "ARRAY"):
+			p.i++
+			array = true
+		default:
+			break loop
+		}
+	}
+	// keep schema for types outside the search path
+	if schema != "" && schema != "public" && schema != "pg_catalog" {
+		words[0] = schema + "." + words[0]
+	}
+	typ, serial := ddlNormalize(p.cat.driver, words, args)
+	switch {
+	case p.cat.driver == "sqlite3":
+		typ = p.raw(start, p.i)
+	case array:
+		typ += "[]"
+	}
+	return typ, values, serial, nil
+}
+
+// ddlNormalize normalizes a data type to the form reported by the driver's
+// catalog, returning whether the type is a postgres serial type.
+func ddlNormalize(driver string, words []string, args string) (string, bool) {
+	// separate modifiers
+	var mods []string
+	for len(words) > 1 && ddlIn(words[len(words)-1], "unsigned", "signed", "zerofill") {
+		mods, words = append([]string{words[len(words)-1]}, mods...), words[:len(words)-1]
+	}
+	typ, serial := strings.Join(words, " "), false
+	switch driver {
+	case "postgres":
+		switch typ {
+		case "serial", "serial4":
+			typ, serial = "integer", true
+		case "bigserial", "serial8":
+			typ, serial = "bigint", true
+		case "smallserial", "serial2":
+			typ, serial = "smallint", true
+		case "int", "int4":
+			typ = "integer"
+		case "int8":
+			typ = "bigint"
+		case "int2":
+			typ = "smallint"
+		case "bool":
+			typ = "boolean"
+		case "float4":
+			typ = "real"
+		case "float", "float8":
+			typ = "double precision"
+		case "decimal":
+			typ = "numeric"
+		case "varchar":
+			typ = "character varying"
+		case "char", "bpchar":
+			typ = "character"
+		case "varbit":
+			typ = "bit varying"
+		case "timestamptz":
+			typ = "timestamp with time zone"
+		case "timetz":
+			typ = "time with time zone"
+		case "timestamp", "time":
+			typ += " without time zone"
+		}
+		// precision precedes the time zone
+		if i := strings.Index(typ, " with"); i != -1 && args != "" && (strings.HasPrefix(typ, "time ") || strings.HasPrefix(typ, "timestamp ")) {
+			return typ[:i] + args + typ[i:], serial
+		}
+	case "mysql":
+		switch typ {
+		case "integer":
+			typ = "int"
+		case "bool", "boolean":
+			typ, args = "tinyint", "(1)"
+		case "dec", "numeric", "fixed":
+			typ = "decimal"
+		case "double precision", "real":
+			typ = "double"
+		case "character varying":
+			typ = "varchar"
+		case "character":
+			typ = "char"
+		}
+		if typ == "decimal" && args == "" {
+			args = "(10,0)"
+		}
+	case "sqlserver":
+		switch typ {
+		case "integer", "int":
+			typ, args = "int", "(10)"
+		case "bigint":
+			args = "(19)"
+		case "smallint":
+			args = "(5)"
+		case "tinyint":
+			args = "(3)"
+		}
+	case "oracle":
+		switch typ {
+		case "integer", "int", "smallint":
+			typ, args = "number", "(38,0)"
+		case "varchar":
+			typ = "varchar2"
+		case "number":
+			if args == "" {
+				args = "(0,0)"
+			}
+		}
+	}
+	return strings.TrimSpace(typ + args + " " + strings.Join(mods, " ")), serial
+}
+
+// statement parses a statement, ignoring statements that do not affect the
+// catalog.
+func (p *ddlParser) statement() error {
+	switch {
+	case p.accept("CREATE"):
+		return p.create()
+	case p.accept("ALTER", "TABLE"):
+		return p.alterTable()
+	case p.accept("ALTER", "TYPE"):
+		return p.alterType()
+	case p.accept("DROP"):
+		return p.drop()
+	case p.accept("COMMENT", "ON", "COLUMN"):
+		return p.commentColumn()
+	case p.accept("COMMENT", "ON", "TABLE"), p.accept("COMMENT", "ON", "VIEW"),
+		p.accept("COMMENT", "ON", "MATERIALIZED", "VIEW"), p.accept("COMMENT", "ON", "FOREIGN", "TABLE"),
+		p.accept("COMMENT", "ON", "TYPE"), p.accept("COMMENT", "ON", "DOMAIN"):
+		return p.commentTable()
+	case p.accept("USE"):
+		if p.cat.driver == "mysql" {
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			p.cat.def = name
+		}
+	case p.accept("SET", "SEARCH_PATH"):
+		if p.cat.driver == "postgres" {
+			if !p.accept("TO") {
+				p.acceptPunct("=")
+			}
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			p.cat.def = name
+		}
+	}
+	return nil
+}
+
+// create parses a CREATE statement.
+func (p *ddlParser) create() error {
+	// find object kind, collecting modifiers
+	mods := make(map[string]bool)
+	var kind string
+	for ; p.more() && kind == ""; p.i++ {
+		switch t := p.peek(0); {
+		case t.typ == ddlPunct && t.val == "(":
+			return nil
+		case t.typ == ddlWord && ddlIn(t.val, "TABLE", "INDEX", "VIEW", "TYPE", "DOMAIN", "TRIGGER", "FUNCTION", "PROCEDURE", "SEQUENCE", "SCHEMA", "DATABASE", "EXTENSION"):
+			kind = strings.ToUpper(t.val)
+		case t.typ == ddlWord:
+			mods[strings.ToUpper(t.val)] = true
+		}
+	}
+	switch {
+	case kind == "TABLE" && !mods["TEMP"] && !mods["TEMPORARY"]:
+		return p.createTable(mods["FOREIGN"])
+	case kind == "INDEX":
+		return p.createIndex(mods["UNIQUE"])
+	case kind == "VIEW" && !mods["TEMP"] && !mods["TEMPORARY"]:
+		return p.createView(mods["MATERIALIZED"])
+	case kind == "TYPE":
+		return p.createType()
+	case kind == "DOMAIN" && p.cat.driver == "postgres":
+		return p.createDomain()
+	}
+	return nil
+}
+
+// createTable parses a CREATE [FOREIGN] TABLE statement. Partitions of a
+// partitioned table are skipped.
+func (p *ddlParser) createTable(foreign bool) error {
+	exists := p.accept("IF", "NOT", "EXISTS")
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	if p.cat.table(schema, name) != nil {
+		if exists {
+			return nil
+		}
+		return fmt.Errorf("table %q already defined", name)
+	}
+	if p.is("PARTITION", "OF") {
+		return nil
+	}
+	if !p.isPunct("(") {
+		ddlWarn("skipping table %q: definition not supported", name)
+		return nil
+	}
+	items, err := p.group()
+	if err != nil {
+		return err
+	}
+	t := &ddlTable{
+		schema: schema,
+		name:   name,
+		typ:    "table",
+	}
+	for _, item := range items {
+		if err := p.sub(item).tableElement(t); err != nil {
+			return fmt.Errorf("table %q: %w", name, err)
+		}
+	}
+	// table options
+	for p.more() {
+		switch {
+		case p.cat.driver == "postgres" && p.accept("PARTITION", "BY"):
+			t.typ, t.def = "partitioned table", p.expr("USING", "WITH", "WITHOUT", "TABLESPACE")
+			continue
+		case foreign && p.isAny("SERVER"):
+			t.typ, t.def = "foreign table", p.raw(p.i, len(p.toks))
+			p.i = len(p.toks)
+			continue
+		}
+		if p.accept("COMMENT") {
+			p.acceptPunct("=")
+			if tok := p.peek(0); tok.typ == ddlString {
+				t.comment = tok.val
+			}
+		}
+		p.i++
+	}
+	p.cat.tables = append(p.cat.tables, t)
+	return nil
+}
+
+// isConstraint returns true when the next token starts a table constraint.
+func (p *ddlParser) isConstraint() bool {
+	if p.isAny("CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE", "LIKE", "PERIOD") {
+		return true
+	}
+	return p.cat.driver == "mysql" && p.isAny("KEY", "INDEX", "FULLTEXT", "SPATIAL")
+}
+
+// tableElement parses a column definition or table constraint.
+func (p *ddlParser) tableElement(t *ddlTable) error {
+	if p.isConstraint() {
+		return p.tableConstraint(t)
+	}
+	return p.columnDef(t)
+}
+
+// tableConstraint parses a table constraint.
+func (p *ddlParser) tableConstraint(t *ddlTable) error {
+	var name string
+	if p.accept("CONSTRAINT") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+	// optional index name and type before the column list
+	indexName := func() error {
+		if !p.isPunct("(") && !p.isAny("USING") {
+			n, err := p.name()
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				name = n
+			}
+		}
+		if p.accept("USING") {
+			p.i++
+		}
+		return nil
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		p.acceptAny("CLUSTERED", "NONCLUSTERED")
+		if p.accept("USING") {
+			p.i++
+		}
+		cols, exprs, err := p.indexColumns()
+		if err != nil || strings.Join(exprs, "") != "" {
+			return err
+		}
+		t.setPrimary(p.cat, name, cols)
+	case p.accept("UNIQUE"):
+		p.acceptAny("KEY", "INDEX")
+		p.acceptAny("CLUSTERED", "NONCLUSTERED")
+		if err := indexName(); err != nil {
+			return err
+		}
+		cols, exprs, err := p.indexColumns()
+		if err != nil {
+			return err
+		}
+		t.addIndex(p.cat, name, cols, exprs, true, false)
+	case p.accept("FOREIGN", "KEY"):
+		if err := indexName(); err != nil {
+			return err
+		}
+		cols, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		fk, err := p.references()
+		if err != nil {
+			return err
+		}
+		fk.name, fk.cols = name, cols
+		t.addForeignKey(p.cat, fk)
+	case p.acceptAny("FULLTEXT", "SPATIAL"), p.isAny("KEY", "INDEX"):
+		p.acceptAny("KEY", "INDEX")
+		if err := indexName(); err != nil {
+			return err
+		}
+		cols, exprs, err := p.indexColumns()
+		if err != nil {
+			return err
+		}
+		t.addIndex(p.cat, name, cols, exprs, false, false)
+	case p.accept("CHECK"):
+		expr, err := p.parenExpr()
+		if err != nil {
+			return err
+		}
+		t.addCheck(p.cat, name, "", expr)
+	}
+	return nil
+}
+
+// parenExpr parses a parenthesized expression (such as the expression of a
+// CHECK constraint or generated column), returning it without the parens.
+func (p *ddlParser) parenExpr() (string, error) {
+	start := p.i
+	if _, err := p.group(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(p.raw(start+1, p.i-1)), nil
+}
+
+// references parses a REFERENCES clause.
+func (p *ddlParser) references() (*ddlForeignKey, error) {
+	if err := p.expect("REFERENCES"); err != nil {
+		return nil, err
+	}
+	schema, table, err := p.qualified()
+	if err != nil {
+		return nil, err
+	}
+	fk := &ddlForeignKey{
+		refSchema: schema,
+		refTable:  table,
+	}
+	if p.isPunct("(") {
+		if fk.refCols, err = p.columnList(); err != nil {
+			return nil, err
+		}
+	}
+	// skip referential actions
+	for p.more() {
+		switch {
+		case p.accept("ON", "DELETE"), p.accept("ON", "UPDATE"):
+			switch {
+			case p.accept("NO", "ACTION"), p.acceptAny("CASCADE", "RESTRICT"):
+			case p.accept("SET", "NULL"), p.accept("SET", "DEFAULT"):
+				if err := p.skipGroup(); err != nil {
+					return nil, err
+				}
+			}
+		case p.accept("MATCH"), p.accept("INITIALLY"):
+			p.i++
+		case p.accept("DEFERRABLE"), p.accept("NOT", "DEFERRABLE"), p.accept("NOT", "ENFORCED"), p.accept("ENFORCED"):
+		default:
+			return fk, nil
+		}
+	}
+	return fk, nil
+}
+
+// columnDef parses a column definition.
+func (p *ddlParser) columnDef(t *ddlTable) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if t.column(name) != nil {
+		return fmt.Errorf("column %q already defined", name)
+	}
+	c := &ddlColumn{name: name}
+	// sqlserver computed columns have no data type
+	if p.cat.driver == "sqlserver" && p.accept("AS") {
+		return p.computed(t, c)
+	}
+	typ, values, serial, err := p.dataType()
+	if err != nil {
+		return fmt.Errorf("column %q: %w", name, err)
+	}
+	c.typ, c.sequence = typ, serial
+	// mysql enums are named after their column
+	if values != nil && p.cat.driver == "mysql" && strings.HasPrefix(typ, "enum") {
+		c.typ = name
+		if p.cat.enum(t.schema, name) == nil {
+			p.cat.enums = append(p.cat.enums, &ddlEnum{
+				schema: t.schema,
+				name:   name,
+				values: values,
+			})
+		}
+	}
+	t.columns = append(t.columns, c)
+	return p.columnConstraints(t, c)
+}
+
+// columnConstraints parses the constraints of a column definition.
+func (p *ddlParser) columnConstraints(t *ddlTable, c *ddlColumn) error {
+	var name string
+	for p.more() {
+		switch {
+		case p.accept("CONSTRAINT"):
+			var err error
+			if name, err = p.name(); err != nil {
+				return err
+			}
+			continue
+		case p.accept("NOT", "NULL"):
+			c.notNull = true
+		case p.accept("NULL"):
+		case p.accept("DEFAULT"):
+			start := p.i
+			def := p.expr(
+				"NOT", "NULL", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK",
+				"CONSTRAINT", "COLLATE", "GENERATED", "AUTO_INCREMENT",
+				"AUTOINCREMENT", "COMMENT", "ON", "IDENTITY",
+			)
+			c.setDefault(p.cat.driver, p.toks[start:p.i], def)
+		case p.accept("PRIMARY", "KEY"):
+			p.acceptAny("ASC", "DESC")
+			p.acceptAny("CLUSTERED", "NONCLUSTERED")
+			t.setPrimary(p.cat, name, []string{c.name})
+		case p.accept("UNIQUE"):
+			p.acceptAny("KEY")
+			p.acceptAny("CLUSTERED", "NONCLUSTERED")
+			t.addIndex(p.cat, name, []string{c.name}, nil, true, false)
+		case p.isAny("REFERENCES"):
+			fk, err := p.references()
+			if err != nil {
+				return err
+			}
+			fk.name, fk.cols = name, []string{c.name}
+			t.addForeignKey(p.cat, fk)
+		case p.accept("CHECK"):
+			expr, err := p.parenExpr()
+			if err != nil {
+				return err
+			}
+			t.addCheck(p.cat, name, c.name, expr)
+		case p.accept("COLLATE"), p.accept("CHARACTER", "SET"), p.accept("CHARSET"), p.accept("ON", "CONFLICT"):
+			p.i++
+		case p.accept("ON", "UPDATE"):
+			p.expr("NOT", "NULL", "DEFAULT", "COMMENT")
+		case p.accept("GENERATED"):
+			if !p.accept("ALWAYS") {
+				p.accept("BY", "DEFAULT")
+			}
+			p.accept("ON", "NULL")
+			if err := p.expect("AS"); err != nil {
+				return err
+			}
+			if p.accept("IDENTITY") {
+				c.sequence = true
+				if err := p.skipGroup(); err != nil {
+					return err
+				}
+				break
+			}
+			if err := p.generated(c); err != nil {
+				return err
+			}
+		case p.accept("AS"):
+			if err := p.generated(c); err != nil {
+				return err
+			}
+		case p.acceptAny("AUTO_INCREMENT", "AUTOINCREMENT"):
+			c.sequence = true
+		case p.accept("IDENTITY"):
+			c.sequence = true
+			if err := p.skipGroup(); err != nil {
+				return err
+			}
+		case p.accept("COMMENT"):
+			if t := p.peek(0); t.typ == ddlString {
+				c.comment = t.val
+			}
+			p.i++
+		default:
+			p.i++
+		}
+		name = ""
+	}
+	return nil
+}
+
+// generated parses the expression of a generated column, using it as the
+// column's default value.
+func (p *ddlParser) generated(c *ddlColumn) error {
+	if !p.isPunct("(") {
+		return nil
+	}
+	expr, err := p.parenExpr()
+	if err != nil {
+		return err
+	}
+	p.acceptAny("STORED", "VIRTUAL", "PERSISTED")
+	c.generated, c.def = true, sql.NullString{String: expr, Valid: true}
+	return nil
+}
+
+// computed parses a sqlserver computed column (ie, 'name AS expr
+// [PERSISTED]'), determining the column's type from the expression. Columns
+// whose type cannot be determined are skipped.
+func (p *ddlParser) computed(t *ddlTable, c *ddlColumn) error {
+	start := p.i
+	expr := p.expr("PERSISTED", "NOT", "NULL", "CONSTRAINT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK")
+	toks := p.toks[start:p.i]
+	// strip parens
+	for len(toks) > 2 && toks[0].typ == ddlPunct && toks[0].val == "(" {
+		q := p.sub(toks)
+		items, err := q.group()
+		if err != nil || q.more() || len(items) != 1 {
+			break
+		}
+		toks = items[0]
+	}
+	var typ string
+	q := p.sub(toks)
+	switch name, err := q.name(); {
+	case err == nil && !q.more() && t.column(name) != nil:
+		typ = t.column(name).typ
+	default:
+		if typ, err = p.sub(toks).cast(); err != nil {
+			ddlWarn("skipping table %q computed column %q: %v", t.name, c.name, err)
+			p.accept("PERSISTED")
+			return p.columnConstraints(t, new(ddlColumn))
+		}
+	}
+	p.accept("PERSISTED")
+	c.typ, c.generated, c.def = typ, true, sql.NullString{String: expr, Valid: true}
+	t.columns = append(t.columns, c)
+	return p.columnConstraints(t, c)
+}
+
+// createIndex parses a CREATE INDEX statement.
+func (p *ddlParser) createIndex(unique bool) error {
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	var name string
+	if !p.isAny("ON") {
+		var err error
+		if _, name, err = p.qualified(); err != nil {
+			return err
+		}
+	}
+	if p.accept("USING") {
+		p.i++
+	}
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	p.accept("ONLY")
+	schema, table, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	t := p.cat.table(schema, table)
+	if t == nil {
+		return fmt.Errorf("index %q: table %q not defined", name, table)
+	}
+	if p.accept("USING") {
+		p.i++
+	}
+	cols, exprs, err := p.indexColumns()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		// unlike unique constraints, unnamed unique indexes use the _idx suffix
+		name = t.uniqueName(t.name+"_"+strings.Join(ddlIndexNames(cols, exprs), "_")+"_idx", "")
+	}
+	index := t.addIndex(p.cat, name, cols, exprs, unique, false)
+	// sqlite3 names only implicit unique indexes sqlite_autoindex_*
+	index.name = name
+	// partial index predicate
+	for p.more() {
+		switch {
+		case p.acceptAny("INCLUDE", "WITH"):
+			if err := p.skipGroup(); err != nil {
+				return err
+			}
+		case p.accept("WHERE"):
+			index.where = p.expr("WITH", "ON", "TABLESPACE")
+		default:
+			p.i++
+		}
+	}
+	return nil
+}
+
+// createType parses a CREATE TYPE statement, ignoring types other than
+// enums and composite types.
+func (p *ddlParser) createType() error {
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	if p.accept("AS") && p.isPunct("(") {
+		return p.createComposite(schema, name)
+	}
+	if !p.accept("ENUM") {
+		return nil
+	}
+	items, err := p.group()
+	if err != nil {
+		return err
+	}
+	e := &ddlEnum{
+		schema: schema,
+		name:   name,
+	}
+	for _, item := range items {
+		e.values = append(e.values, item[0].val)
+	}
+	p.cat.enums = append(p.cat.enums, e)
+	return nil
+}
+
+// alterType parses an ALTER TYPE statement, adding or renaming the values of
+// an enum, or renaming an enum.
+func (p *ddlParser) alterType() error {
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	e := p.cat.enum(schema, name)
+	switch {
+	case p.accept("ADD", "VALUE"):
+		if e == nil {
+			return fmt.Errorf("unknown enum %q", name)
+		}
+		exists := p.accept("IF", "NOT", "EXISTS")
+		value, err := p.stringValue()
+		if err != nil {
+			return err
+		}
+		switch {
+		case ddlIndexOf(e.values, value) != -1 && exists:
+			return nil
+		case ddlIndexOf(e.values, value) != -1:
+			return fmt.Errorf("enum %q value %q already exists", name, value)
+		}
+		i := len(e.values)
+		if before := p.accept("BEFORE"); before || p.accept("AFTER") {
+			ref, err := p.stringValue()
+			if err != nil {
+				return err
+			}
+			if i = ddlIndexOf(e.values, ref); i == -1 {
+				return fmt.Errorf("enum %q has no value %q", name, ref)
+			}
+			if !before {
+				i++
+			}
+		}
+		e.values = append(e.values[:i], append([]string{value}, e.values[i:]...)...)
+	case p.accept("RENAME", "VALUE"):
+		if e == nil {
+			return fmt.Errorf("unknown enum %q", name)
+		}
+		from, err := p.stringValue()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		to, err := p.stringValue()
+		if err != nil {
+			return err
+		}
+		i := ddlIndexOf(e.values, from)
+		if i == -1 {
+			return fmt.Errorf("enum %q has no value %q", name, from)
+		}
+		e.values[i] = to
+	case p.accept("RENAME", "TO") && e != nil:
+		to, err := p.name()
+		if err != nil {
+			return err
+		}
+		// columns of the enum type
+		for _, t := range p.cat.tables {
+			for _, c := range t.columns {
+				switch c.typ {
+				case e.name:
+					c.typ = to
+				case e.name + "[]":
+					c.typ = to + "[]"
+				}
+			}
+		}
+		e.name = to
+	}
+	return nil
+}
+
+// stringValue consumes a string literal.
+func (p *ddlParser) stringValue() (string, error) {
+	t := p.peek(0)
+	if t.typ != ddlString {
+		return "", fmt.Errorf("expected string, got %s", p.desc())
+	}
+	p.i++
+	return t.val, nil
+}
+
+// ddlIndexOf returns the index of s in v, or -1 when not present.
+func ddlIndexOf(v []string, s string) int {
+	for i, z := range v {
+		if z == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// createComposite parses the attributes of a CREATE TYPE ... AS (...)
+// statement. Composite types are kept in the catalog as tables, with the
+// attributes as columns.
+func (p *ddlParser) createComposite(schema, name string) error {
+	items, err := p.group()
+	if err != nil {
+		return err
+	}
+	t := &ddlTable{
+		schema: schema,
+		name:   name,
+		typ:    "composite type",
+	}
+	for _, item := range items {
+		if err := p.sub(item).columnDef(t); err != nil {
+			return fmt.Errorf("type %q: %w", name, err)
+		}
+	}
+	p.cat.tables = append(p.cat.tables, t)
+	return nil
+}
+
+// createDomain parses a CREATE DOMAIN statement. Domains are kept in the
+// catalog as tables, with an unnamed column for the base type and constraints.
+func (p *ddlParser) createDomain() error {
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	p.accept("AS")
+	c := new(ddlColumn)
+	if c.typ, _, _, err = p.dataType(); err != nil {
+		return fmt.Errorf("domain %q: %w", name, err)
+	}
+	t := &ddlTable{
+		schema:  schema,
+		name:    name,
+		typ:     "domain",
+		columns: []*ddlColumn{c},
+	}
+	if err := p.columnConstraints(t, c); err != nil {
+		return fmt.Errorf("domain %q: %w", name, err)
+	}
+	p.cat.tables = append(p.cat.tables, t)
+	return nil
+}
+
+// createView parses a CREATE [MATERIALIZED] VIEW statement.
+func (p *ddlParser) createView(materialized bool) error {
+	p.accept("IF", "NOT", "EXISTS")
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	var names []string
+	if p.isPunct("(") {
+		if names, err = p.columnList(); err != nil {
+			return err
+		}
+	}
+	for p.more() && !p.isAny("AS") {
+		p.i++
+	}
+	if err := p.expect("AS"); err != nil {
+		return err
+	}
+	toks := p.toks[p.i:]
+	t := &ddlTable{
+		schema: schema,
+		name:   name,
+		typ:    "view",
+		def:    p.raw(p.i, len(p.toks)),
+	}
+	if materialized {
+		// strip WITH [NO] DATA
+		if n := len(toks); n > 2 && strings.EqualFold(toks[n-1].val, "DATA") && strings.EqualFold(toks[n-2].val, "NO") {
+			toks = toks[:n-2]
+		} else if n > 1 && strings.EqualFold(toks[n-1].val, "DATA") {
+			toks = toks[:n-1]
+		}
+		if n := len(toks); n > 1 && strings.EqualFold(toks[n-1].val, "WITH") {
+			toks = toks[:n-1]
+		}
+		t.typ, t.def = "materialized view", p.raw(p.i, p.i+len(toks))
+	}
+	if p.cat.driver == "sqlite3" {
+		t.def = p.raw(0, len(p.toks))
+	}
+	if t.columns, err = p.sub(toks).viewColumns(name); err != nil {
+		return err
+	}
+	// only mysql reports the nullability of view columns
+	for _, c := range t.columns {
+		c.notNull = c.notNull && p.cat.driver == "mysql"
+	}
+	for i := 0; i < len(names) && i < len(t.columns); i++ {
+		t.columns[i].name = names[i]
+	}
+	if v := p.cat.table(schema, name); v != nil {
+		*v = *t
+		return nil
+	}
+	p.cat.tables = append(p.cat.tables, t)
+	return nil
+}
+
+// viewColumns determines the columns of a view from its select list. Columns
+// whose type cannot be determined are skipped.
+func (p *ddlParser) viewColumns(view string) ([]*ddlColumn, error) {
+	if !p.accept("SELECT") {
+		ddlWarn("skipping view %q columns: only SELECT is supported", view)
+		return nil, nil
+	}
+	items, refs, err := p.selectList()
+	if err != nil {
+		return nil, err
+	}
+	var cols []*ddlColumn
+	for _, item := range items {
+		c, err := p.sub(item).viewColumn(refs)
+		switch {
+		case err != nil:
+			ddlWarn("skipping view %q column %q: %v", view, p.sub(item).raw(0, len(item)), err)
+		default:
+			cols = append(cols, c...)
+		}
+	}
+	return cols, nil
+}
+
+// queryColumns determines the result columns of a SELECT, or of the RETURNING
+// clause of an INSERT, UPDATE, or DELETE. Columns that cannot be determined
+// are nil.
+func (p *ddlParser) queryColumns() ([]*ddlColumn, error) {
+	var items [][]ddlToken
+	var refs []ddlRef
+	switch {
+	case p.accept("SELECT"):
+		var err error
+		if items, refs, err = p.selectList(); err != nil {
+			return nil, err
+		}
+		// the columns of other SELECTs in a UNION may be null
+		for depth := 0; p.more(); p.i++ {
+			switch t := p.peek(0); {
+			case t.typ == ddlPunct && t.val == "(":
+				depth++
+			case t.typ == ddlPunct && t.val == ")":
+				depth--
+			case depth == 0 && t.typ == ddlWord && strings.EqualFold(t.val, "UNION"):
+				return nil, fmt.Errorf("UNION is not supported")
+			}
+		}
+	case p.acceptAny("INSERT", "UPDATE", "DELETE"):
+		p.acceptAny("INTO", "FROM")
+		p.accept("ONLY")
+		schema, name, err := p.qualified()
+		if err != nil {
+			return nil, err
+		}
+		ref := ddlRef{alias: name, table: p.cat.table(schema, name)}
+		p.accept("AS")
+		if t := p.peek(0); t.typ == ddlIdent || t.typ == ddlWord && !ddlIn(t.val, "SET", "WHERE", "USING", "RETURNING", "VALUES", "SELECT", "DEFAULT", "OVERRIDING", "ON") {
+			ref.alias, _ = p.name()
+		}
+		refs = []ddlRef{ref}
+		for p.more() && !p.isAny("RETURNING") {
+			p.i++
+		}
+		if !p.accept("RETURNING") {
+			return nil, fmt.Errorf("no RETURNING clause")
+		}
+		items = p.split()
+	default:
+		return nil, fmt.Errorf("only SELECT, INSERT, UPDATE, and DELETE are supported")
+	}
+	var cols []*ddlColumn
+	for _, item := range items {
+		c, err := p.sub(item).viewColumn(refs)
+		switch last := item[len(item)-1]; {
+		case err == nil || len(c) != 0:
+		case last.typ == ddlPunct && last.val == "*":
+			return nil, err
+		default:
+			c = []*ddlColumn{nil}
+		}
+		cols = append(cols, c...)
+	}
+	return cols, nil
+}
+
+// paramTypes determines the types of the params of a SELECT, INSERT, UPDATE,
+// or DELETE from the columns they are compared with, assigned to, or inserted
+// into, and from casts and LIMIT and OFFSET clauses. Params are placeholders
+// in the form of the loader's mask (ie, '?' or '$%d'). The types of params
+// that cannot be determined are empty.
+func (p *ddlParser) paramTypes(mask string) ([]string, error) {
+	toks := p.toks
+	// find params
+	params := make(map[int]int) // token index of each param, by number
+	prefix, numbered := strings.CutSuffix(mask, "%d")
+	width := 1
+	if numbered {
+		width = 2
+	}
+	for i, t := range toks {
+		switch {
+		case t.typ != ddlPunct || len(prefix) == 0 || t.val != prefix[:1]:
+		case !numbered:
+			params[len(params)] = i
+		case i+1 < len(toks) && toks[i+1].pos == t.end:
+			s, ok := strings.CutPrefix(strings.ToLower(toks[i+1].val), prefix[1:])
+			if n, err := strconv.Atoi(s); ok && err == nil && n > 0 {
+				params[n-1] = i
+			}
+		}
+	}
+	// determine table references and the columns of inserted values
+	var refs []ddlRef
+	values := make(map[int]string) // types of inserted values, by token position
+	switch {
+	case p.accept("SELECT"):
+		var err error
+		if _, refs, err = p.selectList(); err != nil {
+			return nil, err
+		}
+	case p.acceptAny("INSERT", "REPLACE", "UPDATE", "DELETE"):
+		insert := !ddlIn(p.toks[p.i-1].val, "UPDATE", "DELETE")
+		p.acceptAny("INTO", "FROM")
+		p.accept("ONLY")
+		schema, name, err := p.qualified()
+		if err != nil {
+			return nil, err
+		}
+		ref := ddlRef{alias: name, table: p.cat.table(schema, name)}
+		p.accept("AS")
+		if t := p.peek(0); t.typ == ddlIdent || t.typ == ddlWord && !ddlIn(t.val, "SET", "WHERE", "USING", "RETURNING", "VALUES", "SELECT", "DEFAULT", "OVERRIDING", "ON") {
+			ref.alias, _ = p.name()
+		}
+		refs = []ddlRef{ref}
+		if !insert || ref.table == nil {
+			break
+		}
+		cols := ref.table.columns
+		if p.isPunct("(") {
+			names, err := p.columnList()
+			if err != nil {
+				return nil, err
+			}
+			cols = make([]*ddlColumn, len(names))
+			for i, name := range names {
+				for _, c := range ref.table.columns {
+					if strings.EqualFold(c.name, name) {
+						cols[i] = c
+					}
+				}
+			}
+		}
+		if !p.accept("VALUES") {
+			break
+		}
+		for p.isPunct("(") {
+			items, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			for i, item := range items {
+				if i < len(cols) && cols[i] != nil {
+					values[item[0].pos] = cols[i].typ
+				}
+			}
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	default:
+		return nil, fmt.Errorf("only SELECT, INSERT, UPDATE, and DELETE are supported")
+	}
+	typs := make([]string, len(params))
+	for n, i := range params {
+		if n < len(typs) {
+			typs[n] = p.paramType(refs, values, i, i+width)
+		}
+	}
+	return typs, nil
+}
+
+// paramType determines the type of the param in tokens [i, end).
+func (p *ddlParser) paramType(refs []ddlRef, values map[int]string, i, end int) string {
+	toks := p.toks
+	is := func(j int, v ...string) bool {
+		return 0 <= j && j < len(toks) && toks[j].typ != ddlString && toks[j].typ != ddlIdent && ddlIn(toks[j].val, v...)
+	}
+	// casts
+	if is(end, "::") || is(i-2, "CAST") && is(i-1, "(") && is(end, "AS") {
+		q := p.sub(toks[end+1:])
+		if typ, _, _, err := q.dataType(); err == nil {
+			return typ
+		}
+	}
+	// inserted values
+	if typ, ok := values[toks[i].pos]; ok && (end == len(toks) || is(end, ",", ")")) {
+		return typ
+	}
+	// LIMIT and OFFSET
+	if is(i-1, "LIMIT", "OFFSET") || p.cat.driver == "mysql" && is(i-1, ",") && is(i-3, "LIMIT") {
+		typ, _ := ddlNormalize(p.cat.driver, []string{"integer"}, "")
+		return typ
+	}
+	// col = param, col LIKE param, col BETWEEN param AND param
+	j := i - 1
+	for is(j, "=", "<", ">", "!") {
+		j--
+	}
+	switch {
+	case j != i-1:
+	case is(j, "LIKE", "ILIKE", "GLOB", "BETWEEN"):
+		j--
+	case is(j, "AND") && (is(j-2, "BETWEEN") || is(j-3, "BETWEEN")):
+		for j--; !is(j, "BETWEEN"); j-- {
+		}
+		j--
+	}
+	if is(j, "NOT") {
+		j--
+	}
+	if j != i-1 {
+		if typ := p.columnType(refs, j); typ != "" {
+			return typ
+		}
+	}
+	// param = col
+	if j = end; is(j, "=", "<", ">", "!") {
+		for is(j, "=", "<", ">", "!") {
+			j++
+		}
+		if is(j+1, ".") {
+			j += 2
+		}
+		if typ := p.columnType(refs, j); typ != "" {
+			return typ
+		}
+	}
+	// col IN (value, ..., param, ...)
+	if !is(i-1, "(", ",") {
+		return ""
+	}
+	for j := i - 1; j >= 0; j-- {
+		switch t := toks[j]; {
+		case is(j, "("):
+			if j--; !is(j, "IN") {
+				return ""
+			}
+			if is(j-1, "NOT") {
+				j--
+			}
+			return p.columnType(refs, j-1)
+		case is(j, ")"), t.typ == ddlIdent,
+			t.typ == ddlWord && (j == 0 || toks[j-1].typ != ddlPunct || toks[j-1].end != t.pos):
+			return ""
+		}
+	}
+	return ""
+}
+
+// columnType returns the type of the column referenced by the (optionally
+// qualified) column name ending at token i.
+func (p *ddlParser) columnType(refs []ddlRef, i int) string {
+	if i < 0 || i >= len(p.toks) || p.toks[i].typ != ddlWord && p.toks[i].typ != ddlIdent {
+		return ""
+	}
+	name, _ := p.sub(p.toks[i : i+1]).name()
+	var alias string
+	if i > 1 && p.toks[i-1].typ == ddlPunct && p.toks[i-1].val == "." {
+		alias, _ = p.sub(p.toks[i-2 : i-1]).name()
+	}
+	for _, ref := range refs {
+		if ref.table == nil || alias != "" && alias != ref.alias && alias != ref.table.name {
+			continue
+		}
+		for _, c := range ref.table.columns {
+			if strings.EqualFold(c.name, name) {
+				return c.typ
+			}
+		}
+	}
+	return ""
+}
+
+// selectList parses the select list items and the table references of a
+// SELECT.
+func (p *ddlParser) selectList() ([][]ddlToken, []ddlRef, error) {
+	if p.accept("DISTINCT", "ON") {
+		if err := p.skipGroup(); err != nil {
+			return nil, nil, err
+		}
+	}
+	p.acceptAny("DISTINCT", "ALL")
+	// split select list
+	var items [][]ddlToken
+	start, depth := p.i, 0
+	for ; p.more() && (depth != 0 || !p.isAny("FROM", "UNION", "EXCEPT", "INTERSECT", "WHERE")); p.i++ {
+		switch t := p.peek(0); {
+		case t.typ != ddlPunct:
+		case t.val == "(":
+			depth++
+		case t.val == ")":
+			depth--
+		case t.val == "," && depth == 0:
+			items = append(items, p.toks[start:p.i])
+			start = p.i + 1
+		}
+	}
+	if start < p.i {
+		items = append(items, p.toks[start:p.i])
+	}
+	refs, err := p.fromRefs()
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, refs, nil
+}
+
+// ddlRef is a table reference in a FROM clause.
+type ddlRef struct {
+	alias    string
+	table    *ddlTable
+	nullable bool // on the outer side of a join
+}
+
+// fromRefs parses the table references of a FROM clause.
+func (p *ddlParser) fromRefs() ([]ddlRef, error) {
+	if !p.accept("FROM") {
+		return nil, nil
+	}
+	return p.refList()
+}
+
+// refList parses a list of table references and joins. References on the
+// outer side of a LEFT, RIGHT, or FULL join are marked nullable.
+func (p *ddlParser) refList() ([]ddlRef, error) {
+	var refs []ddlRef
+	var join string
+	for p.more() {
+		// table, subquery, or parenthesized join
+		var ref ddlRef
+		var item []ddlRef
+		switch {
+		case p.isPunct("(") && p.peek(1).typ == ddlWord && ddlIn(p.peek(1).val, "SELECT", "WITH", "VALUES"):
+			if err := p.skipGroup(); err != nil {
+				return nil, err
+			}
+		case p.isPunct("("):
+			start := p.i + 1
+			if err := p.skipGroup(); err != nil {
+				return nil, err
+			}
+			r, err := p.sub(p.toks[start : p.i-1]).refList()
+			if err != nil {
+				return nil, err
+			}
+			item = r
+		default:
+			schema, name, err := p.qualified()
+			if err != nil {
+				return nil, err
+			}
+			ref.alias, ref.table = name, p.cat.table(schema, name)
+		}
+		// alias
+		p.accept("AS")
+		if t := p.peek(0); t.typ == ddlIdent || t.typ == ddlWord && !ddlIn(t.val, ddlFromWords...) {
+			ref.alias, _ = p.name()
+		}
+		if ref.alias != "" {
+			item = append(item, ref)
+		}
+		for i := range item {
+			item[i].nullable = item[i].nullable || join == "LEFT" || join == "FULL"
+		}
+		refs, join = append(refs, item...), ""
+		// skip to next reference
+		for depth := 0; p.more(); p.i++ {
+			t := p.peek(0)
+			if depth == 0 && t.typ == ddlWord && ddlIn(t.val, "WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "UNION", "EXCEPT", "INTERSECT", "WINDOW", "OFFSET", "FETCH", "RETURNING") {
+				return refs, nil
+			}
+			if depth == 0 && t.typ == ddlWord && ddlIn(t.val, "LEFT", "RIGHT", "FULL") && (p.peek(1).typ == ddlWord && ddlIn(p.peek(1).val, "OUTER", "JOIN")) {
+				join = strings.ToUpper(t.val)
+			}
+			if depth == 0 && (t.typ == ddlPunct && t.val == "," || t.typ == ddlWord && strings.EqualFold(t.val, "JOIN")) {
+				p.i++
+				break
+			}
+			switch {
+			case t.typ == ddlPunct && t.val == "(":
+				depth++
+			case t.typ == ddlPunct && t.val == ")":
+				depth--
+			}
+		}
+		// the preceding references are the outer side of a RIGHT or FULL join
+		if join == "RIGHT" || join == "FULL" {
+			for i := range refs {
+				refs[i].nullable = true
+			}
+		}
+	}
+	return refs, nil
+}
+
+// ddlFromWords are words that cannot be a table alias in a FROM clause.
+var ddlFromWords = []string{
+	"JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", "OUTER",
+	"ON", "USING", "WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "UNION",
+	"EXCEPT", "INTERSECT", "WINDOW", "OFFSET", "FETCH", "LATERAL",
+}
+
+// viewColumn determines the view columns for a select list item.
+func (p *ddlParser) viewColumn(refs []ddlRef) ([]*ddlColumn, error) {
+	toks := p.toks
+	// determine alias
+	var alias string
+	hasCast := false
+	for depth, i := 0, 0; i < len(toks); i++ {
+		switch t := toks[i]; {
+		case t.typ == ddlPunct && t.val == "(":
+			depth++
+		case t.typ == ddlPunct && t.val == ")":
+			depth--
+		case t.typ == ddlPunct && t.val == "::" && depth == 0:
+			hasCast = true
+		}
+	}
+	n := len(toks)
+	switch last := toks[n-1]; {
+	case n > 2 && toks[n-2].typ == ddlWord && strings.EqualFold(toks[n-2].val, "AS"):
+		alias, _ = p.sub(toks[n-1:]).name()
+		n -= 2
+	case n > 1 && !hasCast && (last.typ == ddlWord || last.typ == ddlIdent) && (toks[n-2].typ == ddlWord || toks[n-2].typ == ddlIdent || toks[n-2].typ == ddlPunct && toks[n-2].val == ")"):
+		alias, _ = p.sub(toks[n-1:]).name()
+		n--
+	}
+	toks, p.toks = toks[:n], toks[:n]
+	// copy ref columns
+	col := func(r ddlRef, c *ddlColumn) *ddlColumn {
+		v := &ddlColumn{
+			name:    c.name,
+			typ:     c.typ,
+			notNull: c.notNull && !r.nullable,
+		}
+		if alias != "" {
+			v.name = alias
+		}
+		return v
+	}
+	find := func(ref string) ([]ddlRef, error) {
+		if ref == "" {
+			return refs, nil
+		}
+		for _, r := range refs {
+			if r.alias == ref {
+				return []ddlRef{r}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown table %q", ref)
+	}
+	switch {
+	case n == 1 && toks[0].typ == ddlPunct && toks[0].val == "*",
+		n == 3 && toks[2].typ == ddlPunct && toks[2].val == "*":
+		var ref string
+		if n == 3 {
+			ref, _ = p.name()
+		}
+		r, err := find(ref)
+		if err != nil {
+			return nil, err
+		}
+		var cols []*ddlColumn
+		for _, z := range r {
+			if z.table == nil {
+				return nil, fmt.Errorf("unknown columns for %q", z.alias)
+			}
+			for _, c := range z.table.columns {
+				cols = append(cols, col(z, c))
+			}
+		}
+		return cols, nil
+	case n == 1 && (toks[0].typ == ddlWord || toks[0].typ == ddlIdent),
+		n == 3 && toks[1].typ == ddlPunct && toks[1].val == ".":
+		var ref string
+		if n == 3 {
+			ref, _ = p.name()
+			p.i++
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		r, err := find(ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, z := range r {
+			if z.table == nil {
+				continue
+			}
+			if c := z.table.column(name); c != nil {
+				return []*ddlColumn{col(z, c)}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown column %q", name)
+	}
+	// expressions, returned along with any error determining the type or
+	// name, as the nullability is still known
+	c := &ddlColumn{
+		name:    alias,
+		notNull: p.sub(toks).notNullExpr(refs),
+	}
+	typ, err := p.cast()
+	switch {
+	case err != nil:
+		return []*ddlColumn{c}, err
+	case alias == "":
+		return []*ddlColumn{c}, fmt.Errorf("expression requires an alias")
+	}
+	c.typ = typ
+	return []*ddlColumn{c}, nil
+}
+
+// notNullExpr returns true when the expression cannot be null: literals,
+// counts, columns that are not null, and coalesces of an expression that is
+// not null. Casts of such expressions are also not null.
+func (p *ddlParser) notNullExpr(refs []ddlRef) bool {
+	toks := p.toks
+	// strip expr::type
+	for i, depth := 0, 0; i < len(toks); i++ {
+		switch t := toks[i]; {
+		case t.typ == ddlPunct && t.val == "(":
+			depth++
+		case t.typ == ddlPunct && t.val == ")":
+			depth--
+		case t.typ == ddlPunct && t.val == "::" && depth == 0:
+			return i != 0 && p.sub(toks[:i]).notNullExpr(refs)
+		}
+	}
+	n := len(toks)
+	switch {
+	case n == 1 && (toks[0].typ == ddlString || toks[0].typ == ddlNumber),
+		n == 1 && toks[0].typ == ddlWord && ddlIn(toks[0].val, "TRUE", "FALSE"):
+		return true
+	case n == 1 && (toks[0].typ == ddlWord || toks[0].typ == ddlIdent),
+		n == 3 && toks[1].typ == ddlPunct && toks[1].val == ".":
+		cols, err := p.viewColumn(refs)
+		return err == nil && len(cols) == 1 && cols[0].notNull
+	case n > 2 && toks[0].typ == ddlPunct && toks[0].val == "(":
+		q := p.sub(toks)
+		items, err := q.group()
+		return err == nil && !q.more() && len(items) == 1 && p.sub(items[0]).notNullExpr(refs)
+	case n < 3 || toks[0].typ != ddlWord || toks[1].typ != ddlPunct || toks[1].val != "(":
+		return false
+	}
+	// function calls
+	q := p.sub(toks[1:])
+	args, err := q.group()
+	switch {
+	case err != nil || q.more():
+		return false
+	case ddlIn(toks[0].val, "COUNT"):
+		return true
+	case ddlIn(toks[0].val, "CAST") && len(args) == 1:
+		for i, depth := len(args[0])-1, 0; i > 0; i-- {
+			switch t := args[0][i]; {
+			case t.typ == ddlPunct && t.val == ")":
+				depth++
+			case t.typ == ddlPunct && t.val == "(":
+				depth--
+			case depth == 0 && t.typ == ddlWord && strings.EqualFold(t.val, "AS"):
+				return p.sub(args[0][:i]).notNullExpr(refs)
+			}
+		}
+	case ddlIn(toks[0].val, "COALESCE", "IFNULL", "NVL", "ISNULL") && len(args) > 1:
+		for _, arg := range args {
+			if p.sub(arg).notNullExpr(refs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cast determines the type of a casted expression (ie, 'expr::type' or
+// 'CAST(expr AS type)').
+func (p *ddlParser) cast() (string, error) {
+	// expr::type
+	for i := len(p.toks) - 1; i >= 0; i-- {
+		if t := p.toks[i]; t.typ == ddlPunct && t.val == "::" {
+			q := p.sub(p.toks[i+1:])
+			typ, _, _, err := q.dataType()
+			if err != nil || q.more() {
+				break
+			}
+			return typ, nil
+		}
+	}
+	// functions with a fixed result type
+	if t := p.peek(0); t.typ == ddlWord && p.peek(1).typ == ddlPunct && p.peek(1).val == "(" {
+		q := p.sub(p.toks[p.i+1:])
+		if _, err := q.group(); err == nil && !q.more() {
+			if typ, ok := ddlFuncTypes[p.cat.driver][strings.ToLower(t.val)]; ok {
+				typ, _ = ddlNormalize(p.cat.driver, []string{typ}, "")
+				return typ, nil
+			}
+		}
+	}
+	// CONVERT(type, expr)
+	if p.cat.driver == "sqlserver" && p.is("CONVERT") && p.peek(1).typ == ddlPunct && p.peek(1).val == "(" {
+		q := p.sub(p.toks[p.i+1:])
+		if items, err := q.group(); err == nil && !q.more() && len(items) > 1 {
+			q := p.sub(items[0])
+			if typ, _, _, err := q.dataType(); err == nil && !q.more() {
+				return typ, nil
+			}
+		}
+	}
+	// CAST(expr AS type)
+	if p.accept("CAST") && p.isPunct("(") && p.toks[len(p.toks)-1].val == ")" {
+		inner := p.toks[p.i+1 : len(p.toks)-1]
+		for i, depth := len(inner)-1, 0; i >= 0; i-- {
+			switch t := inner[i]; {
+			case t.typ == ddlPunct && t.val == ")":
+				depth++
+			case t.typ == ddlPunct && t.val == "(":
+				depth--
+			case depth == 0 && t.typ == ddlWord && strings.EqualFold(t.val, "AS"):
+				q := p.sub(inner[i+1:])
+				typ, _, _, err := q.dataType()
+				if err != nil || q.more() {
+					return "", fmt.Errorf("unable to determine type")
+				}
+				return typ, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unable to determine type")
+}
+
+// ddlFuncTypes are the result types of functions, by driver.
+var ddlFuncTypes = map[string]map[string]string{
+	"sqlserver": {
+		"len": "int", "datalength": "int", "charindex": "int", "datediff": "int",
+		"year": "int", "month": "int", "day": "int",
+		"upper": "nvarchar", "lower": "nvarchar", "ltrim": "nvarchar", "rtrim": "nvarchar",
+		"trim": "nvarchar", "left": "nvarchar", "right": "nvarchar", "substring": "nvarchar",
+		"replace": "nvarchar", "concat": "nvarchar",
+		"getdate": "datetime", "getutcdate": "datetime", "sysdatetime": "datetime2",
+	},
+}
+
+// alterTable parses an ALTER TABLE statement.
+func (p *ddlParser) alterTable() error {
+	p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	t := p.cat.table(schema, name)
+	if t == nil {
+		return nil
+	}
+	for _, item := range p.split() {
+		if err := p.sub(item).alterAction(t); err != nil {
+			return fmt.Errorf("table %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// alterAction parses an ALTER TABLE action.
+func (p *ddlParser) alterAction(t *ddlTable) error {
+	switch {
+	case p.accept("ADD"):
+		if p.isConstraint() {
+			return p.tableConstraint(t)
+		}
+		p.accept("COLUMN")
+		if p.accept("IF", "NOT", "EXISTS") {
+			if name, err := p.sub(p.toks[p.i:]).name(); err == nil && t.column(name) != nil {
+				return nil
+			}
+		}
+		return p.columnDef(t)
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		c := t.column(name)
+		if c == nil {
+			return fmt.Errorf("column %q not defined", name)
+		}
+		switch {
+		case p.accept("SET", "DEFAULT"):
+			start := p.i
+			def := p.expr()
+			c.setDefault(p.cat.driver, p.toks[start:p.i], def)
+		case p.accept("DROP", "DEFAULT"):
+			c.def.Valid = false
+		case p.accept("SET", "NOT", "NULL"):
+			c.notNull = true
+		case p.accept("DROP", "NOT", "NULL"):
+			c.notNull = false
+		case p.accept("ADD", "GENERATED"):
+			c.sequence = true
+		case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+			if c.typ, _, _, err = p.dataType(); err != nil {
+				return err
+			}
+		}
+	case p.accept("DROP", "CONSTRAINT"):
+		p.accept("IF", "EXISTS")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		t.dropConstraint(name)
+	case p.accept("DROP"):
+		p.accept("COLUMN")
+		p.accept("IF", "EXISTS")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		t.dropColumn(name)
+	}
+	return nil
+}
+
+// drop parses a DROP statement.
+func (p *ddlParser) drop() error {
+	var kind string
+	switch {
+	case p.acceptAny("TABLE", "VIEW", "INDEX", "TYPE"):
+		kind = strings.ToUpper(p.toks[p.i-1].val)
+	case p.accept("MATERIALIZED", "VIEW"), p.accept("FOREIGN", "TABLE"):
+		kind = strings.ToUpper(p.toks[p.i-1].val)
+	case p.accept("DOMAIN"):
+		kind = "DOMAIN"
+	default:
+		return nil
+	}
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "EXISTS")
+	for p.more() {
+		schema, name, err := p.qualified()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case "TABLE", "VIEW", "DOMAIN":
+			p.cat.dropTable(schema, name)
+		case "INDEX":
+			for _, t := range p.cat.tables {
+				t.dropConstraint(name)
+			}
+		case "TYPE":
+			p.cat.dropEnum(schema, name)
+			if t := p.cat.table(schema, name); t != nil && t.typ == "composite type" {
+				p.cat.dropTable(schema, name)
+			}
+		}
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	return nil
+}
+
+// commentTable parses a COMMENT ON TABLE or VIEW statement.
+func (p *ddlParser) commentTable() error {
+	schema, name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("IS"); err != nil {
+		return err
+	}
+	if t := p.cat.table(schema, name); t != nil {
+		t.comment = ""
+		if tok := p.peek(0); tok.typ == ddlString {
+			t.comment = tok.val
+		}
+	}
+	return nil
+}
+
+// commentColumn parses a COMMENT ON COLUMN statement.
+func (p *ddlParser) commentColumn() error {
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		if !p.acceptPunct(".") {
+			break
+		}
+	}
+	if len(names) < 2 {
+		return fmt.Errorf("expected table column, got %q", strings.Join(names, "."))
+	}
+	if err := p.expect("IS"); err != nil {
+		return err
+	}
+	var schema string
+	if len(names) > 2 {
+		schema = names[len(names)-3]
+	}
+	t := p.cat.table(schema, names[len(names)-2])
+	if t == nil {
+		return nil
+	}
+	if c := t.column(names[len(names)-1]); c != nil {
+		c.comment = ""
+		if tok := p.peek(0); tok.typ == ddlString {
+			c.comment = tok.val
+		}
+	}
+	return nil
+}
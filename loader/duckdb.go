@@ -0,0 +1,246 @@
+package loader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xo/xo/models"
+	xo "github.com/xo/xo/types"
+)
+
+func init() {
+	Register("duckdb", Loader{
+		Mask:             "$%d",
+		MaxParams:        65535,
+		Schema:           models.DuckdbSchema,
+		Schemas:          models.DuckdbSchemas,
+		Fingerprint:      models.DuckdbFingerprint,
+		Tables:           models.DuckdbTables,
+		TableColumns:     DuckdbTableColumns,
+		TableSequences:   models.DuckdbTableSequences,
+		TableForeignKeys: models.DuckdbTableForeignKeys,
+		TableIndexes:     models.DuckdbTableIndexes,
+		TableChecks:      models.DuckdbTableChecks,
+		IndexColumns:     DuckdbIndexColumns,
+		ViewCreate:       models.DuckdbViewCreate,
+		ViewSchema:       models.DuckdbViewSchema,
+		ViewDrop:         models.DuckdbViewDrop,
+		QueryColumns:     DuckdbQueryColumns,
+	})
+}
+
+// DuckdbQueryColumns returns the result columns of a query, using DESCRIBE to
+// bind the query without running it.
+func DuckdbQueryColumns(ctx context.Context, db *sql.DB, query string) ([]*models.Column, error) {
+	sqlstr := `DESCRIBE ` + query
+	models.Logf(sqlstr)
+	rows, err := db.QueryContext(ctx, sqlstr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cols []*models.Column
+	for rows.Next() {
+		var name, typ string
+		var null, key, def, extra sql.NullString
+		if err := rows.Scan(&name, &typ, &null, &key, &def, &extra); err != nil {
+			return nil, err
+		}
+		cols = append(cols, &models.Column{
+			ColumnName: name,
+			DataType:   strings.ToLower(typ),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// DuckdbTableColumns returns the columns for the table, using the expression
+// of generated columns parsed from the table's definition as their default
+// value.
+func DuckdbTableColumns(ctx context.Context, db models.DB, schema, table string) ([]*models.Column, error) {
+	cols, err := models.DuckdbTableColumns(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	// duckdb reports the expression of generated columns as their default
+	var defaults bool
+	for _, c := range cols {
+		defaults = defaults || c.DefaultValue.Valid
+	}
+	if !defaults {
+		return cols, nil
+	}
+	cat, err := duckdbCatalog(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	t, err := cat.lookup(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cols {
+		if z := t.column(c.ColumnName); z != nil && z.generated {
+			c.IsGenerated, c.DefaultValue = true, z.def
+		}
+	}
+	return cols, nil
+}
+
+// DuckdbIndexColumns returns the columns for the index. Columns of indexes
+// created with CREATE INDEX are parsed from the index's definition, as duckdb
+// only exposes the columns of constraint indexes.
+func DuckdbIndexColumns(ctx context.Context, db models.DB, schema, table, index string) ([]*models.IndexColumn, error) {
+	cols, err := models.DuckdbIndexColumns(ctx, db, schema, table, index)
+	if err != nil || len(cols) != 0 {
+		return cols, err
+	}
+	def, err := models.DuckdbIndexSQL(ctx, db, schema, index)
+	if err != nil || def == "" {
+		return nil, err
+	}
+	cat, err := duckdbCatalog(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if err := cat.parse(def); err != nil {
+		return nil, fmt.Errorf("index %q: %w", index, err)
+	}
+	return cat.IndexColumns(ctx, db, schema, table, index)
+}
+
+// duckdbCatalog returns a catalog parsed from the table's definition.
+func duckdbCatalog(ctx context.Context, db models.DB, schema, table string) (*ddlCatalog, error) {
+	def, err := models.DuckdbTableSQL(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	cat := &ddlCatalog{
+		driver: "duckdb",
+		def:    schema,
+	}
+	if err := cat.parse(def); err != nil {
+		return nil, fmt.Errorf("table %q: %w", table, err)
+	}
+	return cat, nil
+}
+
+// DuckdbGoType parse a duckdb type into a Go type based on the column
+// definition.
+//
+// Nested types are mapped to the types the duckdb driver scans them as: LIST
+// and ARRAY to []any, STRUCT to map[string]any, MAP to duckdb.Map, and UNION
+// to any.
+func DuckdbGoType(d xo.Type, schema, itype, utype string) (string, string, error) {
+	if d.IsArray || duckdbArrayRE.MatchString(d.Type) {
+		return "[]any", "nil", nil
+	}
+	typ := d.Type
+	if i := strings.IndexByte(typ, '('); i != -1 {
+		typ = strings.TrimSpace(typ[:i])
+	}
+	var goType, zero string
+	switch typ {
+	case "boolean", "bool", "logical":
+		goType, zero = "bool", "false"
+		if d.Nullable {
+			goType, zero = "sql.NullBool", "sql.NullBool{}"
+		}
+	case "tinyint", "int1":
+		goType, zero = "int8", "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "smallint", "int2", "short":
+		goType, zero = "int16", "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "integer", "int4", "int", "signed":
+		goType, zero = itype, "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "bigint", "int8", "long":
+		goType, zero = "int64", "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "utinyint":
+		goType, zero = "uint8", "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "usmallint":
+		goType, zero = "uint16", "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "uinteger":
+		goType, zero = utype, "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "ubigint":
+		goType, zero = "uint64", "0"
+		if d.Nullable {
+			goType, zero = "sql.NullInt64", "sql.NullInt64{}"
+		}
+	case "hugeint", "uhugeint", "int128", "uint128":
+		goType, zero = "*big.Int", "nil"
+	case "float", "float4", "real":
+		goType, zero = "float32", "0.0"
+		if d.Nullable {
+			goType, zero = "sql.NullFloat64", "sql.NullFloat64{}"
+		}
+	case "double", "float8":
+		goType, zero = "float64", "0.0"
+		if d.Nullable {
+			goType, zero = "sql.NullFloat64", "sql.NullFloat64{}"
+		}
+	case "decimal", "numeric":
+		goType, zero = "duckdb.Decimal", "duckdb.Decimal{}"
+		if d.Nullable {
+			goType, zero = "*duckdb.Decimal", "nil"
+		}
+	case "blob", "bytea", "binary", "varbinary":
+		goType, zero = "[]byte", "nil"
+	case "date", "time", "timestamp", "datetime", "timestamptz", "timestamp with time zone",
+		"timestamp_s", "timestamp_ms", "timestamp_ns", "timestamp_us":
+		goType, zero = "time.Time", "time.Time{}"
+		if d.Nullable {
+			goType, zero = "sql.NullTime", "sql.NullTime{}"
+		}
+	case "interval":
+		goType, zero = "duckdb.Interval", "duckdb.Interval{}"
+		if d.Nullable {
+			goType, zero = "*duckdb.Interval", "nil"
+		}
+	case "uuid":
+		goType, zero = "uuid.UUID", "uuid.UUID{}"
+		if d.Nullable {
+			goType, zero = "uuid.NullUUID", "uuid.NullUUID{}"
+		}
+	case "struct":
+		goType, zero = "map[string]any", "nil"
+	case "map":
+		goType, zero = "duckdb.Map", "nil"
+	case "union":
+		goType, zero = "any", "nil"
+	default:
+		// case "varchar", "text", "string", "char", "bpchar", "enum", "json", "bit":
+		goType, zero = "string", `""`
+		if d.Nullable {
+			goType, zero = "sql.NullString", "sql.NullString{}"
+		}
+	}
+	return goType, zero, nil
+}
+
+// duckdbArrayRE matches duckdb fixed size array types.
+var duckdbArrayRE = regexp.MustCompile(`\[[0-9]+\]$`)
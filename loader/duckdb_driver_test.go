@@ -0,0 +1,95 @@
+//go:build duckdb
+
+package loader
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "github.com/marcboeker/go-duckdb"
+)
+
+func TestDuckdb(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	// in-memory databases are per connection
+	db.SetMaxOpenConns(1)
+	ddl := []string{
+		`CREATE SEQUENCE authors_author_id_seq`,
+		`CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY DEFAULT nextval('authors_author_id_seq'),
+  name VARCHAR NOT NULL UNIQUE,
+  name_len INTEGER GENERATED ALWAYS AS (length(name)) VIRTUAL,
+  born DATE,
+  UNIQUE (author_id, born)
+)`,
+		`COMMENT ON TABLE authors IS 'Book authors.'`,
+		`CREATE TABLE "Books" (
+  isbn VARCHAR PRIMARY KEY,
+  author_id INTEGER NOT NULL REFERENCES authors (author_id),
+  author_born DATE,
+  title VARCHAR NOT NULL CHECK (length(title) > 0),
+  FOREIGN KEY (author_id, author_born) REFERENCES authors (author_id, born)
+)`,
+		`CREATE INDEX books_title_idx ON "Books" (title)`,
+		`CREATE UNIQUE INDEX books_lower_title ON "Books" (author_id, (lower(title)))`,
+		`CREATE VIEW book_titles AS SELECT isbn, title FROM "Books"`,
+	}
+	for _, q := range ddl {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	lines, err := ddlDump(loaders["duckdb"], db)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := []string{
+		"schema main",
+		"table Books",
+		"  column isbn VARCHAR not null pk",
+		"  column author_id INTEGER not null",
+		"  column author_born DATE",
+		"  column title VARCHAR not null",
+		"  index Books_pkey [isbn] unique primary",
+		"  index books_lower_title [author_id (lower(title))] unique",
+		"  index books_title_idx [title]",
+		"  fk Books_author_id_fkey author_id authors.author_id",
+		"  fk Books_author_id_author_born_fkey author_id authors.author_id",
+		"  fk Books_author_id_author_born_fkey author_born authors.born",
+		"  check Books_title_check (length(title) > 0)",
+		"table authors -- Book authors.",
+		"  column author_id INTEGER not null default nextval('authors_author_id_seq') pk",
+		"  column name VARCHAR not null",
+		"  column name_len INTEGER default length(\"name\") generated",
+		"  column born DATE",
+		"  sequence author_id",
+		"  index authors_pkey [author_id] unique primary",
+		"  index authors_name_key [name] unique",
+		"  index authors_author_id_born_key [author_id born] unique",
+		"view book_titles",
+		"  column isbn VARCHAR",
+		"  column title VARCHAR",
+	}
+	if s, exp := strings.Join(lines, "\n"), strings.Join(exp, "\n"); s != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, s)
+	}
+	// query introspection
+	ctx := context.Background()
+	cols, err := DuckdbQueryColumns(ctx, db, `SELECT author_id, CAST(1.5 AS DECIMAL(10, 2)) AS price, [1, 2] AS l FROM authors`)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var typs []string
+	for _, c := range cols {
+		typs = append(typs, c.ColumnName+" "+c.DataType)
+	}
+	if s, exp := strings.Join(typs, ", "), "author_id integer, price decimal(10,2), l integer[]"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}
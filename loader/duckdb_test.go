@@ -0,0 +1,62 @@
+package loader
+
+import (
+	"testing"
+
+	xo "github.com/xo/xo/types"
+)
+
+func TestDuckdbGoType(t *testing.T) {
+	tests := []struct {
+		typ      string
+		nullable bool
+		goType   string
+		zero     string
+	}{
+		{"BOOLEAN", false, "bool", "false"},
+		{"BOOLEAN", true, "sql.NullBool", "sql.NullBool{}"},
+		{"TINYINT", false, "int8", "0"},
+		{"INTEGER", false, "int", "0"},
+		{"INTEGER", true, "sql.NullInt64", "sql.NullInt64{}"},
+		{"UINTEGER", false, "uint", "0"},
+		{"UBIGINT", false, "uint64", "0"},
+		{"HUGEINT", false, "*big.Int", "nil"},
+		{"FLOAT", false, "float32", "0.0"},
+		{"DOUBLE", true, "sql.NullFloat64", "sql.NullFloat64{}"},
+		{"DECIMAL(18,3)", false, "duckdb.Decimal", "duckdb.Decimal{}"},
+		{"DECIMAL(18,3)", true, "*duckdb.Decimal", "nil"},
+		{"VARCHAR", true, "sql.NullString", "sql.NullString{}"},
+		{"BLOB", false, "[]byte", "nil"},
+		{"TIMESTAMP WITH TIME ZONE", false, "time.Time", "time.Time{}"},
+		{"DATE", true, "sql.NullTime", "sql.NullTime{}"},
+		{"INTERVAL", false, "duckdb.Interval", "duckdb.Interval{}"},
+		{"UUID", true, "uuid.NullUUID", "uuid.NullUUID{}"},
+		{"INTEGER[]", false, "[]any", "nil"},
+		{"VARCHAR[3]", false, "[]any", "nil"},
+		{"STRUCT(A INTEGER, b VARCHAR)", false, "map[string]any", "nil"},
+		{"MAP(VARCHAR, INTEGER)", false, "duckdb.Map", "nil"},
+		{"UNION(num INTEGER, str VARCHAR)", false, "any", "nil"},
+		{"ENUM('Happy', 'sad')", false, "string", `""`},
+	}
+	for i, test := range tests {
+		d, err := xo.ParseType(test.typ, "duckdb")
+		if err != nil {
+			t.Fatalf("test %d %q expected no error, got: %v", i, test.typ, err)
+		}
+		d.Nullable = test.nullable
+		goType, zero, err := DuckdbGoType(d, "main", "int", "uint")
+		if err != nil {
+			t.Fatalf("test %d %q expected no error, got: %v", i, test.typ, err)
+		}
+		if goType != test.goType {
+			t.Errorf("test %d %q (nullable: %t) expected goType = %q, got: %q", i, test.typ, test.nullable, test.goType, goType)
+		}
+		if zero != test.zero {
+			t.Errorf("test %d %q (nullable: %t) expected zero = %q, got: %q", i, test.typ, test.nullable, test.zero, zero)
+		}
+	}
+	// nested types keep the case of their arguments
+	if d, _ := xo.ParseType("STRUCT(A INTEGER)", "duckdb"); d.Type != "struct(A INTEGER)" {
+		t.Errorf("expected %q, got: %q", "struct(A INTEGER)", d.Type)
+	}
+}
@@ -1,10 +1,11 @@
-// Package loader loads query and schema information from mysql, oracle,
-// postgres, sqlite3 and sqlserver databases.
+// Package loader loads query and schema information from duckdb, mysql,
+// oracle, postgres, sqlite3 and sqlserver databases.
 package loader
 
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"sort"
@@ -23,6 +24,13 @@ func Register(typ string, loader Loader) {
 	loaders[typ] = loader
 }
 
+// Lookup returns the loader registered for the database type, allowing a
+// loader to wrap the funcs of another.
+func Lookup(typ string) (Loader, bool) {
+	l, ok := loaders[typ]
+	return l, ok
+}
+
 // Flags returns the additional flags for the loaders.
 //
 // These should be added to the invocation context for any call to a loader
@@ -54,10 +62,17 @@ func Flags() []xo.FlagSet {
 type Loader struct {
 	Type             string
 	Mask             string
+	MaxParams        int
 	Flags            func() []xo.Flag
 	Schema           func(context.Context, models.DB) (string, error)
+	Schemas          func(context.Context, models.DB) ([]*models.Schema, error)
+	Fingerprint      func(context.Context, models.DB, string) (string, error)
 	Enums            func(context.Context, models.DB, string) ([]*models.Enum, error)
 	EnumValues       func(context.Context, models.DB, string, string) ([]*models.EnumValue, error)
+	Domains          func(context.Context, models.DB, string) ([]*models.Domain, error)
+	DomainChecks     func(context.Context, models.DB, string, string) ([]*models.Check, error)
+	Composites       func(context.Context, models.DB, string) ([]*models.Composite, error)
+	CompositeAttrs   func(context.Context, models.DB, string, string) ([]*models.Column, error)
 	Procs            func(context.Context, models.DB, string) ([]*models.Proc, error)
 	ProcParams       func(context.Context, models.DB, string, string) ([]*models.ProcParam, error)
 	Tables           func(context.Context, models.DB, string, string) ([]*models.Table, error)
@@ -65,19 +80,26 @@ type Loader struct {
 	TableSequences   func(context.Context, models.DB, string, string) ([]*models.Sequence, error)
 	TableForeignKeys func(context.Context, models.DB, string, string) ([]*models.ForeignKey, error)
 	TableIndexes     func(context.Context, models.DB, string, string) ([]*models.Index, error)
+	TableChecks      func(context.Context, models.DB, string, string) ([]*models.Check, error)
 	IndexColumns     func(context.Context, models.DB, string, string, string) ([]*models.IndexColumn, error)
 	ViewCreate       func(context.Context, models.DB, string, string, []string) (sql.Result, error)
 	ViewSchema       func(context.Context, models.DB, string) (string, error)
 	ViewTruncate     func(context.Context, models.DB, string, string) (sql.Result, error)
 	ViewDrop         func(context.Context, models.DB, string, string) (sql.Result, error)
 	ViewStrip        func([]string, []string) ([]string, []string, []string, error)
+	QueryColumns     func(context.Context, *sql.DB, string) ([]*models.Column, error)
+	QueryParamTypes  func(context.Context, *sql.DB, string) ([]string, error)
+	GoType           func(xo.Type, string, string, string) (string, string, error)
 }
 
 // get retrieves the database connection, loader, and schema name from the
 // context.
 func get(ctx context.Context) (*sql.DB, *Loader, string, error) {
 	typ, _ := ctx.Value(xo.DriverKey).(string)
-	l, ok := loaders[typ]
+	l, ok := ctx.Value(loaderKey).(Loader)
+	if !ok {
+		l, ok = loaders[typ]
+	}
 	if !ok {
 		return nil, nil, "", fmt.Errorf("no database loader available for %q", typ)
 	}
@@ -107,6 +129,19 @@ func NthParam(ctx context.Context) (func(int) string, error) {
 	}, nil
 }
 
+// MaxParams returns the maximum number of query parameters supported by the
+// database, defaulting to 999.
+func MaxParams(ctx context.Context) (int, error) {
+	_, l, _, err := get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if l.MaxParams == 0 {
+		return 999, nil
+	}
+	return l.MaxParams, nil
+}
+
 // Schema loads the active schema name from the context.
 func Schema(ctx context.Context) (string, error) {
 	db, l, _, err := get(ctx)
@@ -116,6 +151,37 @@ func Schema(ctx context.Context) (string, error) {
 	return l.Schema(ctx, db)
 }
 
+// Schemas returns the database schemas, defaulting to the active schema when
+// the loader does not support listing schemas.
+func Schemas(ctx context.Context) ([]*models.Schema, error) {
+	db, l, _, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.Schemas != nil {
+		return l.Schemas(ctx, db)
+	}
+	schema, err := l.Schema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Schema{{SchemaName: schema}}, nil
+}
+
+// Fingerprint returns a fingerprint of the schema that changes when the schema
+// changes, for use in caching the loaded schema.
+func Fingerprint(ctx context.Context) (string, error) {
+	db, l, schema, err := get(ctx)
+	if err != nil {
+		return "", err
+	}
+	if l.Fingerprint == nil {
+		driver, _, _ := xo.DriverDbSchema(ctx)
+		return "", fmt.Errorf("schema fingerprint is not supported for %s", driver)
+	}
+	return l.Fingerprint(ctx, db, schema)
+}
+
 // Enums returns the database enums.
 func Enums(ctx context.Context) ([]*models.Enum, error) {
 	db, l, schema, err := get(ctx)
@@ -137,6 +203,51 @@ func EnumValues(ctx context.Context, enum string) ([]*models.EnumValue, error) {
 	return l.EnumValues(ctx, db, schema, enum)
 }
 
+// Domains returns the database domains.
+func Domains(ctx context.Context) ([]*models.Domain, error) {
+	db, l, schema, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.Domains != nil {
+		return l.Domains(ctx, db, schema)
+	}
+	return nil, nil
+}
+
+// DomainChecks returns the database domain check constraints.
+func DomainChecks(ctx context.Context, domain string) ([]*models.Check, error) {
+	db, l, schema, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.DomainChecks != nil {
+		return l.DomainChecks(ctx, db, schema, domain)
+	}
+	return nil, nil
+}
+
+// Composites returns the database composite types.
+func Composites(ctx context.Context) ([]*models.Composite, error) {
+	db, l, schema, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.Composites != nil {
+		return l.Composites(ctx, db, schema)
+	}
+	return nil, nil
+}
+
+// CompositeAttrs returns the database composite type attributes.
+func CompositeAttrs(ctx context.Context, composite string) ([]*models.Column, error) {
+	db, l, schema, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return l.CompositeAttrs(ctx, db, schema, composite)
+}
+
 // Procs returns the database procs.
 func Procs(ctx context.Context) ([]*models.Proc, error) {
 	db, l, schema, err := get(ctx)
@@ -206,6 +317,18 @@ func TableIndexes(ctx context.Context, table string) ([]*models.Index, error) {
 	return l.TableIndexes(ctx, db, schema, table)
 }
 
+// TableChecks returns the database table check constraints.
+func TableChecks(ctx context.Context, table string) ([]*models.Check, error) {
+	db, l, schema, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.TableChecks != nil {
+		return l.TableChecks(ctx, db, schema, table)
+	}
+	return nil, nil
+}
+
 // IndexColumns returns the database index columns.
 func IndexColumns(ctx context.Context, table, index string) ([]*models.IndexColumn, error) {
 	db, l, schema, err := get(ctx)
@@ -270,6 +393,136 @@ func ViewStrip(ctx context.Context, query, inspect []string) ([]string, []string
 	return query, inspect, make([]string, len(query)), nil
 }
 
+// QueryColumns returns the result columns of a query by preparing the query,
+// instead of creating an introspection view.
+func QueryColumns(ctx context.Context, query []string) ([]*models.Column, error) {
+	db, l, _, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.QueryColumns == nil {
+		driver, _, _ := xo.DriverDbSchema(ctx)
+		return nil, fmt.Errorf("prepared query introspection is not supported for %s", driver)
+	}
+	return l.QueryColumns(ctx, db, strings.Join(query, "\n"))
+}
+
+// QueryParamTypes returns the database types of a query's parameters, in
+// order. The types are inferred by the database when preparing the query when
+// supported by the loader, and otherwise from the columns of the schema each
+// param is compared with, assigned to, or inserted into. The types of params
+// that cannot be determined are empty.
+func QueryParamTypes(ctx context.Context, query string) ([]string, error) {
+	db, l, _, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.QueryParamTypes != nil {
+		return l.QueryParamTypes(ctx, db, query)
+	}
+	driver, _, _ := xo.DriverDbSchema(ctx)
+	toks, err := ddlLex(driver, query)
+	if err != nil {
+		return nil, err
+	}
+	stmts := ddlSplit(toks)
+	if len(stmts) != 1 {
+		return nil, fmt.Errorf("expected a single statement")
+	}
+	mask := l.Mask
+	if mask == "" {
+		mask = "?"
+	}
+	return (&ddlParser{cat: queryCatalog(ctx), src: query, toks: stmts[0]}).paramTypes(mask)
+}
+
+// GoType returns the loader's mapping of database types to Go types, or nil
+// when the loader does not provide one and the template's mapping for the
+// driver is used.
+func GoType(ctx context.Context) func(xo.Type, string, string, string) (string, string, error) {
+	_, l, _, err := get(ctx)
+	if err != nil {
+		return nil
+	}
+	return l.GoType
+}
+
+// QueryNotNull determines which result columns of a query cannot be null, by
+// tracing each column back to the column of a table. Columns on the outer side
+// of a LEFT, RIGHT, or FULL join, and columns that cannot be traced, are
+// nullable.
+func QueryNotNull(ctx context.Context, query []string) ([]bool, error) {
+	driver, _, _ := xo.DriverDbSchema(ctx)
+	src := strings.Join(query, "\n")
+	toks, err := ddlLex(driver, src)
+	if err != nil {
+		return nil, err
+	}
+	stmts := ddlSplit(toks)
+	if len(stmts) != 1 {
+		return nil, fmt.Errorf("expected a single statement")
+	}
+	cols, err := (&ddlParser{cat: queryCatalog(ctx), src: src, toks: stmts[0]}).queryColumns()
+	if err != nil {
+		return nil, err
+	}
+	notNull := make([]bool, len(cols))
+	for i, c := range cols {
+		notNull[i] = c != nil && c.notNull
+	}
+	return notNull, nil
+}
+
+// queryCatalog returns an empty catalog that loads the columns of tables
+// referenced by a query from the database.
+func queryCatalog(ctx context.Context) *ddlCatalog {
+	driver, _, schema := xo.DriverDbSchema(ctx)
+	return &ddlCatalog{
+		driver: driver,
+		load: func(s, name string) *ddlTable {
+			if s == "" {
+				s = schema
+			}
+			cols, err := TableColumns(context.WithValue(ctx, xo.SchemaKey, s), name)
+			if err != nil || len(cols) == 0 {
+				return nil
+			}
+			t := &ddlTable{schema: s, name: name}
+			for _, c := range cols {
+				t.columns = append(t.columns, &ddlColumn{
+					name:    c.ColumnName,
+					typ:     c.DataType,
+					notNull: c.NotNull,
+				})
+			}
+			return t
+		},
+	}
+}
+
+// prepare prepares query on a driver connection, passing the prepared
+// statement to f. The statement is closed without being run.
+func prepare(ctx context.Context, db *sql.DB, query string, f func(driver.Stmt) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	models.Logf(query)
+	return conn.Raw(func(dc interface{}) error {
+		c, ok := dc.(driver.ConnPrepareContext)
+		if !ok {
+			return fmt.Errorf("driver connection %T does not support prepare", dc)
+		}
+		stmt, err := c.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		return f(stmt)
+	})
+}
+
 // schemaType returns Go type and zero for a type, removing a "<schema>."
 // prefix when the type is determined to be in the same package.
 func schemaType(typ string, nullable bool, schema string) (string, string) {
@@ -277,6 +530,8 @@ func schemaType(typ string, nullable bool, schema string) (string, string) {
 		// in the same schema, so chop off
 		typ = typ[len(schema)+1:]
 	}
+	// in another schema, so prefix with the schema name
+	typ = strings.Replace(typ, ".", "_", -1)
 	if nullable {
 		typ = "null_" + typ
 	}
@@ -12,7 +12,10 @@ import (
 func init() {
 	Register("mysql", Loader{
 		Mask:             "?",
+		MaxParams:        65535,
 		Schema:           models.MysqlSchema,
+		Schemas:          models.MysqlSchemas,
+		Fingerprint:      models.MysqlFingerprint,
 		Enums:            models.MysqlEnums,
 		EnumValues:       MysqlEnumValues,
 		Procs:            models.MysqlProcs,
@@ -22,6 +25,7 @@ func init() {
 		TableSequences:   models.MysqlTableSequences,
 		TableForeignKeys: models.MysqlTableForeignKeys,
 		TableIndexes:     models.MysqlTableIndexes,
+		TableChecks:      models.MysqlTableChecks,
 		IndexColumns:     models.MysqlIndexColumns,
 		ViewCreate:       models.MysqlViewCreate,
 		ViewDrop:         models.MysqlViewDrop,
@@ -10,7 +10,9 @@ import (
 func init() {
 	Register("oracle", Loader{
 		Mask:             ":%d",
+		MaxParams:        65535,
 		Schema:           models.OracleSchema,
+		Schemas:          models.OracleSchemas,
 		Procs:            models.OracleProcs,
 		ProcParams:       models.OracleProcParams,
 		Tables:           models.OracleTables,
@@ -18,6 +20,7 @@ func init() {
 		TableSequences:   models.OracleTableSequences,
 		TableForeignKeys: models.OracleTableForeignKeys,
 		TableIndexes:     models.OracleTableIndexes,
+		TableChecks:      models.OracleTableChecks,
 		IndexColumns:     models.OracleIndexColumns,
 		ViewCreate:       models.OracleViewCreate,
 		ViewTruncate:     models.OracleViewTruncate,
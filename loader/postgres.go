@@ -2,11 +2,13 @@ package loader
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"regexp"
-	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/xo/xo/models"
 	xo "github.com/xo/xo/types"
 )
@@ -14,10 +16,17 @@ import (
 func init() {
 	Register("postgres", Loader{
 		Mask:             "$%d",
+		MaxParams:        65535,
 		Flags:            PostgresFlags,
 		Schema:           models.PostgresSchema,
+		Schemas:          models.PostgresSchemas,
+		Fingerprint:      PostgresFingerprint,
 		Enums:            models.PostgresEnums,
 		EnumValues:       models.PostgresEnumValues,
+		Domains:          models.PostgresDomains,
+		DomainChecks:     models.PostgresDomainChecks,
+		Composites:       models.PostgresComposites,
+		CompositeAttrs:   PostgresTableColumns,
 		Procs:            models.PostgresProcs,
 		ProcParams:       models.PostgresProcParams,
 		Tables:           models.PostgresTables,
@@ -25,11 +34,14 @@ func init() {
 		TableSequences:   models.PostgresTableSequences,
 		TableForeignKeys: models.PostgresTableForeignKeys,
 		TableIndexes:     models.PostgresTableIndexes,
-		IndexColumns:     PostgresIndexColumns,
+		TableChecks:      models.PostgresTableChecks,
+		IndexColumns:     models.PostgresIndexColumns,
 		ViewCreate:       models.PostgresViewCreate,
 		ViewSchema:       models.PostgresViewSchema,
 		ViewDrop:         models.PostgresViewDrop,
 		ViewStrip:        PostgresViewStrip,
+		QueryColumns:     PostgresQueryColumns,
+		QueryParamTypes:  PostgresQueryParamTypes,
 	})
 }
 
@@ -81,6 +93,83 @@ func PQPostgresGoType(d xo.Type, schema, itype, _ string) (string, string, error
 	return goType, zero, nil
 }
 
+// PgxPostgresGoType parses a type into a Go type based on the database type
+// definition, for use with `github.com/jackc/pgx/v5`.
+//
+// Nullable types are mapped to their `github.com/jackc/pgx/v5/pgtype`
+// equivalent, and array types are mapped to a slice of the non-nullable type.
+func PgxPostgresGoType(d xo.Type, schema, itype, _ string) (string, string, error) {
+	// SETOF -> []T
+	if strings.HasPrefix(d.Type, "SETOF ") {
+		d.Type = d.Type[len("SETOF "):]
+		goType, _, err := PgxPostgresGoType(d, schema, itype, "")
+		if err != nil {
+			return "", "", err
+		}
+		return "[]" + goType, "nil", nil
+	}
+	typ := d.Type
+	if typ == `"char"` {
+		typ = "char"
+	}
+	var goType, zero, nullType string
+	switch typ {
+	case "boolean":
+		goType, zero, nullType = "bool", "false", "pgtype.Bool"
+	case "bpchar", "character varying", "character", "char", "money", "text", "name", "time with time zone":
+		goType, zero, nullType = "string", `""`, "pgtype.Text"
+	case "inet", "cidr":
+		goType, zero = "netip.Prefix", "netip.Prefix{}"
+		if d.Nullable && !d.IsArray {
+			return "*netip.Prefix", "nil", nil
+		}
+	case "smallint":
+		goType, zero, nullType = "int16", "0", "pgtype.Int2"
+	case "integer":
+		goType, zero, nullType = itype, "0", "pgtype.Int4"
+	case "bigint":
+		goType, zero, nullType = "int64", "0", "pgtype.Int8"
+	case "real":
+		goType, zero, nullType = "float32", "0.0", "pgtype.Float4"
+	case "double precision":
+		goType, zero, nullType = "float64", "0.0", "pgtype.Float8"
+	case "numeric":
+		// pgtype.Numeric keeps the exact value, and is itself nullable
+		goType, zero, nullType = "pgtype.Numeric", "pgtype.Numeric{}", "pgtype.Numeric"
+	case "date":
+		goType, zero, nullType = "time.Time", "time.Time{}", "pgtype.Date"
+	case "timestamp with time zone":
+		goType, zero, nullType = "time.Time", "time.Time{}", "pgtype.Timestamptz"
+	case "timestamp without time zone":
+		goType, zero, nullType = "time.Time", "time.Time{}", "pgtype.Timestamp"
+	case "time without time zone":
+		goType, zero = "pgtype.Time", "pgtype.Time{}"
+	case "interval":
+		goType, zero = "pgtype.Interval", "pgtype.Interval{}"
+	case "bit", "bit varying":
+		goType, zero = "pgtype.Bits", "pgtype.Bits{}"
+	case "any", "bytea", "json", "jsonb", "xml":
+		goType, zero = "[]byte", "nil"
+	case "hstore":
+		goType, zero = "pgtype.Hstore", "nil"
+	case "uuid":
+		goType, zero, nullType = "uuid.UUID", "uuid.UUID{}", "pgtype.UUID"
+	default:
+		if d.IsArray {
+			// custom (ie, enum) array elements are scanned as text
+			return "[]string", "nil", nil
+		}
+		goType, zero = schemaType(d.Type, d.Nullable, schema)
+	}
+	switch {
+	case d.IsArray:
+		return "[]" + goType, "nil", nil
+	case d.Nullable && nullType != "":
+		return nullType, nullType + "{}", nil
+	}
+	return goType, zero, nil
+}
+
 // PostgresGoType parse a type into a Go type based on the database type
 // definition.
 func PostgresGoType(d xo.Type, schema, itype string) (string, string, error) {
@@ -159,7 +248,7 @@ func PostgresGoType(d xo.Type, schema, itype string) (string, string, error) {
 		}
 	case "any", "bit varying", "bytea", "interval", "json", "jsonb", "xml":
 		// TODO: write custom type for interval marshaling
-		// TODO: marshalling for json types
+		// json types are marshaled by the go template when --go-json is used
 		goType, zero = "[]byte", "nil"
 	case "hstore":
 		goType, zero = "hstore.Hstore", "nil"
@@ -174,53 +263,16 @@ func PostgresGoType(d xo.Type, schema, itype string) (string, string, error) {
 	return goType, zero, nil
 }
 
-// PostgresTableColumns returns the columns for a table.
-func PostgresTableColumns(ctx context.Context, db models.DB, schema string, table string) ([]*models.Column, error) {
-	return models.PostgresTableColumns(ctx, db, schema, table, enableOids(ctx))
+// PostgresFingerprint returns the fingerprint of the database catalog, as the
+// catalog is shared by all schemas.
+func PostgresFingerprint(ctx context.Context, db models.DB, schema string) (string, error) {
+	return models.PostgresFingerprint(ctx, db)
 }
 
-// PostgresIndexColumns returns the column list for an index.
-//
-// FIXME: rewrite this using SQL exclusively using OVER
-func PostgresIndexColumns(ctx context.Context, db models.DB, schema string, table string, index string) ([]*models.IndexColumn, error) {
-	// load columns
-	cols, err := models.PostgresIndexColumns(ctx, db, schema, index)
-	if err != nil {
-		return nil, err
-	}
-	// load col order
-	colOrd, err := models.PostgresGetColOrder(ctx, db, schema, index)
-	if err != nil {
-		return nil, err
-	}
-	// build schema name used in errors
-	s := schema
-	if s != "" {
-		s += "."
-	}
-	// put cols in order using colOrder
-	var ret []*models.IndexColumn
-	for _, v := range strings.Split(colOrd.Ord, " ") {
-		cid, err := strconv.Atoi(v)
-		if err != nil {
-			return nil, fmt.Errorf("could not convert %s%s index %s column %s to int", s, table, index, v)
-		}
-		// find column
-		found := false
-		var c *models.IndexColumn
-		for _, ic := range cols {
-			if cid == ic.Cid {
-				found, c = true, ic
-				break
-			}
-		}
-		// sanity check
-		if !found {
-			return nil, fmt.Errorf("could not find %s%s index %s column id %d", s, table, index, cid)
-		}
-		ret = append(ret, c)
-	}
-	return ret, nil
+// PostgresTableColumns returns the columns for a table, or the attributes of
+// a composite type.
+func PostgresTableColumns(ctx context.Context, db models.DB, schema string, table string) ([]*models.Column, error) {
+	return models.PostgresTableColumns(ctx, db, schema, table, enableOids(ctx))
 }
 
 // PostgresViewStrip strips '::type AS name' in queries.
@@ -269,3 +321,77 @@ func enableOids(ctx context.Context) bool {
 	b, _ := ctx.Value(oidsKey).(bool)
 	return b
 }
+
+// PostgresQueryColumns returns the result columns of a query from the row
+// description of the prepared statement, without running the query. As such,
+// DML queries with a RETURNING clause can be introspected with a read-only
+// connection.
+func PostgresQueryColumns(ctx context.Context, db *sql.DB, query string) ([]*models.Column, error) {
+	sd, err := pgxPrepare(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+	var cols []*models.Column
+	for _, f := range sd.Fields {
+		typ, err := models.PostgresFormatType(ctx, db, int64(f.DataTypeOID), int64(f.TypeModifier))
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, &models.Column{
+			ColumnName: f.Name,
+			DataType:   typ,
+		})
+	}
+	return cols, nil
+}
+
+// PostgresQueryParamTypes returns the types of a query's parameters, as
+// inferred by the database when preparing the query.
+func PostgresQueryParamTypes(ctx context.Context, db *sql.DB, query string) ([]string, error) {
+	sd, err := pgxPrepare(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+	var typs []string
+	for _, oid := range sd.ParamOIDs {
+		typ, err := models.PostgresFormatType(ctx, db, int64(oid), -1)
+		if err != nil {
+			return nil, err
+		}
+		typs = append(typs, typ)
+	}
+	return typs, nil
+}
+
+// pgxConns are the github.com/jackc/pgx/v5 connections used to prepare
+// queries, by the database they were opened for.
+var pgxConns = struct {
+	sync.Mutex
+	m map[*sql.DB]*pgconn.PgConn
+}{m: make(map[*sql.DB]*pgconn.PgConn)}
+
+// pgxPrepare prepares query on a github.com/jackc/pgx/v5 connection to the
+// context's database, returning the description of the prepared statement.
+// The statement is not run.
+//
+// A single connection is opened per database with the dsn the database was
+// opened with (including any credentials read from the passfile), and is
+// reused for the remaining queries.
+func pgxPrepare(ctx context.Context, db *sql.DB, query string) (*pgconn.StatementDescription, error) {
+	pgxConns.Lock()
+	defer pgxConns.Unlock()
+	conn := pgxConns.m[db]
+	if conn == nil || conn.IsClosed() {
+		dsn := DSN(ctx)
+		if dsn == "" {
+			return nil, errors.New("preparing a query requires a database connection")
+		}
+		var err error
+		if conn, err = pgconn.Connect(ctx, dsn); err != nil {
+			return nil, err
+		}
+		pgxConns.m[db] = conn
+	}
+	models.Logf(query)
+	return conn.Prepare(ctx, "", query, nil)
+}
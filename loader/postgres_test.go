@@ -0,0 +1,93 @@
+package loader
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+	xo "github.com/xo/xo/types"
+)
+
+func TestPostgresQueryColumns(t *testing.T) {
+	dsn := os.Getenv("XO_TEST_POSTGRES")
+	if dsn == "" {
+		t.Skip("XO_TEST_POSTGRES not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	ctx := WithDSN(context.Background(), dsn)
+	// queries are prepared on a separate connection, so the table can not be
+	// temporary
+	if _, err := db.ExecContext(ctx, `CREATE TABLE xo_test_authors (
+  author_id SERIAL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL
+)`); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Exec(`DROP TABLE xo_test_authors`)
+	cols, err := PostgresQueryColumns(ctx, db, `SELECT author_id, name, 1.5::numeric(10, 2) AS price FROM xo_test_authors WHERE name = $1`)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var typs []string
+	for _, c := range cols {
+		typs = append(typs, c.ColumnName+" "+c.DataType)
+	}
+	if s, exp := strings.Join(typs, ", "), "author_id integer, name character varying(255), price numeric(10,2)"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	params, err := PostgresQueryParamTypes(ctx, db, `SELECT author_id FROM xo_test_authors WHERE name = $1 AND author_id > $2`)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if s, exp := strings.Join(params, ", "), "character varying, integer"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}
+
+func TestPostgresGoType(t *testing.T) {
+	tests := []struct {
+		typ      string
+		nullable bool
+		pq       string
+		pgx      string
+	}{
+		{"numeric(10,2)", false, "float64", "pgtype.Numeric"},
+		{"numeric", true, "sql.NullFloat64", "pgtype.Numeric"},
+		{"numeric[]", false, "float64", "[]pgtype.Numeric"},
+		{"double precision", true, "sql.NullFloat64", "pgtype.Float8"},
+		{"uuid", false, "uuid.UUID", "uuid.UUID"},
+		{"uuid", true, "uuid.NullUUID", "pgtype.UUID"},
+		{"timestamp(3) with time zone", false, "time.Time", "time.Time"},
+		{"timestamp(3) without time zone", true, "sql.NullTime", "pgtype.Timestamp"},
+		{"time(6) with time zone", false, "time.Time", "string"},
+		{"timestamp with time zone[]", false, "time.Time", "[]time.Time"},
+	}
+	for i, test := range tests {
+		d, err := xo.ParseType(test.typ, "postgres")
+		if err != nil {
+			t.Fatalf("test %d %q expected no error, got: %v", i, test.typ, err)
+		}
+		d.Nullable = test.nullable
+		goType, _, err := PostgresGoType(d, "public", "int")
+		if err != nil {
+			t.Fatalf("test %d %q expected no error, got: %v", i, test.typ, err)
+		}
+		if goType != test.pq {
+			t.Errorf("test %d %q (nullable: %t) expected pq goType = %q, got: %q", i, test.typ, test.nullable, test.pq, goType)
+		}
+		goType, _, err = PgxPostgresGoType(d, "public", "int", "uint")
+		if err != nil {
+			t.Fatalf("test %d %q expected no error, got: %v", i, test.typ, err)
+		}
+		if goType != test.pgx {
+			t.Errorf("test %d %q (nullable: %t) expected pgx goType = %q, got: %q", i, test.typ, test.nullable, test.pgx, goType)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package loader
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
 	"github.com/xo/xo/models"
 	xo "github.com/xo/xo/types"
 )
@@ -8,18 +14,182 @@ import (
 func init() {
 	Register("sqlite3", Loader{
 		Mask:             "$%d",
+		MaxParams:        32766,
 		Schema:           models.Sqlite3Schema,
+		Fingerprint:      models.Sqlite3Fingerprint,
 		Tables:           models.Sqlite3Tables,
-		TableColumns:     models.Sqlite3TableColumns,
+		TableColumns:     Sqlite3TableColumns,
 		TableSequences:   models.Sqlite3TableSequences,
 		TableForeignKeys: models.Sqlite3TableForeignKeys,
-		TableIndexes:     models.Sqlite3TableIndexes,
-		IndexColumns:     models.Sqlite3IndexColumns,
+		TableIndexes:     Sqlite3TableIndexes,
+		TableChecks:      Sqlite3TableChecks,
+		IndexColumns:     Sqlite3IndexColumns,
 		ViewCreate:       models.Sqlite3ViewCreate,
 		ViewDrop:         models.Sqlite3ViewDrop,
+		QueryColumns:     Sqlite3QueryColumns,
 	})
 }
 
+// Sqlite3QueryColumns returns the result columns of a query, using the
+// declared types of the prepared statement's columns. The statement is never
+// stepped, as github.com/mattn/go-sqlite3 does not step a statement until its
+// first row is read, and the query is not run.
+func Sqlite3QueryColumns(ctx context.Context, db *sql.DB, query string) ([]*models.Column, error) {
+	var cols []*models.Column
+	if err := prepare(ctx, db, query, func(stmt driver.Stmt) error {
+		q, ok := stmt.(driver.StmtQueryContext)
+		if !ok {
+			return fmt.Errorf("unsupported driver statement %T", stmt)
+		}
+		rows, err := q.QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		typs, ok := rows.(driver.RowsColumnTypeDatabaseTypeName)
+		if !ok {
+			return fmt.Errorf("unsupported driver rows %T", rows)
+		}
+		for i, name := range rows.Columns() {
+			cols = append(cols, &models.Column{
+				ColumnName: name,
+				DataType:   strings.ToLower(typs.ColumnTypeDatabaseTypeName(i)),
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// Sqlite3TableColumns returns the columns for the table, using the expression
+// of generated columns parsed from the table's definition as their default
+// value.
+func Sqlite3TableColumns(ctx context.Context, db models.DB, schema, table string) ([]*models.Column, error) {
+	cols, err := models.Sqlite3TableColumns(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	var generated bool
+	for _, c := range cols {
+		generated = generated || c.IsGenerated
+	}
+	if !generated {
+		return cols, nil
+	}
+	cat, err := sqlite3Catalog(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	t, err := cat.lookup("", table)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cols {
+		for _, z := range t.columns {
+			if c.IsGenerated && strings.EqualFold(c.ColumnName, z.name) {
+				c.DefaultValue = z.def
+			}
+		}
+	}
+	return cols, nil
+}
+
+// Sqlite3TableChecks returns the check constraints for the table, parsed from
+// the table's definition, as sqlite3 does not otherwise expose them.
+func Sqlite3TableChecks(ctx context.Context, db models.DB, schema, table string) ([]*models.Check, error) {
+	cat, err := sqlite3Catalog(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return cat.TableChecks(ctx, db, "", table)
+}
+
+// Sqlite3TableIndexes returns the indexes for the table, using the predicate of
+// partial indexes parsed from the index's definition.
+func Sqlite3TableIndexes(ctx context.Context, db models.DB, schema, table string) ([]*models.Index, error) {
+	indexes, err := models.Sqlite3TableIndexes(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		ix, err := sqlite3Index(ctx, db, schema, table, index.IndexName)
+		switch {
+		case err != nil:
+			return nil, err
+		case ix != nil:
+			index.Predicate = ix.where
+		}
+	}
+	return indexes, nil
+}
+
+// Sqlite3IndexColumns returns the columns for the index, using the expressions
+// parsed from the index's definition for expression columns.
+func Sqlite3IndexColumns(ctx context.Context, db models.DB, schema, table, index string) ([]*models.IndexColumn, error) {
+	cols, err := models.Sqlite3IndexColumns(ctx, db, schema, table, index)
+	if err != nil {
+		return nil, err
+	}
+	var exprs bool
+	for _, c := range cols {
+		exprs = exprs || c.ColumnName == ""
+	}
+	if !exprs {
+		return cols, nil
+	}
+	ix, err := sqlite3Index(ctx, db, schema, table, index)
+	if err != nil || ix == nil || ix.exprs == nil {
+		return cols, err
+	}
+	for _, c := range cols {
+		if 0 <= c.SeqNo && c.SeqNo < len(ix.exprs) {
+			c.Expr = ix.exprs[c.SeqNo]
+		}
+	}
+	return cols, nil
+}
+
+// sqlite3Index returns the index parsed from the table's and the index's
+// definitions, or nil when the index was created implicitly.
+func sqlite3Index(ctx context.Context, db models.DB, schema, table, index string) (*ddlIndex, error) {
+	def, err := models.Sqlite3IndexSQL(ctx, db, schema, index)
+	if err != nil || def == "" {
+		return nil, err
+	}
+	cat, err := sqlite3Catalog(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if err := cat.parse(def); err != nil {
+		return nil, fmt.Errorf("index %q: %w", index, err)
+	}
+	t, err := cat.lookup("", table)
+	if err != nil {
+		return nil, err
+	}
+	if ix := t.index(index); ix != nil {
+		return ix, nil
+	}
+	return nil, fmt.Errorf("index %q not defined", index)
+}
+
+// sqlite3Catalog returns a catalog parsed from the table's definition.
+func sqlite3Catalog(ctx context.Context, db models.DB, schema, table string) (*ddlCatalog, error) {
+	def, err := models.Sqlite3TableSQL(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	cat := &ddlCatalog{
+		driver: "sqlite3",
+	}
+	if err := cat.parse(def); err != nil {
+		return nil, fmt.Errorf("table %q: %w", table, err)
+	}
+	return cat, nil
+}
+
 // Sqlite3GoType parse a sqlite3 type into a Go type based on the column
 // definition.
 func Sqlite3GoType(d xo.Type, schema, itype, utype string) (string, string, error) {
@@ -0,0 +1,61 @@
+package loader
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSqlite3QueryColumns(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer db.Close()
+	// in-memory databases are per connection
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE authors (
+  author_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  born DATE
+)`); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		query string
+		exp   string
+	}{
+		{`SELECT author_id, name, born FROM authors`, "author_id integer, name text, born date"},
+		{`SELECT a.name AS n, b.born FROM authors a JOIN authors b ON a.author_id = b.author_id`, "n text, born date"},
+		{`INSERT INTO authors (name) VALUES ('x') RETURNING author_id, name`, "author_id integer, name text"},
+		{`DELETE FROM authors RETURNING born`, "born date"},
+	}
+	ctx := context.Background()
+	for i, test := range tests {
+		cols, err := Sqlite3QueryColumns(ctx, db, test.query)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		var typs []string
+		for _, c := range cols {
+			typs = append(typs, c.ColumnName+" "+c.DataType)
+		}
+		if s := strings.Join(typs, ", "); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	// introspection must not run the query
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM authors`).Scan(&n); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected no rows, got: %d", n)
+	}
+	if _, err := Sqlite3QueryColumns(ctx, db, `SELECT nope FROM authors`); err == nil {
+		t.Errorf("expected error")
+	}
+}
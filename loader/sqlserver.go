@@ -10,7 +10,9 @@ import (
 func init() {
 	Register("sqlserver", Loader{
 		Mask:             "@p%d",
+		MaxParams:        2100,
 		Schema:           models.SqlserverSchema,
+		Schemas:          models.SqlserverSchemas,
 		Procs:            models.SqlserverProcs,
 		ProcParams:       models.SqlserverProcParams,
 		Tables:           models.SqlserverTables,
@@ -18,6 +20,7 @@ func init() {
 		TableSequences:   models.SqlserverTableSequences,
 		TableForeignKeys: models.SqlserverTableForeignKeys,
 		TableIndexes:     models.SqlserverTableIndexes,
+		TableChecks:      models.SqlserverTableChecks,
 		IndexColumns:     models.SqlserverIndexColumns,
 		ViewCreate:       models.SqlserverViewCreate,
 		ViewDrop:         models.SqlserverViewDrop,
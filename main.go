@@ -1,5 +1,6 @@
 // Command xo generates code from database schemas and custom queries. Works
-// with PostgreSQL, MySQL, Microsoft SQL Server, Oracle Database, and SQLite3.
+// with PostgreSQL, MySQL, Microsoft SQL Server, Oracle Database, SQLite3, and
+// DuckDB.
 package main
 
 //go:generate ./gen.sh models
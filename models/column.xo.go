@@ -15,6 +15,7 @@ type Column struct {
 	NotNull      bool           `json:"not_null"`       // not_null
 	DefaultValue sql.NullString `json:"default_value"`  // default_value
 	IsPrimaryKey bool           `json:"is_primary_key"` // is_primary_key
+	IsGenerated  bool           `json:"is_generated"`   // is_generated
 	Comment      sql.NullString `json:"comment"`        // comment
 }
 
@@ -28,6 +29,7 @@ func PostgresTableColumns(ctx context.Context, db DB, schema, table string, sys
 		`a.attnotnull, ` + // ::boolean AS not_null
 		`COALESCE(pg_get_expr(ad.adbin, ad.adrelid), ''), ` + // ::varchar AS default_value
 		`COALESCE(ct.contype = 'p', false), ` + // ::boolean AS is_primary_key
+		`(a.attgenerated <> ''), ` + // ::boolean AS is_generated
 		`d.description ` + // ::varchar as comment
 		`FROM pg_attribute a ` +
 		`JOIN ONLY pg_class c ON c.oid = a.attrelid ` +
@@ -56,7 +58,7 @@ func PostgresTableColumns(ctx context.Context, db DB, schema, table string, sys
 	for rows.Next() {
 		var c Column
 		// scan
-		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.Comment); err != nil {
+		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.IsGenerated, &c.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &c)
@@ -75,8 +77,9 @@ func MysqlTableColumns(ctx context.Context, db DB, schema, table string) ([]*Col
 		`column_name, ` +
 		`IF(data_type = 'enum', column_name, column_type) AS data_type, ` +
 		`IF(is_nullable = 'YES', false, true) AS not_null, ` +
-		`column_default AS default_value, ` +
+		`IF(extra IN ('STORED GENERATED', 'VIRTUAL GENERATED'), generation_expression, column_default) AS default_value, ` +
 		`IF(column_key = 'PRI', true, false) AS is_primary_key, ` +
+		`IF(extra IN ('STORED GENERATED', 'VIRTUAL GENERATED'), true, false) AS is_generated, ` +
 		`column_comment AS comment ` +
 		`FROM information_schema.columns ` +
 		`WHERE table_schema = ? ` +
@@ -94,7 +97,7 @@ func MysqlTableColumns(ctx context.Context, db DB, schema, table string) ([]*Col
 	for rows.Next() {
 		var c Column
 		// scan
-		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.Comment); err != nil {
+		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.IsGenerated, &c.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &c)
@@ -115,8 +118,10 @@ func Sqlite3TableColumns(ctx context.Context, db DB, schema, table string) ([]*C
 		`type AS data_type, ` +
 		`"notnull" AS not_null, ` +
 		`dflt_value AS default_value, ` +
-		`CAST(pk <> 0 AS boolean) AS is_primary_key ` +
-		`FROM pragma_table_info($1)`
+		`CAST(pk <> 0 AS boolean) AS is_primary_key, ` +
+		`CAST(hidden IN (2, 3) AS boolean) AS is_generated ` +
+		`FROM pragma_table_xinfo($1) ` +
+		`WHERE hidden <> 1`
 	// run
 	logf(sqlstr, table)
 	rows, err := db.QueryContext(ctx, sqlstr, table)
@@ -129,7 +134,51 @@ func Sqlite3TableColumns(ctx context.Context, db DB, schema, table string) ([]*C
 	for rows.Next() {
 		var c Column
 		// scan
-		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey); err != nil {
+		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.IsGenerated); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
+
+// DuckdbTableColumns runs a custom query, returning results as [Column].
+func DuckdbTableColumns(ctx context.Context, db DB, schema, table string) ([]*Column, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`c.column_index AS field_ordinal, ` +
+		`c.column_name, ` +
+		`c.data_type, ` +
+		`NOT c.is_nullable AS not_null, ` +
+		`c.column_default AS default_value, ` +
+		`COALESCE(LIST_CONTAINS(p.constraint_column_names, c.column_name), false) AS is_primary_key, ` +
+		`false AS is_generated, ` +
+		`c.comment ` +
+		`FROM duckdb_columns() c ` +
+		`LEFT JOIN duckdb_constraints() p ON p.database_name = c.database_name ` +
+		`AND p.schema_name = c.schema_name ` +
+		`AND p.table_name = c.table_name ` +
+		`AND p.constraint_type = 'PRIMARY KEY' ` +
+		`WHERE c.database_name IN (CURRENT_DATABASE(), 'temp') ` +
+		`AND c.schema_name = $1 ` +
+		`AND c.table_name = $2 ` +
+		`ORDER BY c.column_index`
+	// run
+	logf(sqlstr, schema, table)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, table)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// load results
+	var res []*Column
+	for rows.Next() {
+		var c Column
+		// scan
+		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.IsGenerated, &c.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &c)
@@ -148,7 +197,7 @@ func SqlserverTableColumns(ctx context.Context, db DB, schema, table string) ([]
 		`c.name AS column_name, ` +
 		`TYPE_NAME(c.xtype)+IIF(c.prec > 0, '('+CAST(c.prec AS varchar)+IIF(c.scale > 0,','+CAST(c.scale AS varchar),'')+')', '') AS data_type, ` +
 		`IIF(c.isnullable=1, 0, 1) AS not_null, ` +
-		`x.text AS default_value, ` +
+		`COALESCE(cc.definition, x.text) AS default_value, ` +
 		`IIF(COALESCE(( ` +
 		`SELECT COUNT(z.colid) ` +
 		`FROM sysindexes i ` +
@@ -157,12 +206,15 @@ func SqlserverTableColumns(ctx context.Context, db DB, schema, table string) ([]
 		`AND z.colid = c.colid ` +
 		`WHERE i.id = o.id ` +
 		`AND i.name = k.name ` +
-		`), 0) > 0, 1, 0) AS is_primary_key ` +
+		`), 0) > 0, 1, 0) AS is_primary_key, ` +
+		`IIF(cc.column_id IS NULL, 0, 1) AS is_generated ` +
 		`FROM syscolumns c ` +
 		`JOIN sysobjects o ON o.id = c.id ` +
 		`LEFT JOIN sysobjects k ON k.xtype = 'PK' ` +
 		`AND k.parent_obj = o.id ` +
 		`LEFT JOIN syscomments x ON x.id = c.cdefault ` +
+		`LEFT JOIN sys.computed_columns cc ON cc.object_id = c.id ` +
+		`AND cc.column_id = c.colid ` +
 		`WHERE o.type IN('U', 'V') ` +
 		`AND SCHEMA_NAME(o.uid) = @p1 ` +
 		`AND o.name = @p2 ` +
@@ -179,7 +231,7 @@ func SqlserverTableColumns(ctx context.Context, db DB, schema, table string) ([]
 	for rows.Next() {
 		var c Column
 		// scan
-		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey); err != nil {
+		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.DefaultValue, &c.IsPrimaryKey, &c.IsGenerated); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &c)
@@ -205,8 +257,9 @@ func OracleTableColumns(ctx context.Context, db DB, schema, table string) ([]*Co
 		`WHEN 'RAW' THEN 'RAW(' || c.data_length || ')' ` +
 		`ELSE c.data_type END) AS data_type, ` +
 		`CASE WHEN c.nullable = 'N' THEN '1' ELSE '0' END AS not_null, ` +
-		`CASE WHEN p.column_id IS NOT NULL THEN '1' ELSE '0' END as is_primary_key ` +
-		`FROM all_tab_columns c ` +
+		`CASE WHEN p.column_id IS NOT NULL THEN '1' ELSE '0' END as is_primary_key, ` +
+		`CASE WHEN c.virtual_column = 'YES' THEN '1' ELSE '0' END AS is_generated ` +
+		`FROM all_tab_cols c ` +
 		`LEFT JOIN ( ` +
 		`SELECT distinct c.column_id FROM all_tab_columns c ` +
 		`JOIN all_cons_columns l ON l.owner = c.owner ` +
@@ -220,6 +273,7 @@ func OracleTableColumns(ctx context.Context, db DB, schema, table string) ([]*Co
 		`) p on p.column_id = c.column_id ` +
 		`WHERE c.owner = UPPER(:1) ` +
 		`AND c.table_name = UPPER(:2) ` +
+		`AND c.hidden_column = 'NO' ` +
 		`ORDER BY c.column_id`
 	// run
 	logf(sqlstr, schema, table)
@@ -233,7 +287,7 @@ func OracleTableColumns(ctx context.Context, db DB, schema, table string) ([]*Co
 	for rows.Next() {
 		var c Column
 		// scan
-		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.IsPrimaryKey); err != nil {
+		if err := rows.Scan(&c.FieldOrdinal, &c.ColumnName, &c.DataType, &c.NotNull, &c.IsPrimaryKey, &c.IsGenerated); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &c)
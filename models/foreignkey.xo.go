@@ -10,6 +10,7 @@ import (
 type ForeignKey struct {
 	ForeignKeyName string `json:"foreign_key_name"` // foreign_key_name
 	ColumnName     string `json:"column_name"`      // column_name
+	RefSchemaName  string `json:"ref_schema_name"`  // ref_schema_name
 	RefTableName   string `json:"ref_table_name"`   // ref_table_name
 	RefColumnName  string `json:"ref_column_name"`  // ref_column_name
 	KeyID          int    `json:"key_id"`           // key_id
@@ -21,6 +22,7 @@ func PostgresTableForeignKeys(ctx context.Context, db DB, schema, table string)
 	const sqlstr = `SELECT ` +
 		`tc.constraint_name, ` + // ::varchar AS foreign_key_name
 		`kcu.column_name, ` + // ::varchar AS column_name
+		`ccu.table_schema, ` + // ::varchar AS ref_schema_name
 		`ccu.table_name, ` + // ::varchar AS ref_table_name
 		`ccu.column_name, ` + // ::varchar AS ref_column_name
 		`0 ` + // ::integer AS key_id
@@ -40,6 +42,7 @@ func PostgresTableForeignKeys(ctx context.Context, db DB, schema, table string)
 		`table_schema, ` +
 		`table_name, ` +
 		`column_name, ` +
+		`constraint_schema, ` +
 		`constraint_name ` +
 		`FROM ( ` +
 		`SELECT ` +
@@ -47,11 +50,12 @@ func PostgresTableForeignKeys(ctx context.Context, db DB, schema, table string)
 		`table_schema, ` +
 		`table_name, ` +
 		`column_name, ` +
+		`constraint_schema, ` +
 		`constraint_name ` +
 		`FROM information_schema.constraint_column_usage ` +
 		`) t ` +
 		`) AS ccu ON ccu.constraint_name = tc.constraint_name ` +
-		`AND ccu.table_schema = tc.table_schema ` +
+		`AND ccu.constraint_schema = tc.constraint_schema ` +
 		`AND ccu.ordinal_position = kcu.ordinal_position ` +
 		`WHERE tc.constraint_type = 'FOREIGN KEY' ` +
 		`AND tc.table_schema = $1 ` +
@@ -68,7 +72,7 @@ func PostgresTableForeignKeys(ctx context.Context, db DB, schema, table string)
 	for rows.Next() {
 		var fk ForeignKey
 		// scan
-		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefTableName, &fk.RefColumnName, &fk.KeyID); err != nil {
+		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefSchemaName, &fk.RefTableName, &fk.RefColumnName, &fk.KeyID); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &fk)
@@ -85,6 +89,7 @@ func MysqlTableForeignKeys(ctx context.Context, db DB, schema, table string) ([]
 	const sqlstr = `SELECT ` +
 		`constraint_name AS foreign_key_name, ` +
 		`column_name AS column_name, ` +
+		`referenced_table_schema AS ref_schema_name, ` +
 		`referenced_table_name AS ref_table_name, ` +
 		`referenced_column_name AS ref_column_name ` +
 		`FROM information_schema.key_column_usage ` +
@@ -103,7 +108,7 @@ func MysqlTableForeignKeys(ctx context.Context, db DB, schema, table string) ([]
 	for rows.Next() {
 		var fk ForeignKey
 		// scan
-		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefTableName, &fk.RefColumnName); err != nil {
+		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefSchemaName, &fk.RefTableName, &fk.RefColumnName); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &fk)
@@ -147,12 +152,61 @@ func Sqlite3TableForeignKeys(ctx context.Context, db DB, schema, table string) (
 	return res, nil
 }
 
+// DuckdbTableForeignKeys runs a custom query, returning results as [ForeignKey].
+func DuckdbTableForeignKeys(ctx context.Context, db DB, schema, table string) ([]*ForeignKey, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`table_name || '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_fkey' AS foreign_key_name, ` +
+		`UNNEST(constraint_column_names) AS column_name, ` +
+		`schema_name AS ref_schema_name, ` +
+		`TRIM(CASE ` +
+		`WHEN STARTS_WITH(ref_table, schema_name || '.') THEN SUBSTRING(ref_table, LENGTH(schema_name) + 2) ` +
+		`ELSE ref_table ` +
+		`END, '"') AS ref_table_name, ` +
+		`UNNEST(LIST_TRANSFORM(STRING_SPLIT(ref_cols, ', '), c -> TRIM(c, '"'))) AS ref_column_name, ` +
+		`constraint_index AS key_id ` +
+		`FROM ( ` +
+		`SELECT ` +
+		`*, ` +
+		`REGEXP_EXTRACT(constraint_text, 'REFERENCES (.+)\((.*)\)$', 1) AS ref_table, ` +
+		`REGEXP_EXTRACT(constraint_text, 'REFERENCES (.+)\((.*)\)$', 2) AS ref_cols ` +
+		`FROM duckdb_constraints() ` +
+		`WHERE constraint_type = 'FOREIGN KEY' ` +
+		`) k ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND schema_name = $1 ` +
+		`AND table_name = $2 ` +
+		`ORDER BY constraint_index`
+	// run
+	logf(sqlstr, schema, table)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, table)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// load results
+	var res []*ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		// scan
+		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefSchemaName, &fk.RefTableName, &fk.RefColumnName, &fk.KeyID); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &fk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
+
 // SqlserverTableForeignKeys runs a custom query, returning results as [ForeignKey].
 func SqlserverTableForeignKeys(ctx context.Context, db DB, schema, table string) ([]*ForeignKey, error) {
 	// query
 	const sqlstr = `SELECT ` +
 		`fk.name AS foreign_key_name, ` +
 		`col.name AS column_name, ` +
+		`schema_name(pk_tab.schema_id) AS ref_schema_name, ` +
 		`pk_tab.name AS ref_table_name, ` +
 		`pk_col.name AS ref_column_name ` +
 		`FROM sys.tables tab ` +
@@ -178,7 +232,7 @@ func SqlserverTableForeignKeys(ctx context.Context, db DB, schema, table string)
 	for rows.Next() {
 		var fk ForeignKey
 		// scan
-		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefTableName, &fk.RefColumnName); err != nil {
+		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefSchemaName, &fk.RefTableName, &fk.RefColumnName); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &fk)
@@ -195,6 +249,7 @@ func OracleTableForeignKeys(ctx context.Context, db DB, schema, table string) ([
 	const sqlstr = `SELECT ` +
 		`LOWER(a.constraint_name) AS foreign_key_name, ` +
 		`LOWER(a.column_name) AS column_name, ` +
+		`LOWER(c_pk.owner) AS ref_schema_name, ` +
 		`LOWER(c_pk.table_name) AS ref_table_name, ` +
 		`LOWER(b.column_name) AS ref_column_name ` +
 		`FROM user_cons_columns a ` +
@@ -219,7 +274,7 @@ func OracleTableForeignKeys(ctx context.Context, db DB, schema, table string) ([
 	for rows.Next() {
 		var fk ForeignKey
 		// scan
-		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefTableName, &fk.RefColumnName); err != nil {
+		if err := rows.Scan(&fk.ForeignKeyName, &fk.ColumnName, &fk.RefSchemaName, &fk.RefTableName, &fk.RefColumnName); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &fk)
@@ -11,6 +11,7 @@ type Index struct {
 	IndexName string `json:"index_name"` // index_name
 	IsUnique  bool   `json:"is_unique"`  // is_unique
 	IsPrimary bool   `json:"is_primary"` // is_primary
+	Predicate string `json:"predicate"`  // predicate
 }
 
 // PostgresTableIndexes runs a custom query, returning results as [Index].
@@ -19,13 +20,13 @@ func PostgresTableIndexes(ctx context.Context, db DB, schema, table string) ([]*
 	const sqlstr = `SELECT ` +
 		`DISTINCT ic.relname, ` + // ::varchar AS index_name
 		`i.indisunique, ` + // ::boolean AS is_unique
-		`i.indisprimary ` + // ::boolean AS is_primary
+		`i.indisprimary, ` + // ::boolean AS is_primary
+		`COALESCE(pg_get_expr(i.indpred, i.indrelid, true), '') ` + // ::varchar AS predicate
 		`FROM pg_index i ` +
 		`JOIN ONLY pg_class c ON c.oid = i.indrelid ` +
 		`JOIN ONLY pg_namespace n ON n.oid = c.relnamespace ` +
 		`JOIN ONLY pg_class ic ON ic.oid = i.indexrelid ` +
-		`WHERE i.indkey <> '0' ` +
-		`AND n.nspname = $1 ` +
+		`WHERE n.nspname = $1 ` +
 		`AND c.relname = $2`
 	// run
 	logf(sqlstr, schema, table)
@@ -39,7 +40,7 @@ func PostgresTableIndexes(ctx context.Context, db DB, schema, table string) ([]*
 	for rows.Next() {
 		var i Index
 		// scan
-		if err := rows.Scan(&i.IndexName, &i.IsUnique, &i.IsPrimary); err != nil {
+		if err := rows.Scan(&i.IndexName, &i.IsUnique, &i.IsPrimary, &i.Predicate); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &i)
@@ -115,13 +116,70 @@ func Sqlite3TableIndexes(ctx context.Context, db DB, schema, table string) ([]*I
 	return res, nil
 }
 
+// DuckdbTableIndexes runs a custom query, returning results as [Index].
+func DuckdbTableIndexes(ctx context.Context, db DB, schema, table string) ([]*Index, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`index_name, ` +
+		`is_unique, ` +
+		`is_primary ` +
+		`FROM ( ` +
+		`SELECT ` +
+		`database_name, ` +
+		`schema_name, ` +
+		`table_name, ` +
+		`table_name || CASE constraint_type ` +
+		`WHEN 'PRIMARY KEY' THEN '_pkey' ` +
+		`ELSE '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_key' ` +
+		`END AS index_name, ` +
+		`true AS is_unique, ` +
+		`constraint_type = 'PRIMARY KEY' AS is_primary ` +
+		`FROM duckdb_constraints() ` +
+		`WHERE constraint_type IN ('PRIMARY KEY', 'UNIQUE') ` +
+		`UNION ALL ` +
+		`SELECT ` +
+		`database_name, ` +
+		`schema_name, ` +
+		`table_name, ` +
+		`index_name, ` +
+		`is_unique, ` +
+		`is_primary ` +
+		`FROM duckdb_indexes() ` +
+		`) i ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND schema_name = $1 ` +
+		`AND table_name = $2`
+	// run
+	logf(sqlstr, schema, table)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, table)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// load results
+	var res []*Index
+	for rows.Next() {
+		var i Index
+		// scan
+		if err := rows.Scan(&i.IndexName, &i.IsUnique, &i.IsPrimary); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
+
 // SqlserverTableIndexes runs a custom query, returning results as [Index].
 func SqlserverTableIndexes(ctx context.Context, db DB, schema, table string) ([]*Index, error) {
 	// query
 	const sqlstr = `SELECT ` +
 		`i.name AS index_name, ` +
 		`i.is_primary_key AS is_primary, ` +
-		`i.is_unique ` +
+		`i.is_unique, ` +
+		`COALESCE(i.filter_definition, '') AS predicate ` +
 		`FROM sys.indexes i ` +
 		`INNER JOIN sysobjects o ON i.object_id = o.id ` +
 		`WHERE i.name IS NOT NULL ` +
@@ -140,7 +198,7 @@ func SqlserverTableIndexes(ctx context.Context, db DB, schema, table string) ([]
 	for rows.Next() {
 		var i Index
 		// scan
-		if err := rows.Scan(&i.IndexName, &i.IsPrimary, &i.IsUnique); err != nil {
+		if err := rows.Scan(&i.IndexName, &i.IsPrimary, &i.IsUnique, &i.Predicate); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &i)
@@ -11,28 +11,36 @@ type IndexColumn struct {
 	SeqNo      int    `json:"seq_no"`      // seq_no
 	Cid        int    `json:"cid"`         // cid
 	ColumnName string `json:"column_name"` // column_name
+	Expr       string `json:"expr"`        // expr
 }
 
 // PostgresIndexColumns runs a custom query, returning results as [IndexColumn].
-func PostgresIndexColumns(ctx context.Context, db DB, schema, index string) ([]*IndexColumn, error) {
+func PostgresIndexColumns(ctx context.Context, db DB, schema, table, index string) ([]*IndexColumn, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`(row_number() over()), ` + // ::integer AS seq_no
-		`a.attnum, ` + // ::integer AS cid
-		`a.attname ` + // ::varchar AS column_name
+		`k.n, ` + // ::integer AS seq_no
+		`k.attnum, ` + // ::integer AS cid
+		`COALESCE(a.attname, ''), ` + // ::varchar AS column_name
+		`(CASE ` +
+		`WHEN k.attnum = 0 THEN pg_get_indexdef(i.indexrelid, k.n::integer, true) ` +
+		`ELSE '' ` +
+		`END) ` + // ::varchar AS expr
 		`FROM pg_index i ` +
 		`JOIN ONLY pg_class c ON c.oid = i.indrelid ` +
 		`JOIN ONLY pg_namespace n ON n.oid = c.relnamespace ` +
 		`JOIN ONLY pg_class ic ON ic.oid = i.indexrelid ` +
+		`CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, n) ` +
 		`LEFT JOIN pg_attribute a ON i.indrelid = a.attrelid ` +
-		`AND a.attnum = ANY(i.indkey) ` +
+		`AND a.attnum = k.attnum ` +
 		`AND a.attisdropped = false ` +
-		`WHERE i.indkey <> '0' ` +
+		`WHERE k.n <= i.indnkeyatts ` +
 		`AND n.nspname = $1 ` +
-		`AND ic.relname = $2`
+		`AND c.relname = $2 ` +
+		`AND ic.relname = $3 ` +
+		`ORDER BY k.n`
 	// run
-	logf(sqlstr, schema, index)
-	rows, err := db.QueryContext(ctx, sqlstr, schema, index)
+	logf(sqlstr, schema, table, index)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, table, index)
 	if err != nil {
 		return nil, logerror(err)
 	}
@@ -42,7 +50,7 @@ func PostgresIndexColumns(ctx context.Context, db DB, schema, index string) ([]*
 	for rows.Next() {
 		var ic IndexColumn
 		// scan
-		if err := rows.Scan(&ic.SeqNo, &ic.Cid, &ic.ColumnName); err != nil {
+		if err := rows.Scan(&ic.SeqNo, &ic.Cid, &ic.ColumnName, &ic.Expr); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &ic)
@@ -58,7 +66,7 @@ func MysqlIndexColumns(ctx context.Context, db DB, schema, table, index string)
 	// query
 	const sqlstr = `SELECT ` +
 		`seq_in_index AS seq_no, ` +
-		`column_name ` +
+		`COALESCE(column_name, '') AS column_name ` +
 		`FROM information_schema.statistics ` +
 		`WHERE index_schema = ? ` +
 		`AND table_name = ? ` +
@@ -94,7 +102,7 @@ func Sqlite3IndexColumns(ctx context.Context, db DB, schema, table, index string
 		`SELECT ` +
 		`seqno AS seq_no, ` +
 		`cid, ` +
-		`name AS column_name ` +
+		`COALESCE(name, '') AS column_name ` +
 		`FROM pragma_index_info($1)`
 	// run
 	logf(sqlstr, index)
@@ -119,6 +127,54 @@ func Sqlite3IndexColumns(ctx context.Context, db DB, schema, table, index string
 	return res, nil
 }
 
+// DuckdbIndexColumns runs a custom query, returning results as [IndexColumn].
+func DuckdbIndexColumns(ctx context.Context, db DB, schema, table, index string) ([]*IndexColumn, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`seq_no, ` +
+		`column_name ` +
+		`FROM ( ` +
+		`SELECT ` +
+		`database_name, ` +
+		`schema_name, ` +
+		`table_name, ` +
+		`table_name || CASE constraint_type ` +
+		`WHEN 'PRIMARY KEY' THEN '_pkey' ` +
+		`ELSE '_' || ARRAY_TO_STRING(constraint_column_names, '_') || '_key' ` +
+		`END AS index_name, ` +
+		`UNNEST(RANGE(1, LEN(constraint_column_names) + 1)) AS seq_no, ` +
+		`UNNEST(constraint_column_names) AS column_name ` +
+		`FROM duckdb_constraints() ` +
+		`WHERE constraint_type IN ('PRIMARY KEY', 'UNIQUE') ` +
+		`) c ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND schema_name = $1 ` +
+		`AND table_name = $2 ` +
+		`AND index_name = $3 ` +
+		`ORDER BY seq_no`
+	// run
+	logf(sqlstr, schema, table, index)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, table, index)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// load results
+	var res []*IndexColumn
+	for rows.Next() {
+		var ic IndexColumn
+		// scan
+		if err := rows.Scan(&ic.SeqNo, &ic.ColumnName); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &ic)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
+
 // SqlserverIndexColumns runs a custom query, returning results as [IndexColumn].
 func SqlserverIndexColumns(ctx context.Context, db DB, schema, table, index string) ([]*IndexColumn, error) {
 	// query
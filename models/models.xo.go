@@ -180,6 +180,20 @@ func PostgresViewDrop(ctx context.Context, db DB, schema, id string) (sql.Result
 	return db.ExecContext(ctx, sqlstr)
 }
 
+// PostgresFormatType formats a type oid and type modifier as a type name.
+func PostgresFormatType(ctx context.Context, db DB, oid, typmod int64) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`format_type($1, $2)` // ::varchar AS data_type
+	// run
+	logf(sqlstr, oid, typmod)
+	var dataType string
+	if err := db.QueryRowContext(ctx, sqlstr, oid, typmod).Scan(&dataType); err != nil {
+		return "", logerror(err)
+	}
+	return dataType, nil
+}
+
 // PostgresSchema retrieves the schema.
 func PostgresSchema(ctx context.Context, db DB) (string, error) {
 	// query
@@ -194,6 +208,30 @@ func PostgresSchema(ctx context.Context, db DB) (string, error) {
 	return schemaName, nil
 }
 
+// PostgresFingerprint retrieves the schema fingerprint.
+func PostgresFingerprint(ctx context.Context, db DB) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`concat_ws(':', ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_class), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_attribute), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_attrdef), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_constraint), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_index), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_type), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_enum), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_proc), ` +
+		`(SELECT max(xmin::text::bigint) || '/' || count(*) FROM pg_description) ` +
+		`)` // ::varchar AS fingerprint
+	// run
+	logf(sqlstr)
+	var fingerprint string
+	if err := db.QueryRowContext(ctx, sqlstr).Scan(&fingerprint); err != nil {
+		return "", logerror(err)
+	}
+	return fingerprint, nil
+}
+
 // MysqlViewCreate creates a view for introspection.
 func MysqlViewCreate(ctx context.Context, db DB, schema, id string, query []string) (sql.Result, error) {
 	// query
@@ -228,6 +266,27 @@ func MysqlSchema(ctx context.Context, db DB) (string, error) {
 	return schemaName, nil
 }
 
+// MysqlFingerprint retrieves the schema fingerprint.
+func MysqlFingerprint(ctx context.Context, db DB, schema string) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`CONCAT_WS(':', ` +
+		`(SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, table_type, create_time, table_comment))), 0)) FROM information_schema.tables WHERE table_schema = p.schema_name), ` +
+		`(SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, column_name, ordinal_position, column_type, is_nullable, column_default, extra, column_comment))), 0)) FROM information_schema.columns WHERE table_schema = p.schema_name), ` +
+		`(SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, index_name, seq_in_index, column_name, non_unique))), 0)) FROM information_schema.statistics WHERE table_schema = p.schema_name), ` +
+		`(SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', table_name, constraint_name, column_name, referenced_table_name, referenced_column_name))), 0)) FROM information_schema.key_column_usage WHERE table_schema = p.schema_name), ` +
+		`(SELECT CONCAT(COUNT(*), '/', COALESCE(SUM(CRC32(CONCAT_WS(',', routine_name, routine_type, created, last_altered))), 0)) FROM information_schema.routines WHERE routine_schema = p.schema_name) ` +
+		`) AS fingerprint ` +
+		`FROM (SELECT ? AS schema_name) p`
+	// run
+	logf(sqlstr, schema)
+	var fingerprint string
+	if err := db.QueryRowContext(ctx, sqlstr, schema).Scan(&fingerprint); err != nil {
+		return "", logerror(err)
+	}
+	return fingerprint, nil
+}
+
 // Sqlite3ViewCreate creates a view for introspection.
 func Sqlite3ViewCreate(ctx context.Context, db DB, schema, id string, query []string) (sql.Result, error) {
 	// query
@@ -263,6 +322,165 @@ func Sqlite3Schema(ctx context.Context, db DB) (string, error) {
 	return schemaName, nil
 }
 
+// Sqlite3Fingerprint retrieves the schema fingerprint.
+func Sqlite3Fingerprint(ctx context.Context, db DB, schema string) (string, error) {
+	// query
+	sqlstr := `/* ` + schema + ` */ ` +
+		`SELECT ` +
+		`COALESCE(GROUP_CONCAT(type || ' ' || name || ' ' || COALESCE(sql, ''), ';'), '') AS fingerprint ` +
+		`FROM sqlite_master`
+	// run
+	logf(sqlstr)
+	var fingerprint string
+	if err := db.QueryRowContext(ctx, sqlstr).Scan(&fingerprint); err != nil {
+		return "", logerror(err)
+	}
+	return fingerprint, nil
+}
+
+// Sqlite3TableSQL retrieves the definition for a table.
+func Sqlite3TableSQL(ctx context.Context, db DB, schema, table string) (string, error) {
+	// query
+	sqlstr := `/* ` + schema + ` */ ` +
+		`SELECT ` +
+		`sql AS table_sql ` +
+		`FROM sqlite_master ` +
+		`WHERE type = 'table' ` +
+		`AND tbl_name = $1`
+	// run
+	logf(sqlstr, table)
+	var tableSQL string
+	if err := db.QueryRowContext(ctx, sqlstr, table).Scan(&tableSQL); err != nil {
+		return "", logerror(err)
+	}
+	return tableSQL, nil
+}
+
+// Sqlite3IndexSQL retrieves the definition for an index.
+func Sqlite3IndexSQL(ctx context.Context, db DB, schema, index string) (string, error) {
+	// query
+	sqlstr := `/* ` + schema + ` */ ` +
+		`SELECT ` +
+		`COALESCE(sql, '') AS index_sql ` +
+		`FROM sqlite_master ` +
+		`WHERE type = 'index' ` +
+		`AND name = $1`
+	// run
+	logf(sqlstr, index)
+	var indexSQL string
+	if err := db.QueryRowContext(ctx, sqlstr, index).Scan(&indexSQL); err != nil {
+		return "", logerror(err)
+	}
+	return indexSQL, nil
+}
+
+// DuckdbViewCreate creates a view for introspection.
+func DuckdbViewCreate(ctx context.Context, db DB, schema, id string, query []string) (sql.Result, error) {
+	// query
+	sqlstr := `/* ` + schema + ` */ ` +
+		`CREATE TEMPORARY VIEW ` + id + ` AS ` + strings.Join(query, "\n")
+	// run
+	logf(sqlstr)
+	return db.ExecContext(ctx, sqlstr)
+}
+
+// DuckdbViewSchema retrieves the schema for a view created for introspection.
+func DuckdbViewSchema(ctx context.Context, db DB, id string) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`schema_name ` +
+		`FROM duckdb_views() ` +
+		`WHERE temporary ` +
+		`AND view_name = $1`
+	// run
+	logf(sqlstr, id)
+	var schemaName string
+	if err := db.QueryRowContext(ctx, sqlstr, id).Scan(&schemaName); err != nil {
+		return "", logerror(err)
+	}
+	return schemaName, nil
+}
+
+// DuckdbViewDrop drops a view created for introspection.
+func DuckdbViewDrop(ctx context.Context, db DB, schema, id string) (sql.Result, error) {
+	// query
+	sqlstr := `/* ` + schema + ` */ ` +
+		`DROP VIEW ` + id
+	// run
+	logf(sqlstr)
+	return db.ExecContext(ctx, sqlstr)
+}
+
+// DuckdbSchema retrieves the schema.
+func DuckdbSchema(ctx context.Context, db DB) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`CURRENT_SCHEMA() AS schema_name`
+	// run
+	logf(sqlstr)
+	var schemaName string
+	if err := db.QueryRowContext(ctx, sqlstr).Scan(&schemaName); err != nil {
+		return "", logerror(err)
+	}
+	return schemaName, nil
+}
+
+// DuckdbFingerprint retrieves the schema fingerprint.
+func DuckdbFingerprint(ctx context.Context, db DB, schema string) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`MD5(CONCAT_WS(':', ` +
+		`(SELECT STRING_AGG(table_name || ' ' || sql || ' ' || COALESCE(comment, ''), ';' ORDER BY table_name) FROM duckdb_tables() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name), ` +
+		`(SELECT STRING_AGG(table_name || ' ' || column_name || ' ' || comment, ';' ORDER BY table_name, column_index) FROM duckdb_columns() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name AND comment IS NOT NULL), ` +
+		`(SELECT STRING_AGG(view_name || ' ' || sql || ' ' || COALESCE(comment, ''), ';' ORDER BY view_name) FROM duckdb_views() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name), ` +
+		`(SELECT STRING_AGG(index_name || ' ' || sql, ';' ORDER BY index_name) FROM duckdb_indexes() WHERE database_name = CURRENT_DATABASE() AND schema_name = p.schema_name) ` +
+		`)) AS fingerprint ` +
+		`FROM (SELECT CAST($1 AS VARCHAR) AS schema_name) p`
+	// run
+	logf(sqlstr, schema)
+	var fingerprint string
+	if err := db.QueryRowContext(ctx, sqlstr, schema).Scan(&fingerprint); err != nil {
+		return "", logerror(err)
+	}
+	return fingerprint, nil
+}
+
+// DuckdbTableSQL retrieves the definition for a table.
+func DuckdbTableSQL(ctx context.Context, db DB, schema, table string) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`sql AS table_sql ` +
+		`FROM duckdb_tables() ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND schema_name = $1 ` +
+		`AND table_name = $2`
+	// run
+	logf(sqlstr, schema, table)
+	var tableSQL string
+	if err := db.QueryRowContext(ctx, sqlstr, schema, table).Scan(&tableSQL); err != nil {
+		return "", logerror(err)
+	}
+	return tableSQL, nil
+}
+
+// DuckdbIndexSQL retrieves the definition for an index.
+func DuckdbIndexSQL(ctx context.Context, db DB, schema, index string) (string, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`COALESCE(sql, '') AS index_sql ` +
+		`FROM duckdb_indexes() ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND schema_name = $1 ` +
+		`AND index_name = $2`
+	// run
+	logf(sqlstr, schema, index)
+	var indexSQL string
+	if err := db.QueryRowContext(ctx, sqlstr, schema, index).Scan(&indexSQL); err != nil {
+		return "", logerror(err)
+	}
+	return indexSQL, nil
+}
+
 // SqlserverViewCreate creates a view for introspection.
 func SqlserverViewCreate(ctx context.Context, db DB, schema, id string, query []string) (sql.Result, error) {
 	// query
@@ -135,6 +135,40 @@ func Sqlite3TableSequences(ctx context.Context, db DB, schema, table string) ([]
 	return res, nil
 }
 
+// DuckdbTableSequences runs a custom query, returning results as [Sequence].
+func DuckdbTableSequences(ctx context.Context, db DB, schema, table string) ([]*Sequence, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`column_name ` +
+		`FROM duckdb_columns() ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND schema_name = $1 ` +
+		`AND table_name = $2 ` +
+		`AND column_default LIKE 'nextval(%' ` +
+		`ORDER BY column_index`
+	// run
+	logf(sqlstr, schema, table)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, table)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// load results
+	var res []*Sequence
+	for rows.Next() {
+		var s Sequence
+		// scan
+		if err := rows.Scan(&s.ColumnName); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
+
 // SqlserverTableSequences runs a custom query, returning results as [Sequence].
 func SqlserverTableSequences(ctx context.Context, db DB, schema, table string) ([]*Sequence, error) {
 	// query
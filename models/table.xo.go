@@ -12,6 +12,7 @@ type Table struct {
 	TableName string `json:"table_name"` // table_name
 	ManualPk  bool   `json:"manual_pk"`  // manual_pk
 	ViewDef   string `json:"view_def"`   // view_def
+	Comment   string `json:"comment"`    // comment
 }
 
 // PostgresTables runs a custom query, returning results as [Table].
@@ -20,22 +21,43 @@ func PostgresTables(ctx context.Context, db DB, schema, typ string) ([]*Table, e
 	const sqlstr = `SELECT ` +
 		`(CASE c.relkind ` +
 		`WHEN 'r' THEN 'table' ` +
+		`WHEN 'p' THEN 'partitioned table' ` +
+		`WHEN 'f' THEN 'foreign table' ` +
 		`WHEN 'v' THEN 'view' ` +
+		`WHEN 'm' THEN 'materialized view' ` +
 		`END), ` + // ::varchar AS type
 		`c.relname, ` + // ::varchar AS table_name
 		`false, ` + // ::boolean AS manual_pk
 		`CASE c.relkind ` +
-		`WHEN 'r' THEN COALESCE(obj_description(c.relname::regclass), '') ` +
+		`WHEN 'p' THEN pg_get_partkeydef(c.oid) ` +
+		`WHEN 'f' THEN (` +
+		`SELECT 'SERVER ' || quote_ident(s.srvname) || COALESCE(' OPTIONS (' || (` +
+		`SELECT string_agg(quote_ident(o.option_name) || ' ' || quote_literal(o.option_value), ', ') ` +
+		`FROM pg_options_to_table(f.ftoptions) o` +
+		`) || ')', '') ` +
+		`FROM pg_foreign_table f ` +
+		`JOIN pg_foreign_server s ON s.oid = f.ftserver ` +
+		`WHERE f.ftrelid = c.oid` +
+		`) ` +
 		`WHEN 'v' THEN v.definition ` +
-		`END AS view_def ` +
+		`WHEN 'm' THEN m.definition ` +
+		`ELSE '' ` +
+		`END AS view_def, ` +
+		`COALESCE(obj_description(c.oid, 'pg_class'), '') ` + // ::varchar AS comment
 		`FROM pg_class c ` +
 		`JOIN ONLY pg_namespace n ON n.oid = c.relnamespace ` +
 		`LEFT JOIN pg_views v ON n.nspname = v.schemaname ` +
 		`AND v.viewname = c.relname ` +
+		`LEFT JOIN pg_matviews m ON n.nspname = m.schemaname ` +
+		`AND m.matviewname = c.relname ` +
 		`WHERE n.nspname = $1 ` +
+		`AND NOT c.relispartition ` +
 		`AND (CASE c.relkind ` +
 		`WHEN 'r' THEN 'table' ` +
+		`WHEN 'p' THEN 'table' ` +
+		`WHEN 'f' THEN 'table' ` +
 		`WHEN 'v' THEN 'view' ` +
+		`WHEN 'm' THEN 'view' ` +
 		`END) = LOWER($2)`
 	// run
 	logf(sqlstr, schema, typ)
@@ -49,7 +71,7 @@ func PostgresTables(ctx context.Context, db DB, schema, typ string) ([]*Table, e
 	for rows.Next() {
 		var t Table
 		// scan
-		if err := rows.Scan(&t.Type, &t.TableName, &t.ManualPk, &t.ViewDef); err != nil {
+		if err := rows.Scan(&t.Type, &t.TableName, &t.ManualPk, &t.ViewDef, &t.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &t)
@@ -72,7 +94,8 @@ func MysqlTables(ctx context.Context, db DB, schema, typ string) ([]*Table, erro
 		`CASE t.table_type ` +
 		`WHEN 'BASE TABLE' THEN '' ` +
 		`WHEN 'VIEW' then v.view_definition ` +
-		`END AS view_def ` +
+		`END AS view_def, ` +
+		`IF(t.table_type = 'VIEW', '', t.table_comment) AS comment ` +
 		`FROM information_schema.tables t ` +
 		`LEFT JOIN information_schema.views v ON t.table_schema = v.table_schema ` +
 		`AND t.table_name = v.table_name ` +
@@ -93,7 +116,7 @@ func MysqlTables(ctx context.Context, db DB, schema, typ string) ([]*Table, erro
 	for rows.Next() {
 		var t Table
 		// scan
-		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef); err != nil {
+		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef, &t.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &t)
@@ -114,7 +137,8 @@ func Sqlite3Tables(ctx context.Context, db DB, schema, typ string) ([]*Table, er
 		`CASE LOWER(type) ` +
 		`WHEN 'table' THEN '' ` +
 		`WHEN 'view' THEN sql ` +
-		`END AS view_def ` +
+		`END AS view_def, ` +
+		`'' AS comment ` +
 		`FROM sqlite_master ` +
 		`WHERE tbl_name NOT LIKE 'sqlite_%' ` +
 		`AND LOWER(type) = LOWER($1)`
@@ -130,7 +154,64 @@ func Sqlite3Tables(ctx context.Context, db DB, schema, typ string) ([]*Table, er
 	for rows.Next() {
 		var t Table
 		// scan
-		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef); err != nil {
+		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef, &t.Comment); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
+
+// DuckdbTables runs a custom query, returning results as [Table].
+func DuckdbTables(ctx context.Context, db DB, schema, typ string) ([]*Table, error) {
+	// query
+	const sqlstr = `SELECT ` +
+		`type, ` +
+		`table_name, ` +
+		`view_def, ` +
+		`comment ` +
+		`FROM ( ` +
+		`SELECT ` +
+		`'table' AS type, ` +
+		`schema_name, ` +
+		`table_name, ` +
+		`'' AS view_def, ` +
+		`COALESCE(comment, '') AS comment ` +
+		`FROM duckdb_tables() ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND NOT internal ` +
+		`AND NOT temporary ` +
+		`UNION ALL ` +
+		`SELECT ` +
+		`'view' AS type, ` +
+		`schema_name, ` +
+		`view_name AS table_name, ` +
+		`sql AS view_def, ` +
+		`COALESCE(comment, '') AS comment ` +
+		`FROM duckdb_views() ` +
+		`WHERE database_name = CURRENT_DATABASE() ` +
+		`AND NOT internal ` +
+		`AND NOT temporary ` +
+		`) t ` +
+		`WHERE schema_name = $1 ` +
+		`AND type = LOWER($2) ` +
+		`ORDER BY table_name`
+	// run
+	logf(sqlstr, schema, typ)
+	rows, err := db.QueryContext(ctx, sqlstr, schema, typ)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// load results
+	var res []*Table
+	for rows.Next() {
+		var t Table
+		// scan
+		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef, &t.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &t)
@@ -145,18 +226,23 @@ func Sqlite3Tables(ctx context.Context, db DB, schema, typ string) ([]*Table, er
 func SqlserverTables(ctx context.Context, db DB, schema, typ string) ([]*Table, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`(CASE xtype ` +
+		`(CASE o.xtype ` +
 		`WHEN 'U' THEN 'table' ` +
 		`WHEN 'V' THEN 'view' ` +
 		`END) AS type, ` +
-		`name AS table_name, ` +
-		`CASE xtype ` +
+		`o.name AS table_name, ` +
+		`CASE o.xtype ` +
 		`WHEN 'U' THEN '' ` +
-		`WHEN 'V' THEN OBJECT_DEFINITION(id) ` +
-		`END AS view_def ` +
-		`FROM sysobjects ` +
-		`WHERE SCHEMA_NAME(uid) = @p1 ` +
-		`AND (CASE xtype ` +
+		`WHEN 'V' THEN OBJECT_DEFINITION(o.id) ` +
+		`END AS view_def, ` +
+		`COALESCE(CAST(p.value AS NVARCHAR(4000)), '') AS comment ` +
+		`FROM sysobjects o ` +
+		`LEFT JOIN sys.extended_properties p ON p.major_id = o.id ` +
+		`AND p.minor_id = 0 ` +
+		`AND p.class = 1 ` +
+		`AND p.name = 'MS_Description' ` +
+		`WHERE SCHEMA_NAME(o.uid) = @p1 ` +
+		`AND (CASE o.xtype ` +
 		`WHEN 'U' THEN 'table' ` +
 		`WHEN 'V' THEN 'view' ` +
 		`END) = LOWER(@p2)`
@@ -172,7 +258,7 @@ func SqlserverTables(ctx context.Context, db DB, schema, typ string) ([]*Table,
 	for rows.Next() {
 		var t Table
 		// scan
-		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef); err != nil {
+		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef, &t.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &t)
@@ -192,10 +278,13 @@ func OracleTables(ctx context.Context, db DB, schema, typ string) ([]*Table, err
 		`CASE o.object_type ` +
 		`WHEN 'TABLE' THEN ' ' ` +
 		`WHEN 'VIEW' THEN v.text_vc ` +
-		`END AS view_def ` +
+		`END AS view_def, ` +
+		`NVL(c.comments, ' ') AS comment ` +
 		`FROM all_objects o ` +
 		`LEFT JOIN all_views v ON o.owner = v.owner ` +
 		`AND o.object_name = v.view_name ` +
+		`LEFT JOIN all_tab_comments c ON o.owner = c.owner ` +
+		`AND o.object_name = c.table_name ` +
 		`WHERE o.object_name NOT LIKE '%$%' ` +
 		`AND o.object_name NOT LIKE 'LOGMNR%_%' ` +
 		`AND o.object_name NOT LIKE 'REDO_%' ` +
@@ -215,7 +304,7 @@ func OracleTables(ctx context.Context, db DB, schema, typ string) ([]*Table, err
 	for rows.Next() {
 		var t Table
 		// scan
-		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef); err != nil {
+		if err := rows.Scan(&t.Type, &t.TableName, &t.ViewDef, &t.Comment); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &t)
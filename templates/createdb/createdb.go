@@ -27,7 +27,7 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 		fmtOpts = []string{"-u", "-l={{ . }}", "-i=2", "--lines-between-queries=2"}
 	}
 	f(xo.TemplateType{
-		Modes: []string{"schema"},
+		Modes: []string{"schema", "diff"},
 		Flags: []xo.Flag{
 			{
 				ContextKey: AppendKey,
@@ -76,12 +76,16 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 		},
 		Funcs: NewFuncs,
 		Order: func(ctx context.Context, mode string) []string {
-			return []string{"header", "createdb"}
+			return []string{"header", "createdb", "diff"}
 		},
 		Pre: func(ctx context.Context, mode string, set *xo.Set, out fs.FS, emit func(xo.Template)) error {
-			for _, schema := range set.Schemas {
+			filename, schemas := "xo.xo.sql", set.Schemas
+			if mode == "diff" && len(schemas) != 0 {
+				// Diffs are headed by the schema being migrated to.
+				filename, schemas = "xo.xo.diff.sql", schemas[len(schemas)-1:]
+			}
+			for _, schema := range schemas {
 				// Add support for append mode.
-				filename := "xo.xo.sql"
 				if single := xo.Single(ctx); single != "" {
 					filename = single
 				}
@@ -124,11 +128,23 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 			}
 			return nil
 		},
-		Process: func(ctx context.Context, _ string, set *xo.Set, emit func(xo.Template)) error {
+		Process: func(ctx context.Context, mode string, set *xo.Set, emit func(xo.Template)) error {
+			if mode == "diff" {
+				if len(set.Schemas) != 2 {
+					return errors.New("createdb diff must be passed exactly two schemas")
+				}
+				emit(xo.Template{
+					Partial: "diff",
+					Dest:    "xo.xo.diff.sql",
+					Data:    newFuncs(ctx).diffSchemas(set.Schemas[0], set.Schemas[1]),
+				})
+				return nil
+			}
 			if len(set.Schemas) == 0 {
 				return errors.New("createdb template must be passed at least one schema")
 			}
 			for _, schema := range set.Schemas {
+				schema.Domains = sortDomains(schema.Domains)
 				schema.Tables = sortTables(schema.Tables)
 
 				emit(xo.Template{
@@ -206,6 +222,10 @@ func sortAppendTable(m map[string]xo.Table, seen map[string]bool, sorted []xo.Ta
 		return sorted
 	}
 	for _, fk := range table.ForeignKeys {
+		// ref table in another schema
+		if fk.RefSchema != "" {
+			continue
+		}
 		t, ok := m[fk.RefTable]
 		if !ok {
 			panic(fmt.Sprintf(
@@ -221,6 +241,34 @@ func sortAppendTable(m map[string]xo.Table, seen map[string]bool, sorted []xo.Ta
 	return append(sorted, table)
 }
 
+// sortDomains sorts domains so that domains are created after the domains
+// they are based on.
+func sortDomains(domains []xo.Domain) []xo.Domain {
+	m := make(map[string]xo.Domain)
+	for _, d := range domains {
+		m[d.Name] = d
+	}
+	seen := make(map[string]bool)
+	var sorted []xo.Domain
+	for _, d := range domains {
+		sorted = sortAppendDomain(m, seen, sorted, d)
+	}
+	return sorted
+}
+
+// sortAppendDomain appends and returns the domain and the domain it is based
+// on if not already in seen.
+func sortAppendDomain(m map[string]xo.Domain, seen map[string]bool, sorted []xo.Domain, domain xo.Domain) []xo.Domain {
+	if seen[domain.Name] {
+		return sorted
+	}
+	seen[domain.Name] = true
+	if base, ok := m[domain.Type.Type]; ok {
+		sorted = sortAppendDomain(m, seen, sorted, base)
+	}
+	return append(sorted, domain)
+}
+
 // Funcs is a set of template funcs.
 type Funcs struct {
 	driver      string
@@ -233,42 +281,69 @@ type Funcs struct {
 
 // NewFuncs creates custom template funcs for the context.
 func NewFuncs(ctx context.Context, _ string) (template.FuncMap, error) {
-	driver, _, _ := xo.DriverDbSchema(ctx)
-	funcs := &Funcs{
-		driver:      driver,
-		constraint:  Constraint(ctx),
-		escCols:     Esc(ctx, "columns"),
-		escTypes:    Esc(ctx, "types"),
-		engine:      Engine(ctx),
-		trimComment: TrimComment(ctx),
-	}
+	funcs := newFuncs(ctx)
 	return template.FuncMap{
 		"coldef":          funcs.coldef,
+		"checkdef":        funcs.checkdef,
 		"viewdef":         funcs.viewdef,
+		"domaindef":       funcs.domaindef,
+		"attrdef":         funcs.attrdef,
 		"procdef":         funcs.procdef,
 		"driver":          funcs.driverfn,
 		"constraint":      funcs.constraintfn,
 		"esc":             funcs.escType,
 		"fields":          funcs.fields,
 		"engine":          funcs.enginefn,
+		"tableopts":       funcs.tableopts,
 		"literal":         funcs.literal,
 		"isEndConstraint": funcs.isEndConstraint,
+		"sequences":       funcs.sequences,
 		"comma":           comma,
+		// diff
+		"addColumn":      funcs.addColumn,
+		"dropColumn":     funcs.dropColumn,
+		"alterColumn":    funcs.alterColumn,
+		"createIndex":    funcs.createIndex,
+		"dropIndex":      funcs.dropIndex,
+		"addForeignKey":  funcs.addForeignKey,
+		"dropForeignKey": funcs.dropForeignKey,
+		"addCheck":       funcs.addCheck,
+		"dropCheck":      funcs.dropCheck,
+		"dropView":       funcs.dropView,
+		"renameTable":    funcs.renameTable,
+		"renameColumn":   funcs.renameColumn,
 	}, nil
 }
 
+// newFuncs creates the template funcs for the context.
+func newFuncs(ctx context.Context) *Funcs {
+	driver, _, _ := xo.DriverDbSchema(ctx)
+	return &Funcs{
+		driver:      driver,
+		constraint:  Constraint(ctx),
+		escCols:     Esc(ctx, "columns"),
+		escTypes:    Esc(ctx, "types"),
+		engine:      Engine(ctx),
+		trimComment: TrimComment(ctx),
+	}
+}
+
 // coldef generates a column definition.
 func (f *Funcs) coldef(table xo.Table, field xo.Field) string {
 	// normalize type
 	typ := f.normalize(field.Type)
 	// add sequence definition
 	if field.IsSequence {
-		typ = f.resolveSequence(typ, field)
+		typ = f.resolveSequence(table, typ, field)
 	}
 	// column def
 	def := []string{f.escCol(field.Name), typ}
-	// add default value
-	if field.Default != "" && !field.IsSequence {
+	switch {
+	case field.IsGenerated && field.Default != "":
+		// add generated expression
+		def = f.generated(def, field)
+	case field.Default != "" && !field.IsSequence:
+		// add default value
 		def = append(def, "DEFAULT", f.alterDefault(field.Default))
 	}
 	if !field.Type.Nullable && !field.IsSequence {
@@ -281,6 +356,60 @@ func (f *Funcs) coldef(table xo.Table, field xo.Field) string {
 	return strings.Join(def, " ")
 }
 
+// generated adds the generated column expression to a column definition.
+func (f *Funcs) generated(def []string, field xo.Field) []string {
+	expr := "(" + checkExpr(field.Default) + ")"
+	switch f.driver {
+	case "sqlserver":
+		// computed columns do not have a type
+		return []string{def[0], "AS", expr, "PERSISTED"}
+	case "oracle", "duckdb":
+		return append(def, "GENERATED ALWAYS AS", expr, "VIRTUAL")
+	}
+	return append(def, "GENERATED ALWAYS AS", expr, "STORED")
+}
+
+// checkdef generates a check constraint definition.
+func (f *Funcs) checkdef(c xo.Check) string {
+	var constraint string
+	if c.Name != "" {
+		constraint = f.constraintfn(c.Name)
+	}
+	return constraint + "CHECK (" + checkExpr(c.Expr) + ")"
+}
+
+// checkExpr returns the check expression without any enclosing parens, as
+// some databases report the expression wrapped in parens.
+func checkExpr(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for len(expr) > 1 && expr[0] == '(' && closingParen(expr) == len(expr)-1 {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// closingParen returns the position of the paren closing the paren at the
+// start of s, skipping quoted strings.
+func closingParen(s string) int {
+	var depth int
+	var quote bool
+	for i, c := range s {
+		switch {
+		case c == '\'':
+			quote = !quote
+		case quote:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 // alterDefault parses and alters default column values based on the driver.
 func (f *Funcs) alterDefault(s string) string {
 	switch f.driver {
@@ -312,7 +441,7 @@ var postgresDefaultCastRE = regexp.MustCompile(`(.*)::[a-zA-Z_ ]*(\[\])?$`)
 var sqliteDefaultNeedsParenRE = regexp.MustCompile(`^([\('"].*[\)'"]|\d+)$`)
 
 // resolveSequence resolves a sequence name.
-func (f *Funcs) resolveSequence(typ string, field xo.Field) string {
+func (f *Funcs) resolveSequence(table xo.Table, typ string, field xo.Field) string {
 	switch f.driver {
 	case "postgres":
 		switch typ {
@@ -335,15 +464,41 @@ func (f *Funcs) resolveSequence(typ string, field xo.Field) string {
 		return typ + " IDENTITY(1, 1)"
 	case "oracle":
 		return typ + " GENERATED ALWAYS AS IDENTITY"
+	case "duckdb":
+		return typ + " DEFAULT nextval(" + f.literal(sequenceName(table, field)) + ")"
 	}
 	return ""
 }
 
+// sequences returns the names of the sequences of a table's sequence fields,
+// for drivers where sequences are created before the table.
+func (f *Funcs) sequences(table xo.Table) []string {
+	if f.driver != "duckdb" {
+		return nil
+	}
+	var names []string
+	for _, field := range table.Columns {
+		if field.IsSequence {
+			names = append(names, f.escType(sequenceName(table, field)))
+		}
+	}
+	return names
+}
+
+// sequenceName returns the sequence name for a sequence field, named the way
+// postgres names the sequences of serial columns.
+func sequenceName(table xo.Table, field xo.Field) string {
+	return table.Name + "_" + field.Name + "_seq"
+}
+
 // colFKey
 func (f *Funcs) colFKey(table xo.Table, field xo.Field) string {
 	for _, fk := range table.ForeignKeys {
-		if len(fk.Fields) == 1 && fk.Fields[0] == field {
+		if len(fk.Fields) == 1 && fk.Fields[0].Name == field.Name {
 			tblName, fieldName := f.escType(fk.RefTable), fk.RefFields[0].Name
+			if fk.RefSchema != "" {
+				tblName = f.escType(fk.RefSchema) + "." + tblName
+			}
 			return fmt.Sprintf("%sREFERENCES %s (%s)", f.constraintfn(fk.Name), tblName, fieldName)
 		}
 	}
@@ -355,7 +510,11 @@ func (f *Funcs) viewdef(view xo.Table) string {
 	def := view.Definition
 	switch f.driver {
 	case "postgres", "mysql", "oracle":
-		def = fmt.Sprintf("CREATE VIEW %s AS\n%s", f.escType(view.Name), view.Definition)
+		kind := "VIEW"
+		if view.Type == "materialized view" {
+			kind = "MATERIALIZED VIEW"
+		}
+		def = fmt.Sprintf("CREATE %s %s AS\n%s", kind, f.escType(view.Name), view.Definition)
 	}
 	if f.trimComment {
 		if strings.HasPrefix(def, "--") {
@@ -365,6 +524,26 @@ func (f *Funcs) viewdef(view xo.Table) string {
 	return strings.TrimSuffix(def, ";")
 }
 
+// domaindef generates a domain definition.
+func (f *Funcs) domaindef(domain xo.Domain) string {
+	def := []string{"CREATE DOMAIN", f.escType(domain.Name), "AS", f.normalize(domain.Type)}
+	if domain.Default != "" {
+		def = append(def, "DEFAULT", domain.Default)
+	}
+	if !domain.Type.Nullable {
+		def = append(def, "NOT NULL")
+	}
+	for _, c := range domain.Checks {
+		def = append(def, f.checkdef(c))
+	}
+	return strings.Join(def, " ")
+}
+
+// attrdef generates a composite type attribute definition.
+func (f *Funcs) attrdef(attr xo.Field) string {
+	return f.escCol(attr.Name) + " " + f.normalize(attr.Type)
+}
+
 // procdef generates a proc definition.
 func (f *Funcs) procdef(proc xo.Proc) string {
 	def := f.cleanProcDef(proc.Definition)
@@ -452,6 +631,16 @@ func (f *Funcs) fields(v interface{}) string {
 			fs = append(fs, f.escCol(field.Name))
 		}
 		return strings.Join(fs, ", ")
+	case xo.Index:
+		var fs []string
+		for i, field := range x.Fields {
+			if i < len(x.Exprs) && x.Exprs[i] != "" {
+				fs = append(fs, "("+x.Exprs[i]+")")
+				continue
+			}
+			fs = append(fs, f.escCol(field.Name))
+		}
+		return strings.Join(fs, ", ")
 	}
 	return fmt.Sprintf("[[ UNKNOWN TYPE %T ]]", v)
 }
@@ -463,7 +652,7 @@ func (f *Funcs) esc(s string, esc bool) string {
 	}
 	var start, end string
 	switch f.driver {
-	case "postgres", "sqlite3", "oracle":
+	case "postgres", "sqlite3", "oracle", "duckdb":
 		start, end = `"`, `"`
 	case "mysql":
 		start, end = "`", "`"
@@ -491,6 +680,19 @@ func (f *Funcs) enginefn() string {
 	return fmt.Sprintf(" ENGINE=%s", f.engine)
 }
 
+// tableopts generates the partition key of a partitioned table, or the server
+// and options of a foreign table.
+func (f *Funcs) tableopts(table xo.Table) string {
+	switch {
+	case f.driver != "postgres" || table.Definition == "":
+	case table.Type == "partitioned table":
+		return " PARTITION BY " + table.Definition
+	case table.Type == "foreign table":
+		return " " + table.Definition
+	}
+	return ""
+}
+
 // normalize normalizes a datatype.
 func (f *Funcs) normalize(datatype xo.Type) string {
 	typ := f.convert(datatype)
@@ -523,6 +725,10 @@ func (f *Funcs) convert(datatype xo.Type) string {
 	if alias, ok := typeAliases[f.driver][typ]; ok {
 		typ = alias
 	}
+	// duckdb nested types keep the case of their field names and values
+	if i := strings.IndexByte(typ, '('); f.driver == "duckdb" && i != -1 {
+		return strings.ToUpper(typ[:i]) + typ[i:]
+	}
 	return strings.ToUpper(typ)
 }
 
@@ -536,6 +742,10 @@ func (f *Funcs) isEndConstraint(idx xo.Index) bool {
 	if f.driver == "sqlite3" && idx.Fields[0].IsSequence {
 		return false
 	}
+	// expression and partial indexes can only be created separately
+	if !idx.IsPrimary && (idx.Exprs != nil || idx.Predicate != "") {
+		return false
+	}
 	return idx.IsPrimary || idx.IsUnique
 }
 
@@ -644,7 +854,7 @@ func TrimComment(ctx context.Context) bool {
 func Lang(ctx context.Context) string {
 	driver, _, _ := xo.DriverDbSchema(ctx)
 	switch driver {
-	case "postgres", "sqlite3":
+	case "postgres", "sqlite3", "duckdb":
 		return "postgresql"
 	case "mysql":
 		return "mysql"
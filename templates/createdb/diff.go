@@ -0,0 +1,584 @@
+//go:build xotpl
+
+package createdb
+
+import (
+	"fmt"
+	"strings"
+
+	xo "github.com/xo/xo/types"
+)
+
+// Diff is the difference between two schemas.
+type Diff struct {
+	From             xo.Schema
+	To               xo.Schema
+	CreateEnums      []xo.Enum
+	AlterEnums       []EnumDiff
+	DropEnums        []xo.Enum
+	CreateDomains    []xo.Domain
+	DropDomains      []xo.Domain
+	CreateComposites []xo.Composite
+	DropComposites   []xo.Composite
+	CreateTables     []xo.Table
+	AlterTables      []TableDiff
+	DropTables       []xo.Table
+	RenameTables     []TableRename
+	CreateViews      []xo.Table
+	DropViews        []xo.Table
+}
+
+// TableRename is a dropped table and a created table with the same columns,
+// that may have been renamed.
+type TableRename struct {
+	From xo.Table
+	To   xo.Table
+}
+
+// EnumDiff is the difference between two enums.
+type EnumDiff struct {
+	Enum       xo.Enum
+	AddValues  []xo.Field
+	DropValues []xo.Field
+}
+
+// TableDiff is the difference between two tables.
+type TableDiff struct {
+	Table           xo.Table
+	AddColumns      []xo.Field
+	DropColumns     []xo.Field
+	AlterColumns    []ColumnDiff
+	AddIndexes      []xo.Index
+	DropIndexes     []xo.Index
+	AddForeignKeys  []xo.ForeignKey
+	DropForeignKeys []xo.ForeignKey
+	AddChecks       []xo.Check
+	DropChecks      []xo.Check
+	RenameColumns   []ColumnDiff
+}
+
+// ColumnDiff is the difference between two columns.
+type ColumnDiff struct {
+	From xo.Field
+	To   xo.Field
+}
+
+// diffSchemas computes the difference between two schemas.
+//
+// Created tables are ordered by their foreign key dependencies, and dropped
+// tables in reverse. Domains and composite types are created and dropped, but
+// not altered. Changed views are dropped and recreated. Renamed tables and
+// columns are seen as a drop and an add, and are noted when the definitions
+// match. Procs are not compared.
+func (f *Funcs) diffSchemas(from, to xo.Schema) Diff {
+	d := Diff{
+		From: from,
+		To:   to,
+	}
+	// enums
+	fromEnums := make(map[string]xo.Enum)
+	for _, e := range from.Enums {
+		fromEnums[e.Name] = e
+	}
+	toEnums := make(map[string]bool)
+	for _, e := range to.Enums {
+		toEnums[e.Name] = true
+		prev, ok := fromEnums[e.Name]
+		if !ok {
+			d.CreateEnums = append(d.CreateEnums, e)
+			continue
+		}
+		ed := EnumDiff{
+			Enum:       e,
+			AddValues:  missingFields(e.Values, prev.Values),
+			DropValues: missingFields(prev.Values, e.Values),
+		}
+		if len(ed.AddValues) != 0 || len(ed.DropValues) != 0 {
+			d.AlterEnums = append(d.AlterEnums, ed)
+		}
+	}
+	for _, e := range from.Enums {
+		if !toEnums[e.Name] {
+			d.DropEnums = append(d.DropEnums, e)
+		}
+	}
+	// domains
+	fromDomains, toDomains := make(map[string]bool), make(map[string]bool)
+	for _, dom := range from.Domains {
+		fromDomains[dom.Name] = true
+	}
+	for _, dom := range sortDomains(to.Domains) {
+		toDomains[dom.Name] = true
+		if !fromDomains[dom.Name] {
+			d.CreateDomains = append(d.CreateDomains, dom)
+		}
+	}
+	sortedDomains := sortDomains(from.Domains)
+	for i := len(sortedDomains) - 1; i >= 0; i-- {
+		if !toDomains[sortedDomains[i].Name] {
+			d.DropDomains = append(d.DropDomains, sortedDomains[i])
+		}
+	}
+	// composite types
+	fromComposites, toComposites := make(map[string]bool), make(map[string]bool)
+	for _, c := range from.Composites {
+		fromComposites[c.Name] = true
+	}
+	for _, c := range to.Composites {
+		toComposites[c.Name] = true
+		if !fromComposites[c.Name] {
+			d.CreateComposites = append(d.CreateComposites, c)
+		}
+	}
+	for _, c := range from.Composites {
+		if !toComposites[c.Name] {
+			d.DropComposites = append(d.DropComposites, c)
+		}
+	}
+	// tables
+	fromTables := make(map[string]xo.Table)
+	for _, t := range from.Tables {
+		fromTables[t.Name] = t
+	}
+	toTables := make(map[string]bool)
+	for _, t := range sortTables(to.Tables) {
+		toTables[t.Name] = true
+		prev, ok := fromTables[t.Name]
+		if !ok {
+			d.CreateTables = append(d.CreateTables, t)
+			continue
+		}
+		if td := f.diffTables(prev, t); !td.empty() {
+			d.AlterTables = append(d.AlterTables, td)
+		}
+	}
+	sorted := sortTables(from.Tables)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if !toTables[sorted[i].Name] {
+			d.DropTables = append(d.DropTables, sorted[i])
+		}
+	}
+	for _, t := range d.DropTables {
+		for _, z := range d.CreateTables {
+			if f.columnDefs(t) == f.columnDefs(z) && !d.renamed(z) {
+				d.RenameTables = append(d.RenameTables, TableRename{From: t, To: z})
+				break
+			}
+		}
+	}
+	// views
+	fromViews := make(map[string]xo.Table)
+	for _, v := range from.Views {
+		fromViews[v.Name] = v
+	}
+	toViews := make(map[string]xo.Table)
+	for _, v := range to.Views {
+		toViews[v.Name] = v
+		if prev, ok := fromViews[v.Name]; !ok || viewChanged(prev, v) {
+			d.CreateViews = append(d.CreateViews, v)
+		}
+	}
+	for i := len(from.Views) - 1; i >= 0; i-- {
+		v := from.Views[i]
+		if next, ok := toViews[v.Name]; !ok || viewChanged(v, next) {
+			d.DropViews = append(d.DropViews, v)
+		}
+	}
+	return d
+}
+
+// renamed returns whether the table is the destination of a possible rename.
+func (d Diff) renamed(table xo.Table) bool {
+	for _, r := range d.RenameTables {
+		if r.To.Name == table.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// viewChanged returns whether the view definition changed.
+func viewChanged(from, to xo.Table) bool {
+	return from.Type != to.Type || strings.TrimSpace(from.Definition) != strings.TrimSpace(to.Definition)
+}
+
+// columnDefs returns the names and types of the table's columns.
+func (f *Funcs) columnDefs(table xo.Table) string {
+	var defs []string
+	for _, c := range table.Columns {
+		defs = append(defs, c.Name+" "+f.columnDef(c))
+	}
+	return strings.Join(defs, ", ")
+}
+
+// columnDef returns the type, nullability and default of a column.
+func (f *Funcs) columnDef(field xo.Field) string {
+	def := f.normalize(field.Type)
+	if !field.Type.Nullable {
+		def += " NOT NULL"
+	}
+	if s := f.alterDefault(field.Default); s != "" {
+		def += " DEFAULT " + s
+	}
+	return def
+}
+
+// diffTables computes the difference between two tables.
+func (f *Funcs) diffTables(from, to xo.Table) TableDiff {
+	td := TableDiff{
+		Table:       to,
+		AddColumns:  missingFields(to.Columns, from.Columns),
+		DropColumns: missingFields(from.Columns, to.Columns),
+	}
+	// columns
+	for _, c := range to.Columns {
+		for _, prev := range from.Columns {
+			if prev.Name == c.Name && f.columnChanged(prev, c) {
+				td.AlterColumns = append(td.AlterColumns, ColumnDiff{From: prev, To: c})
+			}
+		}
+	}
+	// indexes
+	td.AddIndexes = missingIndexes(to.Indexes, from.Indexes)
+	td.DropIndexes = missingIndexes(from.Indexes, to.Indexes)
+	// foreign keys
+	td.AddForeignKeys = missingForeignKeys(to.ForeignKeys, from.ForeignKeys)
+	td.DropForeignKeys = missingForeignKeys(from.ForeignKeys, to.ForeignKeys)
+	// checks
+	td.AddChecks = missingChecks(to.Checks, from.Checks)
+	td.DropChecks = missingChecks(from.Checks, to.Checks)
+	// renamed columns
+	added := make(map[string]bool)
+	for _, c := range td.DropColumns {
+		for _, z := range td.AddColumns {
+			if !added[z.Name] && f.columnDef(c) == f.columnDef(z) {
+				td.RenameColumns = append(td.RenameColumns, ColumnDiff{From: c, To: z})
+				added[z.Name] = true
+				break
+			}
+		}
+	}
+	return td
+}
+
+// empty returns whether there are no differences for the table.
+func (td TableDiff) empty() bool {
+	return len(td.AddColumns) == 0 && len(td.DropColumns) == 0 && len(td.AlterColumns) == 0 &&
+		len(td.AddIndexes) == 0 && len(td.DropIndexes) == 0 &&
+		len(td.AddForeignKeys) == 0 && len(td.DropForeignKeys) == 0 &&
+		len(td.AddChecks) == 0 && len(td.DropChecks) == 0
+}
+
+// columnChanged returns whether the column definition changed.
+func (f *Funcs) columnChanged(from, to xo.Field) bool {
+	return f.normalize(from.Type) != f.normalize(to.Type) ||
+		from.Type.Nullable != to.Type.Nullable ||
+		f.alterDefault(from.Default) != f.alterDefault(to.Default) ||
+		from.IsSequence != to.IsSequence
+}
+
+// missingFields returns the fields in a not in b, by name.
+func missingFields(a, b []xo.Field) []xo.Field {
+	var fields []xo.Field
+	for _, field := range a {
+		if !hasField(b, field.Name) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// hasField returns whether fields contains a field with name.
+func hasField(fields []xo.Field, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// missingIndexes returns the indexes in a not in b. An index with the same
+// name but a different definition is considered missing.
+func missingIndexes(a, b []xo.Index) []xo.Index {
+	var indexes []xo.Index
+	for _, idx := range a {
+		found := false
+		for _, z := range b {
+			if idx.Name == z.Name && idx.IsUnique == z.IsUnique && idx.IsPrimary == z.IsPrimary &&
+				fieldNames(idx.Fields) == fieldNames(z.Fields) &&
+				strings.Join(idx.Exprs, ",") == strings.Join(z.Exprs, ",") && idx.Predicate == z.Predicate {
+				found = true
+				break
+			}
+		}
+		if !found {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes
+}
+
+// missingForeignKeys returns the foreign keys in a not in b. A foreign key
+// with the same name but a different definition is considered missing.
+func missingForeignKeys(a, b []xo.ForeignKey) []xo.ForeignKey {
+	var fks []xo.ForeignKey
+	for _, fk := range a {
+		found := false
+		for _, z := range b {
+			if fk.Name == z.Name && fk.RefSchema == z.RefSchema && fk.RefTable == z.RefTable &&
+				fieldNames(fk.Fields) == fieldNames(z.Fields) &&
+				fieldNames(fk.RefFields) == fieldNames(z.RefFields) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fks = append(fks, fk)
+		}
+	}
+	return fks
+}
+
+// missingChecks returns the check constraints in a not in b. A check with the
+// same name but a different expression is considered missing.
+func missingChecks(a, b []xo.Check) []xo.Check {
+	var checks []xo.Check
+	for _, c := range a {
+		found := false
+		for _, z := range b {
+			if c.Name == z.Name && checkExpr(c.Expr) == checkExpr(z.Expr) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// fieldNames returns the comma separated names of fields.
+func fieldNames(fields []xo.Field) string {
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// note returns a sql comment noting a change that could not be generated.
+func note(format string, v ...interface{}) string {
+	return "-- NOTE: " + fmt.Sprintf(format, v...)
+}
+
+// addColumn generates the statement adding a column to a table.
+//
+// A not null column without a default cannot be added to a table with rows,
+// so is added as nullable, followed by a note and the statement altering it to
+// not null once its values are set. Foreign keys are added separately (see
+// addForeignKey).
+func (f *Funcs) addColumn(table xo.Table, field xo.Field) string {
+	table.ForeignKeys = nil
+	if field.Type.Nullable || field.Default != "" || field.IsSequence {
+		return f.addColumnDef(table, field)
+	}
+	nullable := field
+	nullable.Type.Nullable = true
+	stmt := f.addColumnDef(table, nullable)
+	if f.driver == "sqlite3" {
+		return stmt + "\n" + note("%s.%s is added as nullable, as sqlite3 cannot add a not null column without a default", table.Name, field.Name)
+	}
+	return stmt + "\n" +
+		note("%s.%s is added as nullable; set its values before altering it to not null", table.Name, field.Name) + "\n" +
+		f.alterColumn(table, ColumnDiff{From: nullable, To: field})
+}
+
+// addColumnDef generates the statement adding a column definition to a table.
+func (f *Funcs) addColumnDef(table xo.Table, field xo.Field) string {
+	def := f.coldef(table, field)
+	switch f.driver {
+	case "sqlserver":
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;", f.escType(table.Name), def)
+	case "oracle":
+		return fmt.Sprintf("ALTER TABLE %s ADD (%s);", f.escType(table.Name), def)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", f.escType(table.Name), def)
+}
+
+// dropColumn generates the statement dropping a column from a table.
+func (f *Funcs) dropColumn(table xo.Table, field xo.Field) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", f.escType(table.Name), f.escCol(field.Name))
+}
+
+// alterColumn generates the statements altering a column.
+func (f *Funcs) alterColumn(table xo.Table, cd ColumnDiff) string {
+	tableName, name := f.escType(table.Name), f.escCol(cd.To.Name)
+	table.ForeignKeys = nil
+	switch f.driver {
+	case "postgres", "duckdb":
+		if cd.From.IsSequence != cd.To.IsSequence {
+			return note("cannot alter sequence of column %s.%s", table.Name, cd.To.Name)
+		}
+		prefix := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ", tableName, name)
+		var stmts []string
+		if typ := f.normalize(cd.To.Type); typ != f.normalize(cd.From.Type) {
+			stmts = append(stmts, prefix+"TYPE "+typ+";")
+		}
+		if cd.From.Type.Nullable != cd.To.Type.Nullable {
+			if cd.To.Type.Nullable {
+				stmts = append(stmts, prefix+"DROP NOT NULL;")
+			} else {
+				stmts = append(stmts, prefix+"SET NOT NULL;")
+			}
+		}
+		if def := f.alterDefault(cd.To.Default); def != f.alterDefault(cd.From.Default) && !cd.To.IsSequence {
+			if def == "" {
+				stmts = append(stmts, prefix+"DROP DEFAULT;")
+			} else {
+				stmts = append(stmts, prefix+"SET DEFAULT "+def+";")
+			}
+		}
+		return strings.Join(stmts, "\n")
+	case "mysql":
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", tableName, f.coldef(table, cd.To))
+	case "oracle":
+		return fmt.Sprintf("ALTER TABLE %s MODIFY (%s);", tableName, f.coldef(table, cd.To))
+	case "sqlserver":
+		if f.alterDefault(cd.From.Default) != f.alterDefault(cd.To.Default) || cd.From.IsSequence != cd.To.IsSequence {
+			return note("cannot alter default or identity of column %s.%s", table.Name, cd.To.Name)
+		}
+		null := "NOT NULL"
+		if cd.To.Type.Nullable {
+			null = "NULL"
+		}
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s;", tableName, name, f.normalize(cd.To.Type), null)
+	}
+	return note("%s does not support altering column %s.%s", f.driver, table.Name, cd.To.Name)
+}
+
+// createIndex generates the statement creating an index.
+func (f *Funcs) createIndex(table xo.Table, idx xo.Index) string {
+	tableName := f.escType(table.Name)
+	if idx.IsPrimary {
+		if f.driver == "sqlite3" || f.driver == "duckdb" {
+			return note("%s does not support adding primary key %s", f.driver, idx.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s);", tableName, f.escType(idx.Name), f.fields(idx.Fields))
+	}
+	unique, where := "", ""
+	if idx.IsUnique {
+		unique = "UNIQUE "
+	}
+	if idx.Predicate != "" {
+		where = " WHERE " + idx.Predicate
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)%s;", unique, f.escType(idx.Name), tableName, f.fields(idx), where)
+}
+
+// dropIndex generates the statement dropping an index.
+func (f *Funcs) dropIndex(table xo.Table, idx xo.Index) string {
+	tableName, name := f.escType(table.Name), f.escType(idx.Name)
+	switch {
+	case idx.IsPrimary && (f.driver == "sqlite3" || f.driver == "duckdb"):
+		return note("%s does not support dropping primary key %s", f.driver, idx.Name)
+	case idx.IsPrimary && f.driver == "mysql":
+		return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", tableName)
+	case idx.IsPrimary:
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tableName, name)
+	case f.driver == "mysql" || f.driver == "sqlserver":
+		return fmt.Sprintf("DROP INDEX %s ON %s;", name, tableName)
+	}
+	return fmt.Sprintf("DROP INDEX %s;", name)
+}
+
+// addForeignKey generates the statement adding a foreign key.
+//
+// Unlike table definitions, the constraint is always named, so that it can be
+// dropped by a later diff.
+func (f *Funcs) addForeignKey(table xo.Table, fk xo.ForeignKey) string {
+	if f.driver == "sqlite3" || f.driver == "duckdb" {
+		return note("%s does not support adding foreign key %s", f.driver, fk.Name)
+	}
+	refTable := f.escType(fk.RefTable)
+	if fk.RefSchema != "" {
+		refTable = f.escType(fk.RefSchema) + "." + refTable
+	}
+	return fmt.Sprintf(
+		"ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);",
+		f.escType(table.Name), f.escType(fk.Name), f.fields(fk.Fields),
+		refTable, f.fields(fk.RefFields),
+	)
+}
+
+// dropForeignKey generates the statement dropping a foreign key.
+func (f *Funcs) dropForeignKey(table xo.Table, fk xo.ForeignKey) string {
+	tableName, name := f.escType(table.Name), f.escType(fk.Name)
+	switch f.driver {
+	case "sqlite3", "duckdb":
+		return note("%s does not support dropping foreign key %s", f.driver, fk.Name)
+	case "mysql":
+		return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", tableName, name)
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tableName, name)
+}
+
+// addCheck generates the statement adding a check constraint.
+func (f *Funcs) addCheck(table xo.Table, c xo.Check) string {
+	if f.driver == "sqlite3" || f.driver == "duckdb" {
+		return note("%s does not support adding check %s", f.driver, c.Name)
+	}
+	var constraint string
+	if c.Name != "" {
+		constraint = "CONSTRAINT " + f.escType(c.Name) + " "
+	}
+	return fmt.Sprintf(
+		"ALTER TABLE %s ADD %sCHECK (%s);",
+		f.escType(table.Name), constraint, checkExpr(c.Expr),
+	)
+}
+
+// dropCheck generates the statement dropping a check constraint.
+func (f *Funcs) dropCheck(table xo.Table, c xo.Check) string {
+	tableName, name := f.escType(table.Name), f.escType(c.Name)
+	switch {
+	case f.driver == "sqlite3" || f.driver == "duckdb":
+		return note("%s does not support dropping check %s", f.driver, c.Name)
+	case c.Name == "":
+		return note("cannot drop unnamed check (%s)", checkExpr(c.Expr))
+	case f.driver == "mysql":
+		return fmt.Sprintf("ALTER TABLE %s DROP CHECK %s;", tableName, name)
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tableName, name)
+}
+
+// renameTable generates a note for a dropped and created table that may have
+// been renamed.
+func (f *Funcs) renameTable(r TableRename) string {
+	stmt := fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", f.escType(r.From.Name), f.escType(r.To.Name))
+	if f.driver == "sqlserver" {
+		stmt = fmt.Sprintf("EXEC sp_rename '%s', '%s';", r.From.Name, r.To.Name)
+	}
+	return note("%s is dropped and %s is created with the same columns; if renamed, replace with: %s", r.From.Name, r.To.Name, stmt)
+}
+
+// renameColumn generates a note for a dropped and added column that may have
+// been renamed.
+func (f *Funcs) renameColumn(table xo.Table, cd ColumnDiff) string {
+	tableName := f.escType(table.Name)
+	stmt := fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", tableName, f.escCol(cd.From.Name), f.escCol(cd.To.Name))
+	if f.driver == "sqlserver" {
+		stmt = fmt.Sprintf("EXEC sp_rename '%s.%s', '%s', 'COLUMN';", table.Name, cd.From.Name, cd.To.Name)
+	}
+	return note("%s.%s is dropped and %s.%s is added with the same definition; if renamed, replace with: %s", table.Name, cd.From.Name, table.Name, cd.To.Name, stmt)
+}
+
+// dropView generates the statement dropping a view.
+func (f *Funcs) dropView(view xo.Table) string {
+	kind := "VIEW"
+	if view.Type == "materialized view" {
+		kind = "MATERIALIZED VIEW"
+	}
+	return fmt.Sprintf("DROP %s %s;", kind, f.escType(view.Name))
+}
@@ -0,0 +1,98 @@
+//go:build xotpl
+
+package createdb
+
+import (
+	"testing"
+
+	xo "github.com/xo/xo/types"
+)
+
+func TestDiffForeignKeys(t *testing.T) {
+	authorID := xo.Field{Name: "author_id", Type: xo.Type{Type: "integer"}}
+	books := func(refSchema string) xo.Schema {
+		return xo.Schema{
+			Driver: "postgres",
+			Name:   "public",
+			Tables: []xo.Table{{
+				Type:    "table",
+				Name:    "authors",
+				Columns: []xo.Field{authorID},
+			}, {
+				Type:    "table",
+				Name:    "books",
+				Columns: []xo.Field{authorID},
+				ForeignKeys: []xo.ForeignKey{{
+					Name:      "books_author_id_fkey",
+					Fields:    []xo.Field{authorID},
+					RefSchema: refSchema,
+					RefTable:  "authors",
+					RefFields: []xo.Field{authorID},
+				}},
+			}},
+		}
+	}
+	f := &Funcs{driver: "postgres"}
+	tests := []struct {
+		from, to string
+		exp      string
+	}{
+		{"", "", ""},
+		{"lib", "lib", ""},
+		{"", "lib", "ALTER TABLE books ADD CONSTRAINT books_author_id_fkey FOREIGN KEY (author_id) REFERENCES lib.authors (author_id);"},
+		{"lib", "", "ALTER TABLE books ADD CONSTRAINT books_author_id_fkey FOREIGN KEY (author_id) REFERENCES authors (author_id);"},
+	}
+	for i, test := range tests {
+		d := f.diffSchemas(books(test.from), books(test.to))
+		if test.exp == "" {
+			if len(d.AlterTables) != 0 {
+				t.Errorf("test %d expected no changes, got: %+v", i, d.AlterTables)
+			}
+			continue
+		}
+		if len(d.AlterTables) != 1 {
+			t.Fatalf("test %d expected 1 altered table, got: %d", i, len(d.AlterTables))
+		}
+		td := d.AlterTables[0]
+		if len(td.DropForeignKeys) != 1 || len(td.AddForeignKeys) != 1 {
+			t.Fatalf("test %d expected foreign key to be dropped and added, got: %+v", i, td)
+		}
+		if s := f.addForeignKey(td.Table, td.AddForeignKeys[0]); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestDiffViews(t *testing.T) {
+	view := func(name, def string) xo.Table {
+		return xo.Table{Type: "view", Name: name, Definition: def}
+	}
+	from := xo.Schema{Driver: "postgres", Views: []xo.Table{
+		view("a", "SELECT 1"),
+		view("b", "SELECT 2"),
+		view("c", "SELECT 3"),
+	}}
+	to := xo.Schema{Driver: "postgres", Views: []xo.Table{
+		view("a", "SELECT 1\n"),
+		view("b", "SELECT 22"),
+		view("d", "SELECT 4"),
+	}}
+	d := (&Funcs{driver: "postgres"}).diffSchemas(from, to)
+	if s, exp := viewNames(d.DropViews), "c,b"; s != exp {
+		t.Errorf("expected dropped views %q, got: %q", exp, s)
+	}
+	if s, exp := viewNames(d.CreateViews), "b,d"; s != exp {
+		t.Errorf("expected created views %q, got: %q", exp, s)
+	}
+}
+
+func viewNames(views []xo.Table) string {
+	var s string
+	for i, v := range views {
+		if i != 0 {
+			s += ","
+		}
+		s += v.Name
+	}
+	return s
+}
@@ -15,31 +15,26 @@ CREATE TYPE {{ esc $e.Name }} AS ENUM (
 );
 {{ end -}}
 {{- end -}}
-{{- if $s.Tables }}
-{{- range $t := $s.Tables }}
--- table {{ $t.Name }}
-CREATE TABLE {{ esc $t.Name }} (
-{{- range $i, $c := $t.Columns }}
-  {{ coldef $t $c }}{{ comma $i $t.Columns }}
-{{- end -}}
-{{- range $idx := $t.Indexes -}}{{- if isEndConstraint $idx }},
-  {{ constraint $idx.Name -}} {{ if $idx.IsPrimary }}PRIMARY KEY{{ else }}UNIQUE{{ end }} ({{ fields $idx.Fields }})
-{{- end -}}{{- end -}}
-{{- range $fk := $t.ForeignKeys -}}{{- if gt (len $fk.Fields) 1 }},
-  {{ constraint $fk.Name -}} FOREIGN KEY ({{ fields $fk.Fields }}) REFERENCES {{ esc $fk.RefTable }} ({{ fields $fk.RefFields }})
-{{- end -}}{{- end }}
-){{ engine }};
-{{- if $t.Indexes }}
-{{ range $idx := $t.Indexes }}{{ if not (or $idx.IsPrimary $idx.IsUnique) }}
--- index {{ $idx.Name }}
-CREATE INDEX {{ esc $idx.Name }} ON {{ esc $t.Name }} ({{ fields $idx.Fields }});
-{{ end -}}{{- end -}}{{- end }}
+{{- if and $s.Domains (driver "postgres") }}
+{{ range $d := $s.Domains }}
+-- domain {{ $d.Name }}
+{{ domaindef $d }};
 {{ end -}}
 {{- end -}}
+{{- if and $s.Composites (driver "postgres") }}
+{{ range $c := $s.Composites }}{{ template "composite" $c }}{{ end -}}
+{{- end -}}
+{{- if $s.Tables }}
+{{- range $t := $s.Tables }}{{ template "table" $t }}{{ end -}}
+{{- end -}}
 {{- if $s.Views }}
 {{- range $v := $s.Views }}
--- view {{ $v.Name }}
+-- {{ $v.Type }} {{ $v.Name }}
 {{ viewdef $v }};
+{{ range $idx := $v.Indexes }}
+-- index {{ $idx.Name }}
+{{ createIndex $v $idx }}
+{{ end -}}
 {{ end }}
 {{ end -}}
 {{- if $s.Procs }}
@@ -49,3 +44,150 @@ CREATE INDEX {{ esc $idx.Name }} ON {{ esc $t.Name }} ({{ fields $idx.Fields }})
 {{ end -}}
 {{ end -}}
 {{ end -}}
+
+{{ define "diff" -}}
+{{- $d := .Data -}}
+{{- range $r := $d.RenameTables }}
+{{ renameTable $r }}
+{{ end -}}
+{{- range $t := $d.AlterTables }}
+{{- range $c := $t.RenameColumns }}
+{{ renameColumn $t.Table $c }}
+{{ end -}}
+{{- end -}}
+{{- range $v := $d.DropViews }}
+-- drop {{ $v.Type }} {{ $v.Name }}
+{{ dropView $v }}
+{{ end -}}
+{{- range $t := $d.AlterTables }}
+{{- range $fk := $t.DropForeignKeys }}
+-- drop foreign key {{ $fk.Name }}
+{{ dropForeignKey $t.Table $fk }}
+{{ end -}}
+{{- range $c := $t.DropChecks }}
+-- drop check {{ $c.Name }}
+{{ dropCheck $t.Table $c }}
+{{ end -}}
+{{- range $idx := $t.DropIndexes }}
+-- drop index {{ $idx.Name }}
+{{ dropIndex $t.Table $idx }}
+{{ end -}}
+{{- end -}}
+{{- range $t := $d.DropTables }}
+-- drop {{ $t.Type }} {{ $t.Name }}
+DROP {{ if eq $t.Type "foreign table" }}FOREIGN {{ end }}TABLE {{ esc $t.Name }};
+{{ end -}}
+{{- if driver "postgres" -}}
+{{- range $e := $d.CreateEnums }}
+-- enum {{ $e.Name }}
+CREATE TYPE {{ esc $e.Name }} AS ENUM (
+{{- range $i, $v := $e.Values }}
+  {{ literal $v.Name }}{{ comma $i $e.Values }}
+{{- end }}
+);
+{{ end -}}
+{{- range $e := $d.AlterEnums }}
+-- alter enum {{ $e.Enum.Name }}
+{{- range $v := $e.AddValues }}
+ALTER TYPE {{ esc $e.Enum.Name }} ADD VALUE {{ literal $v.Name }};
+{{- end }}
+{{- range $v := $e.DropValues }}
+-- NOTE: cannot drop value {{ literal $v.Name }} from enum {{ $e.Enum.Name }}
+{{- end }}
+{{ end -}}
+{{- range $dom := $d.CreateDomains }}
+-- domain {{ $dom.Name }}
+{{ domaindef $dom }};
+{{ end -}}
+{{- range $c := $d.CreateComposites }}{{ template "composite" $c }}{{ end -}}
+{{- end -}}
+{{- range $t := $d.CreateTables }}{{ template "table" $t }}{{ end -}}
+{{- range $t := $d.AlterTables }}
+{{- range $c := $t.AddColumns }}
+-- add column {{ $t.Table.Name }}.{{ $c.Name }}
+{{ addColumn $t.Table $c }}
+{{ end -}}
+{{- range $c := $t.AlterColumns }}
+-- alter column {{ $t.Table.Name }}.{{ $c.To.Name }}
+{{ alterColumn $t.Table $c }}
+{{ end -}}
+{{- range $c := $t.DropColumns }}
+-- drop column {{ $t.Table.Name }}.{{ $c.Name }}
+{{ dropColumn $t.Table $c }}
+{{ end -}}
+{{- end -}}
+{{- range $t := $d.AlterTables }}
+{{- range $idx := $t.AddIndexes }}
+-- index {{ $idx.Name }}
+{{ createIndex $t.Table $idx }}
+{{ end -}}
+{{- range $fk := $t.AddForeignKeys }}
+-- foreign key {{ $fk.Name }}
+{{ addForeignKey $t.Table $fk }}
+{{ end -}}
+{{- range $c := $t.AddChecks }}
+-- check {{ $c.Name }}
+{{ addCheck $t.Table $c }}
+{{ end -}}
+{{- end -}}
+{{- range $v := $d.CreateViews }}
+-- {{ $v.Type }} {{ $v.Name }}
+{{ viewdef $v }};
+{{ range $idx := $v.Indexes }}
+-- index {{ $idx.Name }}
+{{ createIndex $v $idx }}
+{{ end -}}
+{{ end -}}
+{{- if driver "postgres" -}}
+{{- range $e := $d.DropEnums }}
+-- drop enum {{ $e.Name }}
+DROP TYPE {{ esc $e.Name }};
+{{ end -}}
+{{- range $c := $d.DropComposites }}
+-- drop composite type {{ $c.Name }}
+DROP TYPE {{ esc $c.Name }};
+{{ end -}}
+{{- range $dom := $d.DropDomains }}
+-- drop domain {{ $dom.Name }}
+DROP DOMAIN {{ esc $dom.Name }};
+{{ end -}}
+{{- end -}}
+{{ end -}}
+
+{{ define "composite" -}}
+{{- $c := . }}
+-- composite type {{ $c.Name }}
+CREATE TYPE {{ esc $c.Name }} AS (
+{{- range $i, $a := $c.Attributes }}
+  {{ attrdef $a }}{{ comma $i $c.Attributes }}
+{{- end }}
+);
+{{ end }}
+
+{{ define "table" -}}
+{{- $t := . }}
+{{- range $seq := sequences $t }}
+-- sequence {{ $seq }}
+CREATE SEQUENCE {{ $seq }};
+{{ end }}
+-- {{ $t.Type }} {{ $t.Name }}
+CREATE {{ if eq $t.Type "foreign table" }}FOREIGN {{ end }}TABLE {{ esc $t.Name }} (
+{{- range $i, $c := $t.Columns }}
+  {{ coldef $t $c }}{{ comma $i $t.Columns }}
+{{- end -}}
+{{- range $idx := $t.Indexes -}}{{- if isEndConstraint $idx }},
+  {{ constraint $idx.Name -}} {{ if $idx.IsPrimary }}PRIMARY KEY{{ else }}UNIQUE{{ end }} ({{ fields $idx.Fields }})
+{{- end -}}{{- end -}}
+{{- range $fk := $t.ForeignKeys -}}{{- if gt (len $fk.Fields) 1 }},
+  {{ constraint $fk.Name -}} FOREIGN KEY ({{ fields $fk.Fields }}) REFERENCES {{ with $fk.RefSchema }}{{ esc . }}.{{ end }}{{ esc $fk.RefTable }} ({{ fields $fk.RefFields }})
+{{- end -}}{{- end -}}
+{{- range $c := $t.Checks }},
+  {{ checkdef $c }}
+{{- end }}
+){{ tableopts $t }}{{ engine }};
+{{- if $t.Indexes }}
+{{ range $idx := $t.Indexes }}{{ if not (or $idx.IsPrimary (isEndConstraint $idx)) }}
+-- index {{ $idx.Name }}
+{{ createIndex $t $idx }}
+{{ end -}}{{- end -}}{{- end }}
+{{ end }}
@@ -62,6 +62,17 @@ func convLogger(logger interface{}) func(string, ...interface{}) {
 // DB is the common interface for database operations that can be used with
 // types from schema '{{ schema }}'.
 //
+{{ if pgx -}}
+// This works with [pgx.Conn], [pgx.Tx], and [pgxpool.Pool].
+type DB interface {
+	Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...interface{}) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...interface{}) pgx.Row
+{{- if copy }}
+	CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error)
+{{- end }}
+}
+{{- else -}}
 // This works with both [database/sql.DB] and [database/sql.Tx].
 type DB interface {
 {{ if context -}}
@@ -74,7 +85,403 @@ type DB interface {
 	QueryRow(string, ...interface{}) *sql.Row
 {{- end }}
 }
+{{- end }}
+
+{{ if driver "postgres" "mysql" "sqlite3" "duckdb" -}}
+// maxParams is the maximum number of query parameters, used to limit the
+// number of rows in a batch.
+const maxParams = {{ max_params }}
+
+// nthParam returns the 0-based nth query parameter placeholder.
+func nthParam(n int) string {
+	return {{ nth_param "n" }}
+}
+
+// batches splits count rows having n query parameters each into [start, end)
+// ranges that do not exceed maxParams.
+func batches(count, n int) ([][2]int, error) {
+	if maxParams < n {
+		return nil, ErrTooManyParams
+	}
+	size := maxParams / n
+	var b [][2]int
+	for i := 0; i < count; i += size {
+		end := i + size
+		if count < end {
+			end = count
+		}
+		b = append(b, [2]int{i, end})
+	}
+	return b, nil
+}
+
+// valuesList builds a VALUES list of count rows having n query parameters
+// each.
+func valuesList(count, n int) string {
+	var sb strings.Builder
+	for i := 0; i < count; i++ {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j := 0; j < n; j++ {
+			if j != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(nthParam(i*n + j))
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+{{ end -}}
+{{ if insert_defaults -}}
+// isZero returns true when v is nil or the zero value of its type.
+func isZero(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// insertSQL builds an INSERT query for the columns of a table, returning the
+// values of the returned columns.
+func insertSQL(table string, cols, returning []string) string {
+	vals := make([]string, len(cols))
+	for i := range cols {
+		vals[i] = {{ nth_param "i" }}
+	}
+{{- if driver "sqlserver" }}
+	output := " OUTPUT INSERTED." + strings.Join(returning, ", INSERTED.")
+	if len(cols) == 0 {
+		return "INSERT INTO " + table + output + " DEFAULT VALUES"
+	}
+	return "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ")" + output + " VALUES (" + strings.Join(vals, ", ") + ")"
+{{- else if driver "oracle" }}
+	into := make([]string, len(returning))
+	for i := range returning {
+		into[i] = {{ nth_param "len(cols)+i" }}
+	}
+	return "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + strings.Join(vals, ", ") + ")" +
+		" RETURNING " + strings.Join(returning, ", ") + " INTO " + strings.Join(into, ", ")
+{{- else }}
+	sqlstr := "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + strings.Join(vals, ", ") + ")"
+	if len(cols) == 0 {
+		sqlstr = "INSERT INTO " + table + {{ if driver "mysql" }}" () VALUES ()"{{ else }}" DEFAULT VALUES"{{ end }}
+	}
+	return sqlstr + " RETURNING " + strings.Join(returning, ", ")
+{{- end }}
+}
+
+{{ end -}}
+{{ if eq null_mode "pointer" -}}
+// deref returns the value v points to, or the zero value when v is nil.
+func deref[T any](v *T) T {
+	var z T
+	if v != nil {
+		z = *v
+	}
+	return z
+}
+
+{{ end -}}
+{{ if json -}}
+// JSON is a JSON encoded value of type T, for use with json columns.
+type JSON[T any] struct {
+	V T
+}
+
+// Value satisfies the [driver.Valuer] interface.
+func (j JSON[T]) Value() (driver.Value, error) {
+	buf, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// Scan satisfies the [sql.Scanner] interface.
+func (j *JSON[T]) Scan(v interface{}) error {
+	var buf []byte
+	switch x := v.(type) {
+	case nil:
+		*j = JSON[T]{}
+		return nil
+	case []byte:
+		buf = x
+	case string:
+		buf = []byte(x)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", v)
+	}
+	var z T
+	if err := json.Unmarshal(buf, &z); err != nil {
+		return err
+	}
+	j.V = z
+	return nil
+}
+
+// MarshalJSON satisfies the [json.Marshaler] interface.
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.V)
+}
+
+// UnmarshalJSON satisfies the [json.Unmarshaler] interface.
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	var z T
+	if err := json.Unmarshal(data, &z); err != nil {
+		return err
+	}
+	j.V = z
+	return nil
+}
+
+// NullJSON is a nullable JSON encoded value of type T, for use with nullable
+// json columns.
+type NullJSON[T any] struct {
+	V     T
+	Valid bool
+}
+
+// Value satisfies the [driver.Valuer] interface.
+func (n NullJSON[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return JSON[T]{V: n.V}.Value()
+}
+
+// Scan satisfies the [sql.Scanner] interface.
+func (n *NullJSON[T]) Scan(v interface{}) error {
+	var j JSON[T]
+	if err := j.Scan(v); err != nil {
+		return err
+	}
+	n.V, n.Valid = j.V, v != nil
+	return nil
+}
 
+// MarshalJSON satisfies the [json.Marshaler] interface.
+func (n NullJSON[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON satisfies the [json.Unmarshaler] interface.
+func (n *NullJSON[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullJSON[T]{}
+		return nil
+	}
+	var j JSON[T]
+	if err := j.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	n.V, n.Valid = j.V, true
+	return nil
+}
+
+{{ end -}}
+{{ if .Data.Composites -}}
+// scanComposite scans the composite (row) value v into the attributes dest.
+func scanComposite(v interface{}, dest ...interface{}) error {
+	var s string
+	switch x := v.(type) {
+	case []byte:
+		s = string(x)
+	case string:
+		s = x
+	default:
+		return fmt.Errorf("cannot scan %T into composite", v)
+	}
+	vals, err := parseComposite(s)
+	if err != nil {
+		return err
+	}
+	if len(vals) != len(dest) {
+		return fmt.Errorf("composite has %d attributes, expected %d", len(vals), len(dest))
+	}
+	for i, val := range vals {
+		if err := scanText(dest[i], val); err != nil {
+			return fmt.Errorf("composite attribute %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// parseComposite parses a composite value in the row literal format (for
+// example, '(1,"a b",)'), returning nil for null attributes.
+func parseComposite(s string) ([]*string, error) {
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, fmt.Errorf("invalid composite value %q", s)
+	}
+	var vals []*string
+	var sb strings.Builder
+	quoted, inQuotes := false, false
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s)-1:
+			sb.WriteByte(s[i+1])
+			i++
+		case inQuotes && c == '"' && s[i+1] == '"':
+			sb.WriteByte('"')
+			i++
+		case inQuotes && c == '"':
+			inQuotes = false
+		case inQuotes:
+			sb.WriteByte(c)
+		case c == '"':
+			quoted, inQuotes = true, true
+		case c == ',' || i == len(s)-1:
+			if str := sb.String(); quoted || str != "" {
+				vals = append(vals, &str)
+			} else {
+				vals = append(vals, nil)
+			}
+			sb.Reset()
+			quoted = false
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("invalid composite value %q", s)
+	}
+	return vals, nil
+}
+
+// scanText scans the text value of a composite attribute into dest. A nil s
+// is a null value.
+func scanText(dest interface{}, s *string) error {
+	if z, ok := dest.(sql.Scanner); ok {
+		var v interface{}
+		if s != nil {
+			v = *s
+		}
+		err := z.Scan(v)
+		if err != nil && s != nil {
+			// time types (ie, sql.NullTime) do not scan text values
+			if t, terr := parseTime(*s); terr == nil {
+				return z.Scan(t)
+			}
+		}
+		return err
+	}
+	rv := reflect.ValueOf(dest).Elem()
+	switch {
+	case s == nil:
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	case rv.Kind() == reflect.Ptr:
+		rv.Set(reflect.New(rv.Type().Elem()))
+		return scanText(rv.Interface(), s)
+	}
+	switch d := rv.Addr().Interface().(type) {
+	case *time.Time:
+		t, err := parseTime(*s)
+		if err != nil {
+			return err
+		}
+		*d = t
+		return nil
+	case *[]byte:
+		if !strings.HasPrefix(*s, `\x`) {
+			*d = []byte(*s)
+			return nil
+		}
+		buf, err := hex.DecodeString((*s)[2:])
+		if err != nil {
+			return err
+		}
+		*d = buf
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(*s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(*s)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(*s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(*s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(*s, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot scan text into %T", dest)
+	}
+	return nil
+}
+
+// parseTime parses the text value of a date or time.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05Z07:00:00",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05Z07",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"15:04:05Z07",
+		"15:04:05",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// compositeValue builds the composite (row) value of the attribute values.
+func compositeValue(vals ...interface{}) (driver.Value, error) {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for i, val := range vals {
+		if i != 0 {
+			sb.WriteByte(',')
+		}
+		v, err := driver.DefaultParameterConverter.ConvertValue(val)
+		if err != nil {
+			return nil, err
+		}
+		var s string
+		switch x := v.(type) {
+		case nil:
+			continue
+		case []byte:
+			s = `\x` + hex.EncodeToString(x)
+		case time.Time:
+			s = x.Format("2006-01-02 15:04:05.999999999Z07:00")
+		default:
+			s = fmt.Sprint(x)
+		}
+		sb.WriteString(`"` + compositeEscaper.Replace(s) + `"`)
+	}
+	sb.WriteByte(')')
+	return sb.String(), nil
+}
+
+// compositeEscaper escapes composite attribute values.
+var compositeEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+{{ end -}}
 // Error is an error.
 type Error string
 
@@ -91,6 +498,12 @@ const (
 	ErrDoesNotExist Error = "does not exist"
 	// ErrMarkedForDeletion is the marked for deletion error.
 	ErrMarkedForDeletion Error = "marked for deletion"
+{{- if driver "postgres" "mysql" "sqlite3" "duckdb" }}
+	// ErrTooManyParams is the too many query parameters error.
+	ErrTooManyParams Error = "too many query parameters"
+	// ErrRowCount is the unexpected number of returned rows error.
+	ErrRowCount Error = "unexpected number of returned rows"
+{{- end }}
 )
 
 // ErrInsertFailed is the insert failed error.
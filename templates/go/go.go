@@ -10,8 +10,10 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -129,6 +131,46 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 				Desc:       "array type mode (postgres only)",
 				Enums:      []string{"stdlib", "pq"},
 			},
+			{
+				ContextKey: DriverModeKey,
+				Type:       "string",
+				Desc:       "database driver mode (pgx is postgres only)",
+				Default:    "sql",
+				Enums:      []string{"sql", "pgx"},
+				Aliases:    []string{"go-driver"},
+			},
+			{
+				ContextKey: NullModeKey,
+				Type:       "string",
+				Desc:       "nullable type mode",
+				Default:    "sql",
+				Enums:      []string{"sql", "pointer", "generic"},
+			},
+			{
+				ContextKey: TypeKey,
+				Type:       "[]string",
+				Desc:       "type override (<db type>|<table>.<column>=<type>[;<nullable type>[;<zero>]])",
+			},
+			{
+				ContextKey: JSONKey,
+				Type:       "bool",
+				Desc:       "enable JSON[T] type for json columns",
+			},
+			{
+				ContextKey: CopyKey,
+				Type:       "bool",
+				Desc:       "enable COPY FROM for batch inserts (pgx only)",
+			},
+			{
+				ContextKey: InsertDefaultsKey,
+				Type:       "bool",
+				Desc:       "omit zero values of columns with defaults on insert, returning the inserted values",
+			},
+			{
+				ContextKey: DomainTypesKey,
+				Type:       "bool",
+				Desc:       "generate named types for domains instead of using their base types",
+			},
 			{
 				ContextKey: PkgKey,
 				Type:       "string",
@@ -224,7 +266,17 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 			return funcs, nil
 		},
 		NewContext: func(ctx context.Context, _ string) context.Context {
-			ctx = context.WithValue(ctx, KnownTypesKey, knownTypes)
+			// override types are known, so that their zero values are used
+			known := make(map[string]bool, len(knownTypes))
+			for k, v := range knownTypes {
+				known[k] = v
+			}
+			overrides, _ := TypeOverrides(ctx)
+			for _, o := range overrides {
+				known[o.Type] = true
+				known[o.Nullable] = true
+			}
+			ctx = context.WithValue(ctx, KnownTypesKey, known)
 			ctx = context.WithValue(ctx, ShortsKey, shorts)
 			return ctx
 		},
@@ -234,11 +286,20 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 			case "query":
 				return append(base, "typedef", "query")
 			case "schema":
-				return append(base, "enum", "proc", "typedef", "query", "index", "foreignkey")
+				return append(base, "enum", "domain", "composite", "proc", "typedef", "query", "index", "foreignkey", "reverse_foreignkey", "refresh")
 			}
 			return nil
 		},
 		Pre: func(ctx context.Context, mode string, set *xo.Set, out fs.FS, emit func(xo.Template)) error {
+			if driver, _, _ := xo.DriverDbSchema(ctx); DriverMode(ctx) == "pgx" && driver != "postgres" {
+				return fmt.Errorf("driver mode pgx is not supported for %s", driver)
+			}
+			if Copy(ctx) && DriverMode(ctx) != "pgx" {
+				return errors.New("copy requires driver mode pgx")
+			}
+			if _, err := TypeOverrides(ctx); err != nil {
+				return err
+			}
 			if err := addInitialisms(ctx); err != nil {
 				return err
 			}
@@ -246,12 +307,23 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 			if err != nil {
 				return err
 			}
+			annotated, err := annotationImports(ctx, set)
+			if err != nil {
+				return err
+			}
 			// If -2 is provided, skip package template outputs as requested.
 			// If -a is provided, skip to avoid duplicating the template.
 			if !NotFirst(ctx) && !Append(ctx) {
+				var composites bool
+				for _, schema := range set.Schemas {
+					composites = composites || len(schema.Composites) != 0
+				}
 				emit(xo.Template{
 					Partial: "db",
 					Dest:    "db.xo.go",
+					Data: DB{
+						Composites: composites,
+					},
 				})
 				// If --single is provided, don't generate header for db.xo.go.
 				if xo.Single(ctx) == "" {
@@ -284,6 +356,7 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 			for filename := range files {
 				emit(xo.Template{
 					Partial: "header",
+					Data:    annotated,
 					Dest:    filename,
 				})
 			}
@@ -291,12 +364,15 @@ func Init(ctx context.Context, f func(xo.TemplateType)) error {
 		},
 		Process: func(ctx context.Context, mode string, set *xo.Set, emit func(xo.Template)) error {
 			if mode == "query" {
+				types := make(map[string]Table)
 				for _, query := range set.Queries {
-					if err := emitQuery(ctx, query, emit); err != nil {
+					if err := emitQuery(ctx, query, types, emit); err != nil {
 						return err
 					}
 				}
 			} else {
+				ctx = context.WithValue(ctx, DomainsKey, domainMap(ctx, set.Schemas))
+				ctx = context.WithValue(ctx, TablesKey, tableMap(ctx, set.Schemas))
 				for _, schema := range set.Schemas {
 					if err := emitSchema(ctx, schema, emit); err != nil {
 						return err
@@ -347,11 +423,20 @@ func fileNames(ctx context.Context, mode string, set *xo.Set) (map[string]bool,
 	switch mode {
 	case "schema":
 		for _, schema := range set.Schemas {
+			prefix := schemaPrefix(ctx, schema.Name)
 			for _, e := range schema.Enums {
-				addFile(camelExport(e.Name))
+				addFile(camelExport(prefix + e.Name))
+			}
+			if DomainTypes(ctx) {
+				for _, d := range schema.Domains {
+					addFile(camelExport(prefix + d.Name))
+				}
+			}
+			for _, c := range schema.Composites {
+				addFile(camelExport(prefix + c.Name))
 			}
 			for _, p := range schema.Procs {
-				goName := camelExport(p.Name)
+				goName := camelExport(prefix + p.Name)
 				if p.Type == "function" {
 					addFile("sf_" + goName)
 				} else {
@@ -359,19 +444,24 @@ func fileNames(ctx context.Context, mode string, set *xo.Set) (map[string]bool,
 				}
 			}
 			for _, t := range schema.Tables {
-				addFile(camelExport(singularize(t.Name)))
+				addFile(tableGoName(prefix, t))
 			}
 			for _, v := range schema.Views {
-				addFile(camelExport(singularize(v.Name)))
+				addFile(tableGoName(prefix, v))
 			}
 		}
 	case "query":
 		for _, query := range set.Queries {
-			addFile(query.Type)
-			if query.Exec {
+			switch {
+			case query.File != "":
+				// Queries loaded from a query file are grouped by file.
+				addFile(query.File)
+			case query.Exec:
 				// Single mode is handled at the start of the function but it
 				// must be used for Exec queries.
 				return nil, ErrNoSingle
+			default:
+				addFile(query.Type)
 			}
 		}
 	default:
@@ -380,8 +470,9 @@ func fileNames(ctx context.Context, mode string, set *xo.Set) (map[string]bool,
 	return files, nil
 }
 
-// emitQuery emits the query.
-func emitQuery(ctx context.Context, query xo.Query, emit func(xo.Template)) error {
+// emitQuery emits the query. Types shared by multiple queries are only emitted
+// once, and must have the same fields.
+func emitQuery(ctx context.Context, query xo.Query, types map[string]Table, emit func(xo.Template)) error {
 	var table Table
 	// build type if needed
 	if !query.Exec {
@@ -390,11 +481,22 @@ func emitQuery(ctx context.Context, query xo.Query, emit func(xo.Template)) erro
 			return err
 		}
 	}
+	// determine dest
+	dest := strings.ToLower(table.GoName) + ext
+	if query.File != "" {
+		dest = strings.ToLower(query.File) + ext
+	}
+	// check previously emitted type
+	prev, emitted := types[table.GoName]
+	if emitted && !query.Flat && !sameFields(prev.Fields, table.Fields) {
+		return fmt.Errorf("query %s: type %s redefined with different fields", query.Name, table.GoName)
+	}
 	// emit type definition
-	if !query.Exec && !query.Flat && !Append(ctx) {
+	if !query.Exec && !query.Flat && !Append(ctx) && !emitted {
+		types[table.GoName] = table
 		emit(xo.Template{
 			Partial:  "typedef",
-			Dest:     strings.ToLower(table.GoName) + ext,
+			Dest:     dest,
 			SortType: query.Type,
 			SortName: query.Name,
 			Data:     table,
@@ -403,9 +505,17 @@ func emitQuery(ctx context.Context, query xo.Query, emit func(xo.Template)) erro
 	// build query params
 	var params []QueryParam
 	for _, param := range query.Params {
+		typ := param.Type.Type
+		// map inferred database types, the same as columns
+		if param.Inferred {
+			var err error
+			if typ, _, err = goType(ctx, param.Type); err != nil {
+				return err
+			}
+		}
 		params = append(params, QueryParam{
 			Name:        param.Name,
-			Type:        param.Type.Type,
+			Type:        typ,
 			Interpolate: param.Interpolate,
 			Join:        param.Join,
 		})
@@ -413,7 +523,7 @@ func emitQuery(ctx context.Context, query xo.Query, emit func(xo.Template)) erro
 	// emit query
 	emit(xo.Template{
 		Partial:  "query",
-		Dest:     strings.ToLower(table.GoName) + ext,
+		Dest:     dest,
 		SortType: query.Type,
 		SortName: query.Name,
 		Data: Query{
@@ -432,6 +542,19 @@ func emitQuery(ctx context.Context, query xo.Query, emit func(xo.Template)) erro
 	return nil
 }
 
+// sameFields returns whether a and b have the same field names and types.
+func sameFields(a, b []Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].GoName != b[i].GoName || a[i].Type != b[i].Type {
+			return false
+		}
+	}
+	return true
+}
+
 func buildQueryType(ctx context.Context, query xo.Query) (Table, error) {
 	tf := camelExport
 	if query.Flat {
@@ -439,7 +562,7 @@ func buildQueryType(ctx context.Context, query xo.Query) (Table, error) {
 	}
 	var fields []Field
 	for _, z := range query.Fields {
-		f, err := convertField(ctx, tf, z)
+		f, err := convertField(ctx, tf, "", z)
 		if err != nil {
 			return Table{}, err
 		}
@@ -488,7 +611,7 @@ func buildQueryName(query xo.Query) string {
 func emitSchema(ctx context.Context, schema xo.Schema, emit func(xo.Template)) error {
 	// emit enums
 	for _, e := range schema.Enums {
-		enum := convertEnum(e)
+		enum := convertEnum(ctx, schema.Name, e)
 		emit(xo.Template{
 			Partial:  "enum",
 			Dest:     strings.ToLower(enum.GoName) + ext,
@@ -496,6 +619,34 @@ func emitSchema(ctx context.Context, schema xo.Schema, emit func(xo.Template)) e
 			Data:     enum,
 		})
 	}
+	// emit named domain types
+	if DomainTypes(ctx) {
+		for _, d := range schema.Domains {
+			domain, err := convertDomain(ctx, schema.Name, d)
+			if err != nil {
+				return err
+			}
+			emit(xo.Template{
+				Partial:  "domain",
+				Dest:     strings.ToLower(domain.GoName) + ext,
+				SortName: domain.GoName,
+				Data:     domain,
+			})
+		}
+	}
+	// emit composite types
+	for _, c := range schema.Composites {
+		composite, err := convertComposite(ctx, schema.Name, c)
+		if err != nil {
+			return err
+		}
+		emit(xo.Template{
+			Partial:  "composite",
+			Dest:     strings.ToLower(composite.GoName) + ext,
+			SortName: composite.GoName,
+			Data:     composite,
+		})
+	}
 	// build procs
 	overloadMap := make(map[string][]Proc)
 	// procOrder ensures procs are always emitted in alphabetic order for
@@ -503,7 +654,7 @@ func emitSchema(ctx context.Context, schema xo.Schema, emit func(xo.Template)) e
 	var procOrder []string
 	for _, p := range schema.Procs {
 		var err error
-		if procOrder, err = convertProc(ctx, overloadMap, procOrder, p); err != nil {
+		if procOrder, err = convertProc(ctx, overloadMap, procOrder, schema.Name, p); err != nil {
 			return err
 		}
 	}
@@ -527,7 +678,7 @@ func emitSchema(ctx context.Context, schema xo.Schema, emit func(xo.Template)) e
 	}
 	// emit tables
 	for _, t := range append(schema.Tables, schema.Views...) {
-		table, err := convertTable(ctx, t)
+		table, err := convertTable(ctx, schema.Name, t)
 		if err != nil {
 			return err
 		}
@@ -540,6 +691,10 @@ func emitSchema(ctx context.Context, schema xo.Schema, emit func(xo.Template)) e
 		})
 		// emit indexes
 		for _, i := range t.Indexes {
+			if err := checkIndexParams(i); err != nil {
+				fmt.Fprintf(os.Stderr, "WARNING: skipping table %q index %q: %v\n", t.Name, i.Name, err)
+				continue
+			}
 			index, err := convertIndex(ctx, table, i)
 			if err != nil {
 				return err
@@ -565,15 +720,56 @@ func emitSchema(ctx context.Context, schema xo.Schema, emit func(xo.Template)) e
 				SortName: fkey.SQLName,
 				Data:     fkey,
 			})
+			// emit the reverse on the ref table, in the ref table's file
+			refSchema := fk.RefSchema
+			if refSchema == "" {
+				refSchema = schema.Name
+			}
+			ref, ok := Tables(ctx)[refSchema+"."+fk.RefTable]
+			if !ok {
+				fmt.Fprintf(os.Stderr, "WARNING: skipping table %q foreign key %q reverse func (table %q not generated)\n", t.Name, fk.Name, fk.RefTable)
+				continue
+			}
+			rev := convertReverseFKey(ctx, t, fk, fkey, ref)
+			emit(xo.Template{
+				Dest:     strings.ToLower(ref.GoName) + ext,
+				Partial:  "reverse_foreignkey",
+				SortType: table.Type,
+				SortName: rev.GoName,
+				Data:     rev,
+			})
+		}
+		// emit materialized view refresh
+		if t.Type == "materialized view" {
+			emit(xo.Template{
+				Dest:     strings.ToLower(table.GoName) + ext,
+				Partial:  "refresh",
+				SortType: table.Type,
+				SortName: table.GoName,
+				Data: Refresh{
+					GoName: "Refresh" + table.GoName,
+					Table:  table,
+				},
+			})
 		}
 	}
 	return nil
 }
 
+// schemaPrefix returns the Go name prefix for objects in the schema. When
+// generating multiple schemas, names in schemas other than the first are
+// prefixed with the schema name.
+func schemaPrefix(ctx context.Context, schema string) string {
+	if _, _, first := xo.DriverDbSchema(ctx); schema == "" || schema == first {
+		return ""
+	}
+	return schema + "_"
+}
+
 // convertEnum converts a xo.Enum.
-func convertEnum(e xo.Enum) Enum {
+func convertEnum(ctx context.Context, schema string, e xo.Enum) Enum {
 	var vals []EnumValue
-	goName := camelExport(e.Name)
+	goName := camelExport(schemaPrefix(ctx, schema) + e.Name)
 	for _, v := range e.Values {
 		name := camelExport(strings.ToLower(v.Name))
 		if strings.HasSuffix(name, goName) && goName != name {
@@ -588,24 +784,123 @@ func convertEnum(e xo.Enum) Enum {
 	return Enum{
 		GoName:  goName,
 		SQLName: e.Name,
+		Schema:  schema,
 		Values:  vals,
 	}
 }
 
+// domainMap returns the domains of the schemas, keyed by their schema
+// qualified name. Domains in the first schema are also keyed by their name.
+func domainMap(ctx context.Context, schemas []xo.Schema) map[string]*Domain {
+	_, _, first := xo.DriverDbSchema(ctx)
+	m := make(map[string]*Domain)
+	for _, schema := range schemas {
+		for _, d := range schema.Domains {
+			domain := &Domain{
+				GoName:  camelExport(schemaPrefix(ctx, schema.Name) + d.Name),
+				SQLName: d.Name,
+				Schema:  schema.Name,
+				Base:    d.Type,
+				Comment: d.Comment,
+			}
+			m[schema.Name+"."+d.Name] = domain
+			if schema.Name == first {
+				m[d.Name] = domain
+			}
+		}
+	}
+	return m
+}
+
+// tableMap returns the tables of the schemas, keyed by their schema qualified
+// name.
+func tableMap(ctx context.Context, schemas []xo.Schema) map[string]Table {
+	m := make(map[string]Table)
+	for _, schema := range schemas {
+		prefix := schemaPrefix(ctx, schema.Name)
+		for _, t := range schema.Tables {
+			m[schema.Name+"."+t.Name] = Table{
+				Type:    t.Type,
+				GoName:  tableGoName(prefix, t),
+				SQLName: t.Name,
+				Schema:  schema.Name,
+			}
+		}
+	}
+	return m
+}
+
+// convertDomain converts a xo.Domain to a named domain type.
+func convertDomain(ctx context.Context, schema string, d xo.Domain) (Domain, error) {
+	base := d.Type
+	base.Nullable = false
+	typ, _, err := goType(ctx, base)
+	if err != nil {
+		return Domain{}, err
+	}
+	return Domain{
+		GoName:  camelExport(schemaPrefix(ctx, schema) + d.Name),
+		SQLName: d.Name,
+		Schema:  schema,
+		Type:    typ,
+		Alias:   !basicTypes[typ],
+		Base:    d.Type,
+		Comment: d.Comment,
+	}, nil
+}
+
+// basicTypes are the Go types that named domain types are defined as, instead
+// of being an alias.
+var basicTypes = map[string]bool{
+	"bool":    true,
+	"string":  true,
+	"[]byte":  true,
+	"int":     true,
+	"int16":   true,
+	"int32":   true,
+	"int64":   true,
+	"uint":    true,
+	"uint8":   true,
+	"uint16":  true,
+	"uint32":  true,
+	"uint64":  true,
+	"float32": true,
+	"float64": true,
+}
+
+// convertComposite converts a xo.Composite.
+func convertComposite(ctx context.Context, schema string, c xo.Composite) (Composite, error) {
+	var fields []Field
+	for _, z := range c.Attributes {
+		f, err := convertField(ctx, camelExport, c.Name, z)
+		if err != nil {
+			return Composite{}, err
+		}
+		fields = append(fields, f)
+	}
+	return Composite{
+		GoName:  camelExport(schemaPrefix(ctx, schema) + c.Name),
+		SQLName: c.Name,
+		Schema:  schema,
+		Fields:  fields,
+		Comment: c.Comment,
+	}, nil
+}
+
 // convertProc converts a xo.Proc.
-func convertProc(ctx context.Context, overloadMap map[string][]Proc, order []string, p xo.Proc) ([]string, error) {
-	_, _, schema := xo.DriverDbSchema(ctx)
+func convertProc(ctx context.Context, overloadMap map[string][]Proc, order []string, schema string, p xo.Proc) ([]string, error) {
 	proc := Proc{
 		Type:      p.Type,
-		GoName:    camelExport(p.Name),
+		GoName:    camelExport(schemaPrefix(ctx, schema) + p.Name),
 		SQLName:   p.Name,
+		Schema:    schema,
 		Signature: fmt.Sprintf("%s.%s", schema, p.Name),
 		Void:      p.Void,
 	}
 	// proc params
 	var types []string
 	for _, z := range p.Params {
-		f, err := convertField(ctx, camel, z)
+		f, err := convertField(ctx, camel, "", z)
 		if err != nil {
 			return nil, err
 		}
@@ -618,7 +913,7 @@ func convertProc(ctx context.Context, overloadMap map[string][]Proc, order []str
 	types = nil
 	// proc return
 	for _, z := range p.Returns {
-		f, err := convertField(ctx, camel, z)
+		f, err := convertField(ctx, camel, "", z)
 		if err != nil {
 			return nil, err
 		}
@@ -642,33 +937,66 @@ func convertProc(ctx context.Context, overloadMap map[string][]Proc, order []str
 	return order, nil
 }
 
-// convertTable converts a xo.Table to a Table.
-func convertTable(ctx context.Context, t xo.Table) (Table, error) {
+// convertTable converts a xo.Table of the schema to a Table.
+func convertTable(ctx context.Context, schema string, t xo.Table) (Table, error) {
 	var cols, pkCols []Field
 	for _, z := range t.Columns {
-		f, err := convertField(ctx, camelExport, z)
+		f, err := convertField(ctx, camelExport, t.Name, z)
 		if err != nil {
 			return Table{}, err
 		}
 		cols = append(cols, f)
-		if z.IsPrimary {
+		// foreign tables are read-only
+		if z.IsPrimary && t.Type != "foreign table" {
 			pkCols = append(pkCols, f)
 		}
 	}
+	// use the table comment, falling back to the view definition
+	comment := t.Comment
+	if comment == "" && strings.HasSuffix(t.Type, "view") {
+		comment = t.Definition
+	}
 	return Table{
-		GoName:      camelExport(singularize(t.Name)),
+		Type:        t.Type,
+		GoName:      tableGoName(schemaPrefix(ctx, schema), t),
 		SQLName:     t.Name,
+		Schema:      schema,
 		Fields:      cols,
 		PrimaryKeys: pkCols,
 		Manual:      t.Manual,
-		Comment:     t.Definition,
+		Comment:     comment,
 	}, nil
 }
 
+// tableGoName returns the Go name for a table, using the table's xo:name
+// annotation when present.
+func tableGoName(prefix string, t xo.Table) string {
+	if name := t.Annotations["name"]; name != "" {
+		return camelExport(prefix + name)
+	}
+	return camelExport(prefix + singularize(t.Name))
+}
+
+// checkIndexParams returns an error when the index's fields can not be used
+// as func params.
+func checkIndexParams(i xo.Index) error {
+	names := make(map[string]bool)
+	for j, f := range i.Fields {
+		switch {
+		case f.Name == "":
+			return fmt.Errorf("expression %q can not be passed as a param", i.Exprs[j])
+		case names[f.Name]:
+			return fmt.Errorf("column %q used more than once", f.Name)
+		}
+		names[f.Name] = true
+	}
+	return nil
+}
+
 func convertIndex(ctx context.Context, t Table, i xo.Index) (Index, error) {
 	var fields []Field
 	for _, z := range i.Fields {
-		f, err := convertField(ctx, camelExport, z)
+		f, err := convertField(ctx, camelExport, t.SQLName, z)
 		if err != nil {
 			return Index{}, err
 		}
@@ -676,9 +1004,11 @@ func convertIndex(ctx context.Context, t Table, i xo.Index) (Index, error) {
 	}
 	return Index{
 		SQLName:   i.Name,
-		Func:      camelExport(i.Func),
+		Func:      camelExport(schemaPrefix(ctx, t.Schema) + i.Func),
 		Table:     t,
 		Fields:    fields,
+		Exprs:     i.Exprs,
+		Predicate: i.Predicate,
 		IsUnique:  i.IsUnique,
 		IsPrimary: i.IsPrimary,
 	}, nil
@@ -688,7 +1018,7 @@ func convertFKey(ctx context.Context, t Table, fk xo.ForeignKey) (ForeignKey, er
 	var fields, refFields []Field
 	// convert fields
 	for _, f := range fk.Fields {
-		field, err := convertField(ctx, camelExport, f)
+		field, err := convertField(ctx, camelExport, t.SQLName, f)
 		if err != nil {
 			return ForeignKey{}, err
 		}
@@ -696,23 +1026,92 @@ func convertFKey(ctx context.Context, t Table, fk xo.ForeignKey) (ForeignKey, er
 	}
 	// convert ref fields
 	for _, f := range fk.RefFields {
-		refField, err := convertField(ctx, camelExport, f)
+		refField, err := convertField(ctx, camelExport, fk.RefTable, f)
 		if err != nil {
 			return ForeignKey{}, err
 		}
 		refFields = append(refFields, refField)
 	}
+	// ref table in the same schema when not otherwise specified
+	refSchema := fk.RefSchema
+	if refSchema == "" {
+		refSchema = t.Schema
+	}
+	prefix := schemaPrefix(ctx, refSchema)
+	refTable := camelExport(prefix + singularize(fk.RefTable))
+	if fk.RefName != "" {
+		refTable = camelExport(prefix + fk.RefName)
+	}
 	return ForeignKey{
 		GoName:    camelExport(fk.Func),
 		SQLName:   fk.Name,
 		Table:     t,
 		Fields:    fields,
-		RefTable:  camelExport(singularize(fk.RefTable)),
+		RefTable:  refTable,
 		RefFields: refFields,
-		RefFunc:   camelExport(fk.RefFunc),
+		RefFunc:   camelExport(prefix + fk.RefFunc),
 	}, nil
 }
 
+// convertReverseFKey converts the foreign key of table t into the reverse
+// foreign key on the ref table. Uses the table's index on the foreign key's
+// fields, when there is one, and the ref fields' values can be passed to its
+// func.
+func convertReverseFKey(ctx context.Context, t xo.Table, fk xo.ForeignKey, fkey ForeignKey, ref Table) ReverseForeignKey {
+	index := Index{
+		SQLName: fkey.SQLName,
+		Table:   fkey.Table,
+		Fields:  fkey.Fields,
+	}
+	refFields := fkey.RefFields
+	for _, i := range t.Indexes {
+		if i.IsUnique || i.Predicate != "" || len(i.Fields) != len(fk.Fields) || checkIndexParams(i) != nil {
+			continue
+		}
+		// map index fields to the foreign key's fields
+		var fields, refs []Field
+		for j, z := range i.Fields {
+			if j < len(i.Exprs) && i.Exprs[j] != "" {
+				break
+			}
+			for k, y := range fk.Fields {
+				if y.Name == z.Name {
+					fields, refs = append(fields, fkey.Fields[k]), append(refs, fkey.RefFields[k])
+				}
+			}
+		}
+		if len(fields) != len(fk.Fields) || !convertibleFields(fields, refs) {
+			continue
+		}
+		index = Index{
+			SQLName: i.Name,
+			Func:    camelExport(schemaPrefix(ctx, fkey.Table.Schema) + i.Func),
+			Table:   fkey.Table,
+			Fields:  fields,
+		}
+		refFields = refs
+		break
+	}
+	return ReverseForeignKey{
+		GoName:    camelExport(schemaPrefix(ctx, fkey.Table.Schema) + fk.RevFunc),
+		SQLName:   fkey.SQLName,
+		RefTable:  ref,
+		RefFields: refFields,
+		Index:     index,
+	}
+}
+
+// convertibleFields returns whether the values of the ref fields can be
+// converted to the types of fields.
+func convertibleFields(fields, refFields []Field) bool {
+	for i, field := range fields {
+		if _, ok := wrapValue("", field.Type, refFields[i].Type); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func overloadedName(sqlTypes []string, proc Proc) string {
 	if len(proc.Params) == 0 {
 		return proc.GoName
@@ -734,23 +1133,72 @@ func overloadedName(sqlTypes []string, proc Proc) string {
 	return fmt.Sprintf("%sBy%sAnd%s", proc.GoName, front, last)
 }
 
-func convertField(ctx context.Context, tf transformFunc, f xo.Field) (Field, error) {
+// convertField converts a xo.Field of the table (if any), applying the
+// column's xo:type annotation or any type overrides for the table column or
+// database type.
+func convertField(ctx context.Context, tf transformFunc, table string, f xo.Field) (Field, error) {
 	typ, zero, err := goType(ctx, f.Type)
 	if err != nil {
 		return Field{}, err
 	}
+	overrides, err := TypeOverrides(ctx)
+	if err != nil {
+		return Field{}, err
+	}
+	dbType := f.Type.Type
+	if f.Type.IsArray {
+		dbType += "[]"
+	}
+	var o TypeOverride
+	var ok bool
+	if s := f.Annotations["type"]; s != "" {
+		if o, err = parseTypeOverride(ctx, s); err != nil {
+			return Field{}, fmt.Errorf("column %q: invalid xo:type annotation %q", f.Name, s)
+		}
+		ok = true
+	}
+	if !ok {
+		o, ok = overrides[table+"."+f.Name]
+	}
+	if !ok {
+		o, ok = overrides[dbType]
+	}
+	switch {
+	case ok && f.Type.Nullable:
+		typ, zero = o.Nullable, o.NullableZero
+	case ok:
+		typ, zero = o.Type, o.Zero
+	}
+	// wrap json columns with the JSON type, using the type annotated in the
+	// column comment (xo:type=<type>) or the override as the value type
+	if driver, _, _ := xo.DriverDbSchema(ctx); JSON(ctx) && !f.Type.IsArray && jsonTypes[driver][f.Type.Type] {
+		elem := "json.RawMessage"
+		if ok {
+			elem = o.Type
+		}
+		typ, zero = jsonType(ctx, elem, f.Type.Nullable)
+	}
+	goName := tf(f.Name)
+	if name := f.Annotations["name"]; name != "" {
+		goName = tf(name)
+	}
 	return Field{
-		Type:       typ,
-		GoName:     tf(f.Name),
-		SQLName:    f.Name,
-		Zero:       zero,
-		IsPrimary:  f.IsPrimary,
-		IsSequence: f.IsSequence,
-		Comment:    f.Comment,
+		Type:        typ,
+		GoName:      goName,
+		SQLName:     f.Name,
+		Zero:        zero,
+		IsPrimary:   f.IsPrimary,
+		Default:     f.Default,
+		IsSequence:  f.IsSequence,
+		IsGenerated: f.IsGenerated,
+		Comment:     f.Comment,
 	}, nil
 }
 
 func goType(ctx context.Context, typ xo.Type) (string, string, error) {
+	if d := Domains(ctx)[typ.Type]; d != nil {
+		return domainGoType(ctx, d, typ)
+	}
 	driver, _, schema := xo.DriverDbSchema(ctx)
 	var f func(xo.Type, string, string, string) (string, string, error)
 	switch driver {
@@ -759,22 +1207,121 @@ func goType(ctx context.Context, typ xo.Type) (string, string, error) {
 	case "oracle":
 		f = loader.OracleGoType
 	case "postgres":
-		switch mode := ArrayMode(ctx); mode {
-		case "stdlib":
-			f = loader.StdlibPostgresGoType
-		case "pq", "":
-			f = loader.PQPostgresGoType
-		default:
-			return "", "", fmt.Errorf("unknown array mode: %q", mode)
+		if DriverMode(ctx) == "pgx" {
+			f = loader.PgxPostgresGoType
+		} else {
+			switch mode := ArrayMode(ctx); mode {
+			case "stdlib":
+				f = loader.StdlibPostgresGoType
+			case "pq", "":
+				f = loader.PQPostgresGoType
+			default:
+				return "", "", fmt.Errorf("unknown array mode: %q", mode)
+			}
 		}
 	case "sqlite3":
 		f = loader.Sqlite3GoType
+	case "duckdb":
+		f = loader.DuckdbGoType
 	case "sqlserver":
 		f = loader.SqlserverGoType
-	default:
+	}
+	// a loader may provide its own mapping (ie, one interpreted from
+	// --loader-src), which falls through to the driver's mapping above for
+	// the types it returns an empty Go type for
+	if custom := loader.GoType(ctx); custom != nil {
+		f = loaderGoType(custom, f)
+	}
+	if f == nil {
 		return "", "", fmt.Errorf("unknown driver %q", driver)
 	}
-	return f(typ, schema, Int32(ctx), Uint32(ctx))
+	goType, zero, err := f(typ, schema, Int32(ctx), Uint32(ctx))
+	mode := NullMode(ctx)
+	if err != nil || !typ.Nullable || mode == "sql" || mode == "" {
+		return goType, zero, err
+	}
+	// replace nullable wrapper types (sql.NullString, uuid.NullUUID, ...)
+	// with the non-nullable type, leaving types that are already nilable as-is
+	typ.Nullable = false
+	base, _, err := f(typ, schema, Int32(ctx), Uint32(ctx))
+	if err != nil {
+		return "", "", err
+	}
+	if base == goType || strings.HasPrefix(goType, "*") {
+		return goType, zero, nil
+	}
+	if mode == "pointer" {
+		return "*" + base, "nil", nil
+	}
+	return "sql.Null[" + base + "]", "sql.Null[" + base + "]{}", nil
+}
+
+// loaderGoType wraps a loader's Go type mapping, falling through to the
+// driver's mapping when the loader returns an empty Go type.
+func loaderGoType(custom, f func(xo.Type, string, string, string) (string, string, error)) func(xo.Type, string, string, string) (string, string, error) {
+	return func(typ xo.Type, schema, itype, utype string) (string, string, error) {
+		goType, zero, err := custom(typ, schema, itype, utype)
+		switch {
+		case err != nil:
+			return "", "", err
+		case goType != "":
+			return goType, zero, nil
+		case f == nil:
+			return "", "", fmt.Errorf("unsupported type %q", typ.Type)
+		}
+		return f(typ, schema, itype, utype)
+	}
+}
+
+// domainGoType returns the Go type and zero value for a column of the domain
+// type. Domains resolve to their base type, unless named domain types are
+// being generated.
+func domainGoType(ctx context.Context, d *Domain, typ xo.Type) (string, string, error) {
+	base := d.Base
+	base.Nullable = base.Nullable && typ.Nullable
+	if !DomainTypes(ctx) || typ.IsArray {
+		base.IsArray = base.IsArray || typ.IsArray
+		return goType(ctx, base)
+	}
+	nullable := base.Nullable
+	base.Nullable = false
+	_, zero, err := goType(ctx, base)
+	switch mode := NullMode(ctx); {
+	case err != nil:
+		return "", "", err
+	case !nullable:
+		return d.GoName, zero, nil
+	case mode == "pointer":
+		return "*" + d.GoName, "nil", nil
+	case mode == "generic":
+		return "sql.Null[" + d.GoName + "]", "sql.Null[" + d.GoName + "]{}", nil
+	}
+	// nullable named domains use the base sql.Null* type
+	base.Nullable = true
+	return goType(ctx, base)
+}
+
+// jsonTypes are the json database types, by driver.
+var jsonTypes = map[string]map[string]bool{
+	"postgres": {
+		"json":  true,
+		"jsonb": true,
+	},
+	"mysql": {
+		"json": true,
+	},
+}
+
+// jsonType returns the JSON Go type and zero value for a json column having
+// the value type elem.
+func jsonType(ctx context.Context, elem string, nullable bool) (string, string) {
+	switch {
+	case !nullable:
+		return "JSON[" + elem + "]", "JSON[" + elem + "]{}"
+	case NullMode(ctx) == "pointer":
+		return "*JSON[" + elem + "]", "nil"
+	}
+	return "NullJSON[" + elem + "]", "NullJSON[" + elem + "]{}"
 }
 
 type transformFunc func(...string) string
@@ -798,10 +1345,12 @@ type Funcs struct {
 	driver     string
 	schema     string
 	nth        func(int) string
+	maxParams  int
 	first      bool
 	pkg        string
 	tags       []string
 	imports    []string
+	uuid       string
 	conflict   string
 	custom     string
 	escSchema  bool
@@ -809,6 +1358,11 @@ type Funcs struct {
 	escColumn  bool
 	fieldtag   *template.Template
 	context    string
+	pgx        bool
+	copy       bool
+	defaults   bool
+	nullMode   string
+	json       bool
 	inject     string
 	oracleType string
 	// knownTypes is the collection of known Go types.
@@ -840,14 +1394,20 @@ func NewFuncs(ctx context.Context) (template.FuncMap, error) {
 	if err != nil {
 		return nil, err
 	}
+	maxParams, err := loader.MaxParams(ctx)
+	if err != nil {
+		return nil, err
+	}
 	funcs := &Funcs{
 		first:      first,
 		driver:     driver,
 		schema:     schema,
 		nth:        nth,
+		maxParams:  maxParams,
 		pkg:        Pkg(ctx),
 		tags:       Tags(ctx),
 		imports:    Imports(ctx),
+		uuid:       UUID(ctx),
 		conflict:   Conflict(ctx),
 		custom:     Custom(ctx),
 		escSchema:  Esc(ctx, "schema"),
@@ -855,6 +1415,11 @@ func NewFuncs(ctx context.Context) (template.FuncMap, error) {
 		escColumn:  Esc(ctx, "column"),
 		fieldtag:   fieldtag,
 		context:    Context(ctx),
+		pgx:        DriverMode(ctx) == "pgx",
+		copy:       Copy(ctx),
+		defaults:   InsertDefaults(ctx),
+		nullMode:   NullMode(ctx),
+		json:       JSON(ctx) && jsonTypes[driver] != nil,
 		inject:     inject,
 		oracleType: OracleType(ctx),
 		knownTypes: KnownTypes(ctx),
@@ -877,6 +1442,13 @@ func (f *Funcs) FuncMap() template.FuncMap {
 		// context
 		"context":         f.contextfn,
 		"context_both":    f.context_both,
+		"pgx":             f.pgxfn,
+		"uuid":            f.uuidfn,
+		"result_type":     f.result_type,
+		"copy":            f.copyfn,
+		"insert_defaults": f.insert_defaults,
+		"null_mode":       f.null_mode,
+		"json":            f.jsonfn,
 		"context_disable": f.context_disable,
 		// func and query
 		"func_name_context":   f.func_name_context,
@@ -887,6 +1459,8 @@ func (f *Funcs) FuncMap() template.FuncMap {
 		"recv":                f.recv_none,
 		"foreign_key_context": f.foreign_key_context,
 		"foreign_key":         f.foreign_key_none,
+		"reverse_key_context": f.reverse_key_context,
+		"reverse_params":      f.reverse_params,
 		"db":                  f.db,
 		"db_prefix":           f.db_prefix,
 		"db_update":           f.db_update,
@@ -895,6 +1469,8 @@ func (f *Funcs) FuncMap() template.FuncMap {
 		"logf":                f.logf,
 		"logf_pkeys":          f.logf_pkeys,
 		"logf_update":         f.logf_update,
+		"args_many":           f.args_many,
+		"copy_table":          f.copy_table,
 		// type
 		"names":        f.names,
 		"names_all":    f.names_all,
@@ -904,9 +1480,18 @@ func (f *Funcs) FuncMap() template.FuncMap {
 		"type":         f.typefn,
 		"field":        f.field,
 		"short":        f.short,
+		"plural":       inflector.Pluralize,
 		// sqlstr funcs
-		"querystr": f.querystr,
-		"sqlstr":   f.sqlstr,
+		"querystr":        f.querystr,
+		"sqlstr":          f.sqlstr,
+		"sqlstr_many":     f.sqlstr_many,
+		"sqlstr_defaults": f.sqlstr_defaults,
+		"returning":       f.returning,
+		"default_fields":  f.default_fields,
+		// batch funcs
+		"max_params":   f.max_params,
+		"nth_param":    f.nth_param,
+		"insert_count": f.insert_count,
 		// helpers
 		"check_name": checkName,
 		"eval":       eval,
@@ -932,8 +1517,30 @@ func (f *Funcs) driverfn(drivers ...string) bool {
 }
 
 // schemafn takes a series of names and joins them with the schema name.
-func (f *Funcs) schemafn(names ...string) string {
+//
+// Tables, procs and enums are joined with their schema name.
+func (f *Funcs) schemafn(v ...interface{}) string {
 	s := f.schema
+	var names []string
+	for _, z := range v {
+		var name, schema string
+		switch x := z.(type) {
+		case string:
+			name = x
+		case Table:
+			name, schema = x.SQLName, x.Schema
+		case Proc:
+			name, schema = x.SQLName, x.Schema
+		case Enum:
+			name, schema = x.SQLName, x.Schema
+		default:
+			return fmt.Sprintf("[[ UNSUPPORTED TYPE 31: %T ]]", z)
+		}
+		if schema != "" {
+			s = schema
+		}
+		names = append(names, name)
+	}
 	// escape table names
 	if f.escTable {
 		for i, name := range names {
@@ -945,7 +1552,7 @@ func (f *Funcs) schemafn(names ...string) string {
 	case s == "" && n == "":
 		return ""
 	case f.driver == "sqlite3" && n == "":
-		return f.schema
+		return s
 	case f.driver == "sqlite3":
 		return n
 	case s != "" && n != "":
@@ -967,14 +1574,40 @@ func (f *Funcs) tagsfn() []string {
 	return f.tags
 }
 
-// importsfn returns the imports.
-func (f *Funcs) importsfn() []PackageImport {
+// importsfn returns the imports and the additional packages, skipping packages
+// already imported by the header.
+func (f *Funcs) importsfn(extra []string) []PackageImport {
+	hdr := map[string]bool{
+		"context": true, "database/sql": true, "database/sql/driver": true,
+		"encoding/csv": true, "errors": true, "fmt": true, "io": true, "os": true,
+		"reflect": true, "regexp": true, "strconv": true, "strings": true, "time": true,
+	}
+	var pkgs []string
+	switch {
+	case f.pgx:
+		pkgs = []string{"net/netip", "github.com/jackc/pgx/v5", "github.com/jackc/pgx/v5/pgconn", "github.com/jackc/pgx/v5/pgtype", f.uuid}
+	case f.driver == "postgres":
+		pkgs = []string{"github.com/lib/pq", "github.com/lib/pq/hstore"}
+	case f.driver == "duckdb":
+		pkgs = []string{"github.com/marcboeker/go-duckdb"}
+	}
+	for _, pkg := range pkgs {
+		hdr[pkg] = true
+	}
 	var imports []PackageImport
-	for _, s := range f.imports {
+	seen := make(map[string]bool)
+	for _, s := range append(f.imports, extra...) {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
 		alias, pkg := "", s
 		if i := strings.Index(pkg, " "); i != -1 {
 			alias, pkg = pkg[:i], strings.TrimSpace(pkg[i:])
 		}
+		if alias == "" && hdr[pkg] {
+			continue
+		}
 		imports = append(imports, PackageImport{
 			Alias: alias,
 			Pkg:   strconv.Quote(pkg),
@@ -998,6 +1631,45 @@ func (f *Funcs) context_disable() bool {
 	return f.context == "disable"
 }
 
+// pgxfn returns true when the driver mode is pgx.
+func (f *Funcs) pgxfn() bool {
+	return f.pgx
+}
+
+// copyfn returns true when COPY FROM is enabled for batch inserts.
+func (f *Funcs) copyfn() bool {
+	return f.copy
+}
+
+// insert_defaults returns true when zero values of fields having a default are
+// omitted on insert.
+func (f *Funcs) insert_defaults() bool {
+	return f.defaults
+}
+
+// null_mode returns the nullable type mode.
+func (f *Funcs) null_mode() string {
+	return f.nullMode
+}
+
+// uuidfn returns the uuid type package.
+func (f *Funcs) uuidfn() string {
+	return f.uuid
+}
+
+// jsonfn returns true when the JSON type is enabled for the driver.
+func (f *Funcs) jsonfn() bool {
+	return f.json
+}
+
+// result_type returns the result type of exec queries.
+func (f *Funcs) result_type() string {
+	if f.pgx {
+		return "pgconn.CommandTag"
+	}
+	return "sql.Result"
+}
+
 // injectfn returns the injected content provided from args.
 func (f *Funcs) injectfn() string {
 	return f.inject
@@ -1014,6 +1686,8 @@ func (f *Funcs) func_name_none(v interface{}) string {
 		return x.GoName
 	case ForeignKey:
 		return x.GoName
+	case ReverseForeignKey:
+		return x.GoName
 	case Proc:
 		n := x.GoName
 		if x.Overloaded {
@@ -1022,6 +1696,8 @@ func (f *Funcs) func_name_none(v interface{}) string {
 		return n
 	case Index:
 		return x.Func
+	case Refresh:
+		return x.GoName
 	}
 	return fmt.Sprintf("[[ UNSUPPORTED TYPE 1: %T ]]", v)
 }
@@ -1037,6 +1713,8 @@ func (f *Funcs) func_name_context(v interface{}) string {
 		return nameContext(f.context_both(), x.GoName)
 	case ForeignKey:
 		return nameContext(f.context_both(), x.GoName)
+	case ReverseForeignKey:
+		return nameContext(f.context_both(), x.GoName)
 	case Proc:
 		n := x.GoName
 		if x.Overloaded {
@@ -1045,6 +1723,8 @@ func (f *Funcs) func_name_context(v interface{}) string {
 		return nameContext(f.context_both(), n)
 	case Index:
 		return nameContext(f.context_both(), x.Func)
+	case Refresh:
+		return nameContext(f.context_both(), x.GoName)
 	}
 	return fmt.Sprintf("[[ UNSUPPORTED TYPE 2: %T ]]", v)
 }
@@ -1065,7 +1745,7 @@ func (f *Funcs) funcfn(name string, context bool, v interface{}) string {
 		// returns
 		switch {
 		case x.Exec:
-			r = append(r, "sql.Result")
+			r = append(r, f.result_type())
 		case x.Flat:
 			for _, z := range x.Type.Fields {
 				r = append(r, f.typefn(z.Type))
@@ -1093,6 +1773,8 @@ func (f *Funcs) funcfn(name string, context bool, v interface{}) string {
 			rt = "[]" + rt
 		}
 		r = append(r, rt)
+	case Refresh:
+		p = append(p, "concurrently bool")
 	default:
 		return fmt.Sprintf("[[ UNSUPPORTED TYPE 3: %T ]]", v)
 	}
@@ -1123,6 +1805,8 @@ func (f *Funcs) recv(name string, context bool, t Table, v interface{}) string {
 	switch x := v.(type) {
 	case ForeignKey:
 		r = append(r, "*"+x.RefTable)
+	case ReverseForeignKey:
+		r = append(r, "[]*"+x.Index.Table.GoName)
 	}
 	r = append(r, "error")
 	return fmt.Sprintf("func (%s *%s) %s(%s) (%s)", short, t.GoName, name, strings.Join(p, ", "), strings.Join(r, ", "))
@@ -1174,17 +1858,59 @@ func (f *Funcs) foreign_key_none(v interface{}) string {
 	case ForeignKey:
 		name = x.RefFunc
 		p = append(p, "context.Background()", "db", f.convertTypes(x))
+	case ReverseForeignKey:
+		name = f.short(x.RefTable) + "." + f.func_name_context(x)
+		p = append(p, "context.Background()", "db")
 	default:
 		return fmt.Sprintf("[[ UNSUPPORTED TYPE 7: %T ]]", v)
 	}
 	return fmt.Sprintf("%s(%s)", name, strings.Join(p, ", "))
 }
 
+// reverse_key_context generates a call to the index func of a reverse foreign
+// key.
+func (f *Funcs) reverse_key_context(v interface{}) string {
+	var name string
+	var p []string
+	if f.contextfn() {
+		p = append(p, "ctx")
+	}
+	switch x := v.(type) {
+	case ReverseForeignKey:
+		name = x.Index.Func
+		if f.context_both() {
+			name += "Context"
+		}
+		// add params
+		p = append(p, "db", f.reverseTypes(x, true))
+	default:
+		return fmt.Sprintf("[[ UNSUPPORTED TYPE 31: %T ]]", v)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(p, ", "))
+}
+
+// reverse_params generates the ref field params of a reverse foreign key's
+// query.
+func (f *Funcs) reverse_params(v interface{}) string {
+	switch x := v.(type) {
+	case ReverseForeignKey:
+		return f.reverseTypes(x, false)
+	}
+	return fmt.Sprintf("[[ UNSUPPORTED TYPE 32: %T ]]", v)
+}
+
 // db generates a db.<name>Context(ctx, sqlstr, ...)
+//
+// With pgx, generates a db.<name>(ctx, sqlstr, ...).
 func (f *Funcs) db(name string, v ...interface{}) string {
 	// params
 	var p []interface{}
-	if f.contextfn() {
+	switch {
+	case f.pgx && f.contextfn():
+		p = append(p, "ctx")
+	case f.pgx:
+		p = append(p, "context.Background()")
+	case f.contextfn():
 		name += "Context"
 		p = append(p, "ctx")
 	}
@@ -1213,6 +1939,7 @@ func (f *Funcs) db_prefix(name string, skip bool, vs ...interface{}) string {
 					}
 				}
 			}
+			ignore = append(ignore, generatedNames(x.Fields)...)
 			p := f.names_ignore(prefix, v, ignore...)
 			// p is "" when no columns are present except for primary key
 			// params
@@ -1236,6 +1963,7 @@ func (f *Funcs) db_update(name string, v interface{}) string {
 		for _, pk := range x.PrimaryKeys {
 			ignore = append(ignore, pk.GoName)
 		}
+		ignore = append(ignore, generatedNames(x.Fields)...)
 		p = append(p, f.names_ignore(prefix, x, ignore...), f.names(prefix, x.PrimaryKeys))
 	default:
 		return fmt.Sprintf("[[ UNSUPPORTED TYPE 9: %T ]]", v)
@@ -1300,6 +2028,7 @@ func (f *Funcs) logf(v interface{}, ignore ...interface{}) string {
 	// add fields
 	switch x := v.(type) {
 	case Table:
+		ignoreNames = append(ignoreNames, generatedNames(x.Fields)...)
 		p = append(p, f.names_ignore(f.short(x.GoName)+".", x, ignoreNames...))
 	default:
 		return fmt.Sprintf("[[ UNSUPPORTED TYPE 12: %T ]]", v)
@@ -1316,6 +2045,7 @@ func (f *Funcs) logf_update(v interface{}) string {
 		for _, pk := range x.PrimaryKeys {
 			ignore = append(ignore, pk.GoName)
 		}
+		ignore = append(ignore, generatedNames(x.Fields)...)
 		p = append(p, f.names_ignore(prefix, x, ignore...), f.names(prefix, x.PrimaryKeys))
 	default:
 		return fmt.Sprintf("[[ UNSUPPORTED TYPE 13: %T ]]", v)
@@ -1449,28 +2179,40 @@ func (f *Funcs) sqlstr(typ string, v interface{}) string {
 	default:
 		return fmt.Sprintf("const sqlstr = `UNKNOWN QUERY TYPE: %s`", typ)
 	}
+	// backticks (ie, in mysql index expressions) can not be in a raw string
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "`", "` + \"`\" + `")
+	}
 	return fmt.Sprintf("const sqlstr = `%s`", strings.Join(lines, "` +\n\t`"))
 }
 
 // sqlstr_insert_base builds an INSERT query
-// If not all, sequence columns are skipped.
+// If not all, sequence columns are skipped. Generated columns are always
+// skipped.
 func (f *Funcs) sqlstr_insert_base(all bool, v interface{}) []string {
 	switch x := v.(type) {
 	case Table:
 		// build names and values
 		var n int
 		var fields, vals []string
+		var seq bool
 		for _, z := range x.Fields {
-			if z.IsSequence && !all {
+			if (z.IsSequence && !all) || z.IsGenerated {
 				continue
 			}
 			fields, vals = append(fields, f.colname(z)), append(vals, f.nth(n))
+			seq = seq || z.IsSequence
 			n++
 		}
+		values := ") VALUES ("
+		if seq && f.driver == "postgres" {
+			// allow values for GENERATED ALWAYS AS IDENTITY columns
+			values = ") OVERRIDING SYSTEM VALUE VALUES ("
+		}
 		return []string{
-			"INSERT INTO " + f.schemafn(x.SQLName) + " (",
+			"INSERT INTO " + f.schemafn(x) + " (",
 			strings.Join(fields, ", "),
-			") VALUES (",
+			values,
 			strings.Join(vals, ", "),
 			")",
 		}
@@ -1480,7 +2222,9 @@ func (f *Funcs) sqlstr_insert_base(all bool, v interface{}) []string {
 
 // sqlstr_insert_manual builds an INSERT query that inserts all fields.
 func (f *Funcs) sqlstr_insert_manual(v interface{}) []string {
-	return f.sqlstr_insert_base(true, v)
+	lines := f.sqlstr_insert_base(true, v)
+	lines[len(lines)-1] += f.returning_clause("insert_manual", v)
+	return lines
 }
 
 // sqlstr_insert builds an INSERT query, skipping the sequence field with
@@ -1491,9 +2235,10 @@ func (f *Funcs) sqlstr_insert(v interface{}) []string {
 		var seq Field
 		var count int
 		for _, field := range x.Fields {
-			if field.IsSequence {
+			switch {
+			case field.IsSequence:
 				seq = field
-			} else {
+			case !field.IsGenerated:
 				count++
 			}
 		}
@@ -1509,8 +2254,8 @@ func (f *Funcs) sqlstr_insert(v interface{}) []string {
 			default:
 				return []string{fmt.Sprintf("[[ UNSUPPORTED ORACLE TYPE: %s]]", f.oracleType)}
 			}
-		case "postgres":
-			lines[len(lines)-1] += ` RETURNING ` + f.colname(seq)
+		case "postgres", "sqlite3", "duckdb":
+			lines[len(lines)-1] += f.returning_clause("insert", v)
 		case "sqlserver":
 			lines[len(lines)-1] += "; SELECT ID = CONVERT(BIGINT, SCOPE_IDENTITY())"
 		}
@@ -1519,6 +2264,245 @@ func (f *Funcs) sqlstr_insert(v interface{}) []string {
 	return []string{fmt.Sprintf("[[ UNSUPPORTED TYPE 18: %T ]]", v)}
 }
 
+// returning returns the fields returned by an insert, insert_manual, update, or
+// upsert query for drivers supporting RETURNING, being the generated fields
+// and, when inserting, the sequence field.
+//
+// sqlite3 only returns the sequence field when there are generated fields, as
+// it is otherwise retrieved with LastInsertId.
+//
+// An insert_defaults query returns the sequence, generated, and default fields
+// for all drivers.
+func (f *Funcs) returning(typ string, v interface{}) []Field {
+	x, ok := v.(Table)
+	switch {
+	case !ok:
+		return nil
+	case typ == "insert_defaults":
+		var fields []Field
+		for _, field := range x.Fields {
+			if field.IsSequence || field.IsGenerated || field.Default != "" {
+				fields = append(fields, field)
+			}
+		}
+		return fields
+	case f.driver != "postgres" && f.driver != "sqlite3" && f.driver != "duckdb":
+		return nil
+	}
+	var fields []Field
+	for _, field := range x.Fields {
+		if field.IsGenerated {
+			fields = append(fields, field)
+		}
+	}
+	if typ != "insert" || (f.driver == "sqlite3" && len(fields) == 0) {
+		return fields
+	}
+	for _, field := range x.Fields {
+		if field.IsSequence {
+			return append([]Field{field}, fields...)
+		}
+	}
+	return fields
+}
+
+// default_fields returns the fields having a default value, that are omitted
+// from an insert when zero, when enabled.
+func (f *Funcs) default_fields(v interface{}) []Field {
+	x, ok := v.(Table)
+	if !ok || !f.defaults {
+		return nil
+	}
+	var fields []Field
+	for _, field := range x.Fields {
+		if field.Default != "" && !field.IsSequence && !field.IsGenerated {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// sqlstr_defaults generates the code building an INSERT query at runtime,
+// omitting the zero values of fields having a default value, and returning
+// the values generated by the database.
+func (f *Funcs) sqlstr_defaults(v interface{}) string {
+	x, ok := v.(Table)
+	if !ok {
+		return fmt.Sprintf("[[ UNSUPPORTED TYPE 29: %T ]]", v)
+	}
+	short := f.short(x.GoName)
+	defaults := make(map[string]bool)
+	for _, field := range f.default_fields(x) {
+		defaults[field.GoName] = true
+	}
+	// fields always inserted
+	var cols, args []string
+	for _, field := range x.Fields {
+		if field.IsSequence || field.IsGenerated || defaults[field.GoName] {
+			continue
+		}
+		cols, args = append(cols, strconv.Quote(f.colname(field))), append(args, short+"."+field.GoName)
+	}
+	lines := []string{fmt.Sprintf("cols, args := []string{%s}, []interface{}{%s}", strings.Join(cols, ", "), strings.Join(args, ", "))}
+	// fields inserted when not zero
+	for _, field := range x.Fields {
+		if !defaults[field.GoName] {
+			continue
+		}
+		name := short + "." + field.GoName
+		lines = append(lines,
+			fmt.Sprintf("if !isZero(%s) {", name),
+			fmt.Sprintf("\tcols, args = append(cols, %s), append(args, %s)", strconv.Quote(f.colname(field)), name),
+			"}",
+		)
+	}
+	// returned fields
+	var returning, out []string
+	for _, field := range f.returning("insert_defaults", x) {
+		returning = append(returning, strconv.Quote(f.colname(field)))
+		out = append(out, "sql.Out{Dest: &"+short+"."+field.GoName+"}")
+	}
+	lines = append(lines, fmt.Sprintf("sqlstr := insertSQL(%s, cols, []string{%s})", strconv.Quote(f.schemafn(x)), strings.Join(returning, ", ")))
+	if f.driver == "oracle" {
+		lines = append(lines, "args = append(args, "+strings.Join(out, ", ")+")")
+	}
+	return strings.Join(lines, "\n\t")
+}
+
+// returning_clause builds the RETURNING clause for the fields returned by a
+// query.
+func (f *Funcs) returning_clause(typ string, v interface{}) string {
+	var names []string
+	for _, field := range f.returning(typ, v) {
+		names = append(names, f.colname(field))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return " RETURNING " + strings.Join(names, ", ")
+}
+
+// generatedNames returns the names of the generated fields, which are never
+// written.
+func generatedNames(fields []Field) []string {
+	var names []string
+	for _, field := range fields {
+		if field.IsGenerated {
+			names = append(names, field.GoName)
+		}
+	}
+	return names
+}
+
+// sqlstr_many builds a multi-row INSERT or upsert query for a batch of rows,
+// using the generated valuesList func to build the VALUES list.
+func (f *Funcs) sqlstr_many(typ string, v interface{}) string {
+	x, ok := v.(Table)
+	if !ok {
+		return fmt.Sprintf("[[ UNSUPPORTED TYPE 26: %T ]]", v)
+	}
+	var suffix []string
+	all := true
+	switch typ {
+	case "insert_manual":
+	case "insert":
+		all = false
+	case "upsert":
+		switch f.driver {
+		case "postgres", "sqlite3", "duckdb":
+			suffix = f.sqlstr_upsert_postgres_sqlite(x)
+		case "mysql":
+			suffix = f.sqlstr_upsert_mysql(x)
+		}
+	default:
+		return fmt.Sprintf("sqlstr := `UNKNOWN QUERY TYPE: %s`", typ)
+	}
+	if s := f.returning_clause(typ, x); s != "" {
+		if n := len(suffix); n != 0 {
+			suffix[n-1] = strings.TrimRight(suffix[n-1], " ") + s
+		} else {
+			suffix = append(suffix, s)
+		}
+	}
+	lines := f.sqlstr_insert_base(all, x)
+	lines = append(lines[:2], strings.TrimSuffix(lines[2], "("))
+	s := fmt.Sprintf("sqlstr := `%s` +\n\tvaluesList(len(batch), %d)", strings.Join(lines, "` +\n\t`"), f.insert_count(all, x))
+	if len(suffix) != 0 {
+		s += " +\n\t`" + strings.Join(suffix, "` +\n\t`") + "`"
+	}
+	return s
+}
+
+// insert_count returns the number of parameters for a row of a table, skipping
+// sequence fields when not all, and generated fields.
+func (f *Funcs) insert_count(all bool, v interface{}) int {
+	var n int
+	switch x := v.(type) {
+	case Table:
+		for _, field := range x.Fields {
+			if (all || !field.IsSequence) && !field.IsGenerated {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// args_many generates the parameters for a row of a table in a batch, skipping
+// sequence fields when not all, and generated fields.
+func (f *Funcs) args_many(all bool, v interface{}) string {
+	switch x := v.(type) {
+	case Table:
+		ignore := generatedNames(x.Fields)
+		for _, field := range x.Fields {
+			if !all && field.IsSequence {
+				ignore = append(ignore, field.GoName)
+			}
+		}
+		return f.names_ignore(f.short(x.GoName)+".", x, ignore...)
+	}
+	return fmt.Sprintf("[[ UNSUPPORTED TYPE 27: %T ]]", v)
+}
+
+// copy_table generates the table identifier and column names for a pgx
+// CopyFrom.
+func (f *Funcs) copy_table(v interface{}) string {
+	switch x := v.(type) {
+	case Table:
+		schema := f.schema
+		if x.Schema != "" {
+			schema = x.Schema
+		}
+		table := []string{strconv.Quote(x.SQLName)}
+		if schema != "" {
+			table = append([]string{strconv.Quote(schema)}, table...)
+		}
+		var cols []string
+		for _, field := range x.Fields {
+			if !field.IsGenerated {
+				cols = append(cols, strconv.Quote(field.SQLName))
+			}
+		}
+		return fmt.Sprintf("pgx.Identifier{%s}, []string{%s}", strings.Join(table, ", "), strings.Join(cols, ", "))
+	}
+	return fmt.Sprintf("[[ UNSUPPORTED TYPE 28: %T ]]", v)
+}
+
+// max_params returns the maximum number of query parameters for the driver.
+func (f *Funcs) max_params() int {
+	return f.maxParams
+}
+
+// nth_param generates a Go expression for the 0-based nth query parameter
+// placeholder, where name is the variable holding n.
+func (f *Funcs) nth_param(name string) string {
+	first := f.nth(0)
+	if first == f.nth(1) {
+		return strconv.Quote(first)
+	}
+	return fmt.Sprintf("%q + strconv.Itoa(%s+1)", strings.TrimSuffix(first, "1"), name)
+}
+
 // sqlstr_update_base builds an UPDATE query, using primary key fields as the WHERE
 // clause, adding prefix.
 //
@@ -1534,7 +2518,7 @@ func (f *Funcs) sqlstr_update_base(prefix string, v interface{}) (int, []string)
 		var n int
 		var list []string
 		for _, z := range x.Fields {
-			if z.IsPrimary {
+			if z.IsPrimary || z.IsGenerated {
 				continue
 			}
 			name, param := f.colname(z), f.nth(n)
@@ -1546,7 +2530,7 @@ func (f *Funcs) sqlstr_update_base(prefix string, v interface{}) (int, []string)
 		}
 		name := ""
 		if prefix == "" {
-			name = f.schemafn(x.SQLName) + " "
+			name = f.schemafn(x) + " "
 		}
 		return n, []string{
 			"UPDATE " + name + "SET ",
@@ -1567,7 +2551,7 @@ func (f *Funcs) sqlstr_update(v interface{}) []string {
 		for i, z := range x.PrimaryKeys {
 			list = append(list, fmt.Sprintf("%s = %s", f.colname(z), f.nth(n+i)))
 		}
-		return append(lines, "WHERE "+strings.Join(list, " AND "))
+		return append(lines, "WHERE "+strings.Join(list, " AND ")+f.returning_clause("update", v))
 	}
 	return []string{fmt.Sprintf("[[ UNSUPPORTED TYPE 20: %T ]]", v)}
 }
@@ -1578,8 +2562,12 @@ func (f *Funcs) sqlstr_upsert(v interface{}) []string {
 		// build insert
 		lines := f.sqlstr_insert_base(true, x)
 		switch f.driver {
-		case "postgres", "sqlite3":
-			return append(lines, f.sqlstr_upsert_postgres_sqlite(x)...)
+		case "postgres", "sqlite3", "duckdb":
+			lines = append(lines, f.sqlstr_upsert_postgres_sqlite(x)...)
+			if s := f.returning_clause("upsert", x); s != "" {
+				lines[len(lines)-1] = strings.TrimRight(lines[len(lines)-1], " ") + s
+			}
+			return lines
 		case "mysql":
 			return append(lines, f.sqlstr_upsert_mysql(x)...)
 		case "sqlserver", "oracle":
@@ -1617,7 +2605,7 @@ func (f *Funcs) sqlstr_upsert_mysql(v interface{}) []string {
 		var list []string
 		i := len(x.Fields)
 		for _, z := range x.Fields {
-			if z.IsSequence {
+			if z.IsSequence || z.IsGenerated {
 				continue
 			}
 			name := f.colname(z)
@@ -1639,14 +2627,16 @@ func (f *Funcs) sqlstr_upsert_sqlserver_oracle(v interface{}) []string {
 		// merge [table]...
 		switch f.driver {
 		case "sqlserver":
-			lines = []string{"MERGE " + f.schemafn(x.SQLName) + " AS t "}
+			lines = []string{"MERGE " + f.schemafn(x) + " AS t "}
 		case "oracle":
-			lines = []string{"MERGE " + f.schemafn(x.SQLName) + "t "}
+			lines = []string{"MERGE " + f.schemafn(x) + "t "}
 		}
 		// using (select ..)
 		var fields, predicate []string
-		for i, field := range x.Fields {
-			fields = append(fields, fmt.Sprintf("%s %s", f.nth(i), field.SQLName))
+		for _, field := range x.Fields {
+			if !field.IsGenerated {
+				fields = append(fields, fmt.Sprintf("%s %s", f.nth(len(fields)), field.SQLName))
+			}
 		}
 		for _, field := range x.PrimaryKeys {
 			predicate = append(predicate, fmt.Sprintf("s.%s = t.%s", field.SQLName, field.SQLName))
@@ -1666,8 +2656,8 @@ func (f *Funcs) sqlstr_upsert_sqlserver_oracle(v interface{}) []string {
 		// build param lists
 		var updateParams, insertParams, insertVals []string
 		for _, field := range x.Fields {
-			// sequences are always managed by db
-			if field.IsSequence {
+			// sequences and generated fields are always managed by db
+			if field.IsSequence || field.IsGenerated {
 				continue
 			}
 			// primary keys
@@ -1703,7 +2693,7 @@ func (f *Funcs) sqlstr_delete(v interface{}) []string {
 			list = append(list, fmt.Sprintf("%s = %s", f.colname(z), f.nth(i)))
 		}
 		return []string{
-			"DELETE FROM " + f.schemafn(x.SQLName) + " ",
+			"DELETE FROM " + f.schemafn(x) + " ",
 			"WHERE " + strings.Join(list, " AND "),
 		}
 	}
@@ -1719,15 +2709,23 @@ func (f *Funcs) sqlstr_index(v interface{}) []string {
 		for _, z := range x.Table.Fields {
 			fields = append(fields, f.colname(z))
 		}
-		// index fields
+		// index fields and expressions
 		var list []string
 		for i, z := range x.Fields {
-			list = append(list, fmt.Sprintf("%s = %s", f.colname(z), f.nth(i)))
+			name := f.colname(z)
+			if i < len(x.Exprs) && x.Exprs[i] != "" {
+				name = x.Exprs[i]
+			}
+			list = append(list, fmt.Sprintf("%s = %s", name, f.nth(i)))
+		}
+		// partial index predicate
+		if x.Predicate != "" {
+			list = append(list, "("+x.Predicate+")")
 		}
 		return []string{
 			"SELECT ",
 			strings.Join(fields, ", ") + " ",
-			"FROM " + f.schemafn(x.Table.SQLName) + " ",
+			"FROM " + f.schemafn(x.Table) + " ",
 			"WHERE " + strings.Join(list, " AND "),
 		}
 	}
@@ -1765,7 +2763,7 @@ func (f *Funcs) sqlstr_proc(v interface{}) []string {
 			list = append(list, s)
 		}
 		// dont prefix with schema for oracle
-		name := f.schemafn(x.SQLName)
+		name := f.schemafn(x)
 		if f.driver == "oracle" {
 			name = x.SQLName
 		}
@@ -1796,7 +2794,7 @@ func (f *Funcs) sqlstr_func(v interface{}) []string {
 			list = append(list, f.nth(i))
 		}
 		return []string{
-			fmt.Sprintf(format, f.schemafn(x.SQLName), strings.Join(list, ", ")),
+			fmt.Sprintf(format, f.schemafn(x), strings.Join(list, ", ")),
 		}
 	}
 	return []string{fmt.Sprintf("[[ UNSUPPORTED TYPE 28: %T ]]", v)}
@@ -1817,9 +2815,10 @@ func (f *Funcs) convertTypes(fkey ForeignKey) string {
 		}
 		// convert types
 		typ, refType := field.Type, refField.Type
-		if strings.HasPrefix(typ, "sql.Null") {
-			expr = expr + "." + typ[8:]
-			typ = strings.ToLower(typ[8:])
+		expr, typ = nullValue(expr, typ)
+		if v, ok := pgtypeValues[typ]; ok {
+			expr = expr + "." + v[0]
+			typ = v[1]
 		}
 		if strings.ToLower(refType) != typ {
 			expr = refType + "(" + expr + ")"
@@ -1829,6 +2828,85 @@ func (f *Funcs) convertTypes(fkey ForeignKey) string {
 	return strings.Join(p, ", ")
 }
 
+// nullValue returns the expression for the value of a nullable type and the
+// lower cased value type. Nil pointers are converted to the zero value.
+// reverseTypes generates the ref field values of a reverse foreign key,
+// converting them to the types of the index's fields when convert is true.
+func (f *Funcs) reverseTypes(rev ReverseForeignKey, convert bool) string {
+	var p []string
+	for i, refField := range rev.RefFields {
+		expr := f.short(rev.RefTable) + "." + refField.GoName
+		if convert {
+			expr, _ = wrapValue(expr, rev.Index.Fields[i].Type, refField.Type)
+		}
+		p = append(p, expr)
+	}
+	return strings.Join(p, ", ")
+}
+
+// wrapValue converts expr of type refType to typ, wrapping it in the nullable
+// type when typ is nullable. The inverse of nullValue. Returns false when expr
+// cannot be converted.
+func wrapValue(expr, typ, refType string) (string, bool) {
+	if typ == refType {
+		return expr, true
+	}
+	var field, base string
+	switch {
+	case strings.HasPrefix(typ, "sql.Null["):
+		field, base = "V", typ[9:len(typ)-1]
+	case strings.HasPrefix(typ, "sql.Null"):
+		field = typ[8:]
+		base = strings.ToLower(field)
+		if base == "time" {
+			base = "time.Time"
+		}
+	case strings.HasPrefix(typ, "*") && typ[1:] == refType:
+		return "&" + expr, true
+	default:
+		v, ok := pgtypeValues[typ]
+		if !ok {
+			return "", false
+		}
+		field, base = v[0], v[1]
+	}
+	switch {
+	case strings.EqualFold(base, refType):
+	case numberRE.MatchString(base) && numberRE.MatchString(refType):
+		expr = base + "(" + expr + ")"
+	default:
+		return "", false
+	}
+	return fmt.Sprintf("%s{%s: %s, Valid: true}", typ, field, expr), true
+}
+
+func nullValue(expr, typ string) (string, string) {
+	switch {
+	case strings.HasPrefix(typ, "sql.Null["):
+		return expr + ".V", strings.ToLower(typ[9 : len(typ)-1])
+	case strings.HasPrefix(typ, "sql.Null"):
+		return expr + "." + typ[8:], strings.ToLower(typ[8:])
+	case strings.HasPrefix(typ, "*"):
+		return "deref(" + expr + ")", strings.ToLower(typ[1:])
+	}
+	return expr, typ
+}
+
+// pgtypeValues are the value field and Go type of the pgtype nullable types.
+var pgtypeValues = map[string][2]string{
+	"pgtype.Bool":        {"Bool", "bool"},
+	"pgtype.Text":        {"String", "string"},
+	"pgtype.Int2":        {"Int16", "int16"},
+	"pgtype.Int4":        {"Int32", "int32"},
+	"pgtype.Int8":        {"Int64", "int64"},
+	"pgtype.Float4":      {"Float32", "float32"},
+	"pgtype.Float8":      {"Float64", "float64"},
+	"pgtype.Date":        {"Time", "time.time"},
+	"pgtype.Timestamp":   {"Time", "time.time"},
+	"pgtype.Timestamptz": {"Time", "time.time"},
+	"pgtype.UUID":        {"Bytes", "uuid.uuid"},
+}
+
 // params converts a list of fields into their named Go parameters, skipping
 // any Field with Name contained in ignore. addType will cause the go Type to
 // be added after each variable name. addPrefix will cause the returned string
@@ -1879,7 +2957,8 @@ func (f *Funcs) zero(z ...interface{}) string {
 				zeroes = append(zeroes, f.zero(p))
 			}
 		case Field:
-			if _, ok := f.knownTypes[x.Type]; ok || x.Zero == "nil" {
+			// named domain types have the zero value of their base type
+			if _, ok := f.knownTypes[x.Type]; ok || x.Zero == "nil" || !strings.HasSuffix(x.Zero, "{}") {
 				zeroes = append(zeroes, x.Zero)
 				break
 			}
@@ -1893,13 +2972,21 @@ func (f *Funcs) zero(z ...interface{}) string {
 
 // typefn generates the Go type, prefixing the custom package name if applicable.
 func (f *Funcs) typefn(typ string) string {
+	for _, s := range []string{"sql.Null[", "JSON[", "*JSON[", "NullJSON["} {
+		if strings.HasPrefix(typ, s) {
+			return s + f.typefn(typ[len(s):len(typ)-1]) + "]"
+		}
+	}
 	if strings.Contains(typ, ".") {
 		return typ
 	}
 	var prefix string
-	for strings.HasPrefix(typ, "[]") {
-		typ = typ[2:]
-		prefix += "[]"
+	for strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "*") {
+		n := 1
+		if typ[0] == '[' {
+			n = 2
+		}
+		prefix, typ = prefix+typ[:n], typ[n:]
 	}
 	if _, ok := f.knownTypes[typ]; ok || f.custom == "" {
 		return prefix + typ
@@ -2008,13 +3095,15 @@ func eval(v interface{}, s string) (string, error) {
 // templateReservedNames are the template reserved names.
 var templateReservedNames = map[string]bool{
 	// variables
-	"ctx":  true,
-	"db":   true,
-	"err":  true,
-	"log":  true,
-	"logf": true,
-	"res":  true,
-	"rows": true,
+	"args":  true,
+	"batch": true,
+	"ctx":   true,
+	"db":    true,
+	"err":   true,
+	"log":   true,
+	"logf":  true,
+	"res":   true,
+	"rows":  true,
 
 	// packages
 	"context": true,
@@ -2025,6 +3114,7 @@ var templateReservedNames = map[string]bool{
 	"hstore":  true,
 	"regexp":  true,
 	"sql":     true,
+	"strconv": true,
 	"strings": true,
 	"time":    true,
 	"uuid":    true,
@@ -2090,27 +3180,36 @@ func nameContext(context bool, name string) string {
 
 // Context keys.
 var (
-	AppendKey     xo.ContextKey = "append"
-	KnownTypesKey xo.ContextKey = "known-types"
-	ShortsKey     xo.ContextKey = "shorts"
-	NotFirstKey   xo.ContextKey = "not-first"
-	Int32Key      xo.ContextKey = "int32"
-	Uint32Key     xo.ContextKey = "uint32"
-	ArrayModeKey  xo.ContextKey = "array-mode"
-	PkgKey        xo.ContextKey = "pkg"
-	TagKey        xo.ContextKey = "tag"
-	ImportKey     xo.ContextKey = "import"
-	UUIDKey       xo.ContextKey = "uuid"
-	CustomKey     xo.ContextKey = "custom"
-	ConflictKey   xo.ContextKey = "conflict"
-	InitialismKey xo.ContextKey = "initialism"
-	EscKey        xo.ContextKey = "esc"
-	FieldTagKey   xo.ContextKey = "field-tag"
-	ContextKey    xo.ContextKey = "context"
-	InjectKey     xo.ContextKey = "inject"
-	InjectFileKey xo.ContextKey = "inject-file"
-	LegacyKey     xo.ContextKey = "legacy"
-	OracleTypeKey xo.ContextKey = "oracle-type"
+	AppendKey         xo.ContextKey = "append"
+	KnownTypesKey     xo.ContextKey = "known-types"
+	ShortsKey         xo.ContextKey = "shorts"
+	NotFirstKey       xo.ContextKey = "not-first"
+	Int32Key          xo.ContextKey = "int32"
+	Uint32Key         xo.ContextKey = "uint32"
+	ArrayModeKey      xo.ContextKey = "array-mode"
+	DriverModeKey     xo.ContextKey = "driver-mode"
+	NullModeKey       xo.ContextKey = "null-mode"
+	TypeKey           xo.ContextKey = "type"
+	JSONKey           xo.ContextKey = "json"
+	CopyKey           xo.ContextKey = "copy"
+	InsertDefaultsKey xo.ContextKey = "insert-defaults"
+	DomainTypesKey    xo.ContextKey = "domain-types"
+	DomainsKey        xo.ContextKey = "domains"
+	TablesKey         xo.ContextKey = "tables"
+	PkgKey            xo.ContextKey = "pkg"
+	TagKey            xo.ContextKey = "tag"
+	ImportKey         xo.ContextKey = "import"
+	UUIDKey           xo.ContextKey = "uuid"
+	CustomKey         xo.ContextKey = "custom"
+	ConflictKey       xo.ContextKey = "conflict"
+	InitialismKey     xo.ContextKey = "initialism"
+	EscKey            xo.ContextKey = "esc"
+	FieldTagKey       xo.ContextKey = "field-tag"
+	ContextKey        xo.ContextKey = "context"
+	InjectKey         xo.ContextKey = "inject"
+	InjectFileKey     xo.ContextKey = "inject-file"
+	LegacyKey         xo.ContextKey = "legacy"
+	OracleTypeKey     xo.ContextKey = "oracle-type"
 )
 
 // Append returns append from the context.
@@ -2155,6 +3254,54 @@ func ArrayMode(ctx context.Context) string {
 	return s
 }
 
+// DriverMode returns driver-mode from the context.
+func DriverMode(ctx context.Context) string {
+	s, _ := ctx.Value(DriverModeKey).(string)
+	return s
+}
+
+// NullMode returns null-mode from the context.
+func NullMode(ctx context.Context) string {
+	s, _ := ctx.Value(NullModeKey).(string)
+	return s
+}
+
+// JSON returns json from the context.
+func JSON(ctx context.Context) bool {
+	b, _ := ctx.Value(JSONKey).(bool)
+	return b
+}
+
+// Copy returns copy from the context.
+func Copy(ctx context.Context) bool {
+	b, _ := ctx.Value(CopyKey).(bool)
+	return b
+}
+
+// InsertDefaults returns insert-defaults from the context.
+func InsertDefaults(ctx context.Context) bool {
+	b, _ := ctx.Value(InsertDefaultsKey).(bool)
+	return b
+}
+
+// DomainTypes returns domain-types from the context.
+func DomainTypes(ctx context.Context) bool {
+	b, _ := ctx.Value(DomainTypesKey).(bool)
+	return b
+}
+
+// Domains returns domains from the context.
+func Domains(ctx context.Context) map[string]*Domain {
+	m, _ := ctx.Value(DomainsKey).(map[string]*Domain)
+	return m
+}
+
+// Tables returns the generated tables from the context.
+func Tables(ctx context.Context) map[string]Table {
+	m, _ := ctx.Value(TablesKey).(map[string]Table)
+	return m
+}
+
 // Pkg returns pkg from the context.
 func Pkg(ctx context.Context) string {
 	s, _ := ctx.Value(PkgKey).(string)
@@ -2164,6 +3311,14 @@ func Pkg(ctx context.Context) string {
 	return s
 }
 
+// UUID returns the uuid type package from the context.
+func UUID(ctx context.Context) string {
+	if s, _ := ctx.Value(UUIDKey).(string); s != "" {
+		return s
+	}
+	return "github.com/google/uuid"
+}
+
 // Tags returns tags from the context.
 func Tags(ctx context.Context) []string {
 	v, _ := ctx.Value(TagKey).([]string)
@@ -2188,12 +3343,200 @@ func Imports(ctx context.Context) []string {
 		}
 	}
 	// add uuid import
-	if s, _ := ctx.Value(UUIDKey).(string); s != "" {
-		imports = append(imports, s)
+	imports = append(imports, UUID(ctx))
+	// add type override imports
+	overrides, _ := TypeOverrides(ctx)
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
 	}
-	return imports
+	sort.Strings(keys)
+	for _, k := range keys {
+		imports = append(imports, overrides[k].Imports...)
+	}
+	// remove duplicates
+	var unique []string
+	seen := make(map[string]bool)
+	for _, s := range imports {
+		if !seen[s] {
+			unique = append(unique, s)
+		}
+		seen[s] = true
+	}
+	return unique
+}
+
+// annotationImports returns the imports for the types in the xo:type
+// annotations of the set's columns.
+func annotationImports(ctx context.Context, set *xo.Set) ([]string, error) {
+	var imports []string
+	for _, schema := range set.Schemas {
+		for _, tables := range [][]xo.Table{schema.Tables, schema.Views} {
+			for _, t := range tables {
+				for _, f := range t.Columns {
+					s := f.Annotations["type"]
+					if s == "" {
+						continue
+					}
+					o, err := parseTypeOverride(ctx, s)
+					if err != nil {
+						return nil, fmt.Errorf("%s column %q: invalid xo:type annotation %q", t.Name, f.Name, s)
+					}
+					imports = append(imports, o.Imports...)
+				}
+			}
+		}
+	}
+	return imports, nil
+}
+
+// TypeOverride is a Go type override for a database type or table column.
+type TypeOverride struct {
+	Type         string
+	Nullable     string
+	Zero         string
+	NullableZero string
+	Imports      []string
+}
+
+// TypeOverrides returns the type overrides from the context, keyed by
+// database type or table.column.
+//
+// Each override is in the form of <key>=<type>[;<nullable type>[;<zero>]],
+// where types may be prefixed with their import path (for example,
+// users.settings=github.com/acme/types.Settings). When not specified, the
+// nullable type is determined by the null mode, and the zero value by the
+// type.
+func TypeOverrides(ctx context.Context) (map[string]TypeOverride, error) {
+	v, _ := ctx.Value(TypeKey).([]string)
+	overrides := make(map[string]TypeOverride)
+	for _, s := range v {
+		if s == "" {
+			continue
+		}
+		i := strings.Index(s, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("invalid type override %q", s)
+		}
+		o, err := parseTypeOverride(ctx, s[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid type override %q", s)
+		}
+		overrides[strings.TrimSpace(s[:i])] = o
+	}
+	return overrides, nil
+}
+
+// parseTypeOverride parses a type override in the form of
+// <type>[;<nullable type>[;<zero>]].
+func parseTypeOverride(ctx context.Context, s string) (TypeOverride, error) {
+	z := strings.Split(s, ";")
+	if len(z) > 3 || strings.TrimSpace(z[0]) == "" {
+		return TypeOverride{}, errors.New("invalid type")
+	}
+	var o TypeOverride
+	var pkg string
+	o.Type, pkg = parseGoType(strings.TrimSpace(z[0]))
+	if pkg != "" {
+		o.Imports = append(o.Imports, pkg)
+	}
+	// nullable type
+	switch {
+	case len(z) > 1 && strings.TrimSpace(z[1]) != "":
+		o.Nullable, pkg = parseGoType(strings.TrimSpace(z[1]))
+		if pkg != "" {
+			o.Imports = append(o.Imports, pkg)
+		}
+	case nilable(o.Type):
+		o.Nullable = o.Type
+	default:
+		switch NullMode(ctx) {
+		case "pointer":
+			o.Nullable = "*" + o.Type
+		case "generic":
+			o.Nullable = "sql.Null[" + o.Type + "]"
+		default:
+			o.Nullable = o.Type
+		}
+	}
+	// zero values
+	o.Zero, o.NullableZero = zeroValue(o.Type), zeroValue(o.Nullable)
+	if len(z) > 2 && strings.TrimSpace(z[2]) != "" {
+		o.Zero = strings.TrimSpace(z[2])
+		if o.Nullable == o.Type {
+			o.NullableZero = o.Zero
+		}
+	}
+	return o, nil
+}
+
+// parseGoType parses a Go type optionally prefixed by its import path (for
+// example, *github.com/acme/types.Settings), returning the type as used in
+// generated code (*types.Settings) and the import path.
+func parseGoType(s string) (string, string) {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return r != '*' && r != '[' && r != ']'
+	})
+	if i == -1 {
+		return s, ""
+	}
+	prefix, typ := s[:i], s[i:]
+	// the type name follows the last dot after the import path's last
+	// element, ignoring any type arguments
+	slash := strings.LastIndex(typ, "/")
+	if j := strings.Index(typ, "["); j != -1 {
+		slash = strings.LastIndex(typ[:j], "/")
+	}
+	if slash == -1 {
+		return s, ""
+	}
+	end := len(typ)
+	if j := strings.Index(typ[slash:], "["); j != -1 {
+		end = slash + j
+	}
+	dot := strings.LastIndex(typ[:end], ".")
+	if dot < slash {
+		return s, ""
+	}
+	pkg := typ[:dot]
+	// use the package name, skipping major version suffixes (ie, /v2 and
+	// gopkg.in's .v3)
+	name := path.Base(pkg)
+	if versionRE.MatchString(name) {
+		name = path.Base(path.Dir(pkg))
+	}
+	if j := strings.Index(name, "."); j != -1 {
+		name = name[:j]
+	}
+	return prefix + name + typ[dot:], pkg
+}
+
+// versionRE matches major version import path suffixes.
+var versionRE = regexp.MustCompile(`^v[0-9]+$`)
+
+// nilable returns whether typ can be nil.
+func nilable(typ string) bool {
+	return strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[")
 }
 
+// zeroValue returns the zero value for typ.
+func zeroValue(typ string) string {
+	switch {
+	case nilable(typ):
+		return "nil"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case numberRE.MatchString(typ):
+		return "0"
+	}
+	return typ + "{}"
+}
+
+// numberRE matches Go number types.
+var numberRE = regexp.MustCompile(`^(u?int(8|16|32|64)?|float(32|64)|byte|rune)$`)
+
 // Custom returns the custom package from the context.
 func Custom(ctx context.Context) string {
 	s, _ := ctx.Value(CustomKey).(string)
@@ -2290,6 +3633,7 @@ type EnumValue struct {
 type Enum struct {
 	GoName  string
 	SQLName string
+	Schema  string
 	Values  []EnumValue
 	Comment string
 }
@@ -2300,6 +3644,7 @@ type Proc struct {
 	GoName         string
 	OverloadedName string
 	SQLName        string
+	Schema         string
 	Signature      string
 	Params         []Field
 	Returns        []Field
@@ -2313,6 +3658,7 @@ type Table struct {
 	Type        string
 	GoName      string
 	SQLName     string
+	Schema      string
 	PrimaryKeys []Field
 	Fields      []Field
 	Manual      bool
@@ -2331,26 +3677,72 @@ type ForeignKey struct {
 	Comment   string
 }
 
+// ReverseForeignKey is a reverse foreign key template, retrieving the rows of
+// a table referring to the ref table.
+type ReverseForeignKey struct {
+	GoName    string
+	SQLName   string
+	RefTable  Table
+	RefFields []Field
+	Index     Index
+	Comment   string
+}
+
 // Index is an index template.
 type Index struct {
 	SQLName   string
 	Func      string
 	Table     Table
 	Fields    []Field
+	Exprs     []string
+	Predicate string
 	IsUnique  bool
 	IsPrimary bool
 	Comment   string
 }
 
+// DB is the db template.
+type DB struct {
+	Composites bool
+}
+
+// Domain is a named domain type template.
+type Domain struct {
+	GoName  string
+	SQLName string
+	Schema  string
+	Type    string
+	Alias   bool
+	Base    xo.Type
+	Comment string
+}
+
+// Composite is a composite type template.
+type Composite struct {
+	GoName  string
+	SQLName string
+	Schema  string
+	Fields  []Field
+	Comment string
+}
+
+// Refresh is a materialized view refresh template.
+type Refresh struct {
+	GoName string
+	Table  Table
+}
+
 // Field is a field template.
 type Field struct {
-	GoName     string
-	SQLName    string
-	Type       string
-	Zero       string
-	IsPrimary  bool
-	IsSequence bool
-	Comment    string
+	GoName      string
+	SQLName     string
+	Type        string
+	Zero        string
+	Default     string
+	IsPrimary   bool
+	IsSequence  bool
+	IsGenerated bool
+	Comment     string
 }
 
 // QueryParam is a custom query parameter template.
@@ -2689,11 +4081,7 @@ func addLegacyFuncs(ctx context.Context, funcs template.FuncMap) {
 		if f.Type == t.Type {
 			return expr
 		}
-		ft := f.Type
-		if strings.HasPrefix(ft, "sql.Null") {
-			expr = expr + "." + f.Type[8:]
-			ft = strings.ToLower(f.Type[8:])
-		}
+		expr, ft := nullValue(expr, f.Type)
 		if t.Type != ft {
 			expr = t.Type + "(" + expr + ")"
 		}
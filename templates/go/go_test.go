@@ -0,0 +1,291 @@
+//go:build xotpl
+
+package gotpl
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	xo "github.com/xo/xo/types"
+)
+
+func TestParseGoType(t *testing.T) {
+	tests := []struct {
+		s   string
+		typ string
+		pkg string
+	}{
+		{"string", "string", ""},
+		{"json.RawMessage", "json.RawMessage", ""},
+		{"encoding/json.RawMessage", "json.RawMessage", "encoding/json"},
+		{"*github.com/acme/types.Settings", "*types.Settings", "github.com/acme/types"},
+		{"[]github.com/acme/types.Tag", "[]types.Tag", "github.com/acme/types"},
+		{"gopkg.in/yaml.v3.Node", "yaml.Node", "gopkg.in/yaml.v3"},
+		{"example.com/x.y/pkg.T", "pkg.T", "example.com/x.y/pkg"},
+		{"github.com/jackc/pgx/v5/pgtype.Numeric", "pgtype.Numeric", "github.com/jackc/pgx/v5/pgtype"},
+		{"github.com/gofrs/uuid/v5.UUID", "uuid.UUID", "github.com/gofrs/uuid/v5"},
+		{"database/sql.Null[github.com/acme/types.T]", "sql.Null[github.com/acme/types.T]", "database/sql"},
+	}
+	for i, test := range tests {
+		typ, pkg := parseGoType(test.s)
+		if typ != test.typ || pkg != test.pkg {
+			t.Errorf("test %d expected %q, %q, got: %q, %q", i, test.typ, test.pkg, typ, pkg)
+		}
+	}
+}
+
+func TestTypeOverrides(t *testing.T) {
+	tests := []struct {
+		mode string
+		s    string
+		key  string
+		exp  TypeOverride
+		err  string
+	}{
+		{"", "jsonb=encoding/json.RawMessage", "jsonb", TypeOverride{
+			Type:         "json.RawMessage",
+			Nullable:     "json.RawMessage",
+			Zero:         "json.RawMessage{}",
+			NullableZero: "json.RawMessage{}",
+			Imports:      []string{"encoding/json"},
+		}, ""},
+		{"pointer", "users.settings=github.com/acme/types.Settings", "users.settings", TypeOverride{
+			Type:         "types.Settings",
+			Nullable:     "*types.Settings",
+			Zero:         "types.Settings{}",
+			NullableZero: "nil",
+			Imports:      []string{"github.com/acme/types"},
+		}, ""},
+		{"generic", "money=int64", "money", TypeOverride{
+			Type:         "int64",
+			Nullable:     "sql.Null[int64]",
+			Zero:         "0",
+			NullableZero: "sql.Null[int64]{}",
+		}, ""},
+		{"", "users.settings = github.com/acme/types.Settings;*gopkg.in/yaml.v3.Node", "users.settings", TypeOverride{
+			Type:         "types.Settings",
+			Nullable:     "*yaml.Node",
+			Zero:         "types.Settings{}",
+			NullableZero: "nil",
+			Imports:      []string{"github.com/acme/types", "gopkg.in/yaml.v3"},
+		}, ""},
+		{"", "point=geo.Point;;geo.Origin", "point", TypeOverride{
+			Type:         "geo.Point",
+			Nullable:     "geo.Point",
+			Zero:         "geo.Origin",
+			NullableZero: "geo.Origin",
+		}, ""},
+		{"", "jsonb", "", TypeOverride{}, `invalid type override "jsonb"`},
+		{"", "=string", "", TypeOverride{}, `invalid type override "=string"`},
+		{"", "jsonb=", "", TypeOverride{}, `invalid type override "jsonb="`},
+		{"", "jsonb= ;string", "", TypeOverride{}, `invalid type override "jsonb= ;string"`},
+		{"", "jsonb=a;b;c;d", "", TypeOverride{}, `invalid type override "jsonb=a;b;c;d"`},
+	}
+	for i, test := range tests {
+		ctx := context.WithValue(context.Background(), NullModeKey, test.mode)
+		ctx = context.WithValue(ctx, TypeKey, []string{test.s})
+		overrides, err := TypeOverrides(ctx)
+		switch {
+		case test.err != "" && err == nil:
+			t.Errorf("test %d expected error %q", i, test.err)
+			continue
+		case test.err != "" && err.Error() != test.err:
+			t.Errorf("test %d expected error %q, got: %v", i, test.err, err)
+			continue
+		case test.err != "":
+			continue
+		case err != nil:
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if o, ok := overrides[test.key]; !ok || !reflect.DeepEqual(o, test.exp) {
+			t.Errorf("test %d expected %+v, got: %+v", i, test.exp, o)
+		}
+	}
+}
+
+func TestGoTypeNullMode(t *testing.T) {
+	tests := []struct {
+		driver string
+		mode   string
+		typ    string
+		exp    string
+		zero   string
+	}{
+		{"sqlite3", "sql", "text", "sql.NullString", "sql.NullString{}"},
+		{"sqlite3", "pointer", "text", "*string", "nil"},
+		{"sqlite3", "generic", "text", "sql.Null[string]", "sql.Null[string]{}"},
+		{"sqlite3", "pointer", "integer", "*int", "nil"},
+		{"sqlite3", "generic", "integer", "sql.Null[int]", "sql.Null[int]{}"},
+		{"sqlite3", "pointer", "blob", "[]byte", "nil"},
+		{"sqlite3", "generic", "blob", "[]byte", "nil"},
+		{"postgres", "pointer", "uuid", "*uuid.UUID", "nil"},
+		{"postgres", "generic", "timestamp with time zone", "sql.Null[time.Time]", "sql.Null[time.Time]{}"},
+		{"mysql", "pointer", "double", "*float64", "nil"},
+	}
+	for i, test := range tests {
+		ctx := context.WithValue(context.Background(), xo.DriverKey, test.driver)
+		ctx = context.WithValue(ctx, NullModeKey, test.mode)
+		ctx = context.WithValue(ctx, Int32Key, "int")
+		ctx = context.WithValue(ctx, Uint32Key, "uint")
+		typ, zero, err := goType(ctx, xo.Type{Type: test.typ, Nullable: true})
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if typ != test.exp || zero != test.zero {
+			t.Errorf("test %d expected %q, %q, got: %q, %q", i, test.exp, test.zero, typ, zero)
+		}
+		// non-nullable types are not affected by the mode
+		typ, _, err = goType(ctx, xo.Type{Type: test.typ})
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "sql.Null") {
+			t.Errorf("test %d expected non-nullable type, got: %q", i, typ)
+		}
+	}
+}
+
+func TestNullValue(t *testing.T) {
+	tests := []struct {
+		typ  string
+		expr string
+		exp  string
+	}{
+		{"int64", "b.AuthorID", "int64"},
+		{"sql.NullInt64", "b.AuthorID.Int64", "int64"},
+		{"sql.Null[int]", "b.AuthorID.V", "int"},
+		{"*int", "deref(b.AuthorID)", "int"},
+		{"*uuid.UUID", "deref(b.AuthorID)", "uuid.uuid"},
+	}
+	for i, test := range tests {
+		expr, typ := nullValue("b.AuthorID", test.typ)
+		if expr != test.expr || typ != test.exp {
+			t.Errorf("test %d expected %q, %q, got: %q, %q", i, test.expr, test.exp, expr, typ)
+		}
+	}
+}
+
+func TestTypefn(t *testing.T) {
+	f := &Funcs{
+		custom:     "custom",
+		knownTypes: map[string]bool{"int": true, "string": true},
+	}
+	tests := []struct {
+		typ string
+		exp string
+	}{
+		{"int", "int"},
+		{"Status", "custom.Status"},
+		{"*Status", "*custom.Status"},
+		{"[]*Status", "[]*custom.Status"},
+		{"sql.Null[Status]", "sql.Null[custom.Status]"},
+		{"sql.Null[int]", "sql.Null[int]"},
+		{"sql.NullString", "sql.NullString"},
+	}
+	for i, test := range tests {
+		if s := f.typefn(test.typ); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestSchemaPrefix(t *testing.T) {
+	ctx := context.WithValue(context.Background(), xo.DriverKey, "postgres")
+	ctx = context.WithValue(ctx, xo.SchemaKey, "public")
+	ctx = context.WithValue(ctx, Int32Key, "int")
+	ctx = context.WithValue(ctx, Uint32Key, "uint")
+	tests := []struct {
+		schema string
+		exp    string
+	}{
+		{"", ""},
+		{"public", ""},
+		{"lib", "lib_"},
+		{"lib_archive", "lib_archive_"},
+	}
+	for i, test := range tests {
+		if s := schemaPrefix(ctx, test.schema); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	authorID := xo.Field{Name: "author_id", Type: xo.Type{Type: "integer"}}
+	table, err := convertTable(ctx, "lib", xo.Table{
+		Type:    "table",
+		Name:    "books",
+		Columns: []xo.Field{authorID},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := "LibBook"; table.GoName != exp {
+		t.Errorf("expected %q, got: %q", exp, table.GoName)
+	}
+	fkeys := []struct {
+		refSchema string
+		refTable  string
+		refFunc   string
+	}{
+		{"public", "Author", "AuthorByAuthorID"},
+		{"", "LibAuthor", "LibAuthorByAuthorID"},
+		{"lib", "LibAuthor", "LibAuthorByAuthorID"},
+	}
+	for i, test := range fkeys {
+		fkey, err := convertFKey(ctx, table, xo.ForeignKey{
+			Name:      "books_author_id_fkey",
+			Func:      "author",
+			Fields:    []xo.Field{authorID},
+			RefSchema: test.refSchema,
+			RefTable:  "authors",
+			RefFields: []xo.Field{authorID},
+			RefFunc:   "author_by_author_id",
+		})
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if fkey.RefTable != test.refTable || fkey.RefFunc != test.refFunc {
+			t.Errorf("test %d expected %q, %q, got: %q, %q", i, test.refTable, test.refFunc, fkey.RefTable, fkey.RefFunc)
+		}
+	}
+	// sql names are qualified with the table's schema
+	f := &Funcs{driver: "postgres", schema: "public"}
+	if s, exp := f.schemafn(table), "lib.books"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	if s, exp := f.schemafn("authors"), "public.authors"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}
+
+func TestEmitQueryInferredParams(t *testing.T) {
+	ctx := context.WithValue(context.Background(), xo.DriverKey, "postgres")
+	ctx = context.WithValue(ctx, Int32Key, "int")
+	ctx = context.WithValue(ctx, Uint32Key, "uint")
+	query := xo.Query{
+		Name:  "DeleteAuthors",
+		Exec:  true,
+		Query: []string{"DELETE FROM authors WHERE author_id = $1 AND name = $2 AND kind = $3 AND created_at > $4"},
+		Params: []xo.Field{
+			{Name: "authorID", Type: xo.Type{Type: "integer"}, Inferred: true},
+			{Name: "name", Type: xo.Type{Type: "string"}},
+			{Name: "kind", Type: xo.Type{Type: "author_kind"}, Inferred: true},
+			{Name: "createdAt", Type: xo.Type{Type: "timestamp with time zone"}, Inferred: true},
+		},
+	}
+	var params []QueryParam
+	if err := emitQuery(ctx, query, make(map[string]Table), func(tpl xo.Template) {
+		if q, ok := tpl.Data.(Query); ok {
+			params = q.Params
+		}
+	}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var v []string
+	for _, p := range params {
+		v = append(v, p.Name+" "+p.Type)
+	}
+	if s, exp := strings.Join(v, ", "), "authorID int, name string, kind AuthorKind, createdAt time.Time"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}
@@ -17,17 +17,28 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
-{{- if driver "postgres" }}
+{{- if pgx }}
+	"net/netip"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"{{ uuid }}"
+{{ else if driver "postgres" }}
 	"github.com/lib/pq"
 	"github.com/lib/pq/hstore"
-{{ end }}{{ range imports }}
+{{ else if driver "duckdb" }}
+	"github.com/marcboeker/go-duckdb"
+{{ end }}{{ range imports .Data }}
 	{{ with .Alias }}{{ . }} {{ end }}{{ .Pkg }}
 {{ end }}
 )
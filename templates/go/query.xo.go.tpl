@@ -3,7 +3,7 @@
 {{- if $q.Comment -}}
 // {{ $q.Comment | eval (func_name_context $q) }}
 {{- else -}}
-// {{ func_name_context $q }} runs a custom query{{ if $q.Exec }} as a [sql.Result]{{ else if not $q.Flat }}, returning results as [{{ $q.Type.GoName }}]{{ end }}.
+// {{ func_name_context $q }} runs a custom query{{ if $q.Exec }} as a [{{ result_type }}]{{ else if not $q.Flat }}, returning results as [{{ $q.Type.GoName }}]{{ end }}.
 {{- end }}
 {{ func_context $q }} {
 	// query
@@ -26,6 +26,24 @@
 		return nil, logerror(err)
 	}
 	return &{{ short $q.Type }}, nil
+{{- else if pgx -}}
+	rows, err := {{ db "Query" $q }}
+	if err != nil {
+		return nil, logerror(err)
+	}
+	// load results
+	res, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*{{ type $q.Type.GoName }}, error) {
+		var {{ short $q.Type}} {{ type $q.Type.GoName }}
+		// scan
+		if err := row.Scan({{ names (print "&" (short $q.Type) ".") $q.Type.Fields }}); err != nil {
+			return nil, err
+		}
+		return &{{ short $q.Type }}, nil
+	})
+	if err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
 {{- else -}}
 	rows, err := {{ db "Query" $q }}
 	if err != nil {
@@ -53,7 +71,7 @@
 {{- if $q.Comment -}}
 // {{ $q.Comment | eval (func_name $q) }}
 {{- else -}}
-// {{ func_name $q }} runs a custom query{{ if $q.Exec }} as a [sql.Result]{{ else if not $q.Flat }}, returning results as [{{ $q.Type.GoName }}]{{ end }}.
+// {{ func_name $q }} runs a custom query{{ if $q.Exec }} as a [{{ result_type }}]{{ else if not $q.Flat }}, returning results as [{{ $q.Type.GoName }}]{{ end }}.
 {{- end }}
 {{ func $q }} {
 	return {{ func_name_context $q }}({{ names_all "" "context.Background()" "db" $q }})
@@ -1,6 +1,6 @@
 {{ define "enum" }}
 {{- $e := .Data -}}
-// {{ $e.GoName }} is the '{{ $e.SQLName }}' enum type from schema '{{ schema }}'.
+// {{ $e.GoName }} is the '{{ $e.SQLName }}' enum type from schema '{{ $e.Schema }}'.
 type {{ $e.GoName }} uint16
 
 // {{ $e.GoName }} values.
@@ -58,7 +58,7 @@ func ({{ short $e.GoName }} *{{ $e.GoName }}) Scan(v interface{}) error {
 
 {{ $nullName := (printf "%s%s" "Null" $e.GoName) -}}
 {{- $nullShort := (short $nullName) -}}
-// {{ $nullName }} represents a null '{{ $e.SQLName }}' enum for schema '{{ schema }}'.
+// {{ $nullName }} represents a null '{{ $e.SQLName }}' enum for schema '{{ $e.Schema }}'.
 type {{ $nullName }} struct {
 	{{ $e.GoName }} {{ $e.GoName }}
 	// Valid is true if [{{ $e.GoName }}] is not null.
@@ -93,6 +93,68 @@ func (err ErrInvalid{{ $e.GoName }}) Error() string {
 }
 {{ end }}
 
+{{ define "domain" }}
+{{- $d := .Data -}}
+{{- if $d.Comment -}}
+// {{ $d.Comment | eval $d.GoName }}
+{{- else -}}
+// {{ $d.GoName }} is the '{{ $d.SQLName }}' domain type from schema '{{ $d.Schema }}'.
+{{- end }}
+type {{ $d.GoName }} {{ if $d.Alias }}= {{ end }}{{ $d.Type }}
+{{ end }}
+
+{{ define "composite" }}
+{{- $c := .Data -}}
+{{- if $c.Comment -}}
+// {{ $c.Comment | eval $c.GoName }}
+{{- else -}}
+// {{ $c.GoName }} is the '{{ $c.SQLName }}' composite type from schema '{{ $c.Schema }}'.
+{{- end }}
+type {{ $c.GoName }} struct {
+{{ range $c.Fields -}}
+	{{ field . }}
+{{ end -}}
+}
+
+// Value satisfies the [driver.Valuer] interface.
+func ({{ short $c.GoName }} {{ $c.GoName }}) Value() (driver.Value, error) {
+	return compositeValue({{ names (print (short $c.GoName) ".") $c.Fields }})
+}
+
+// Scan satisfies the [sql.Scanner] interface.
+func ({{ short $c.GoName }} *{{ $c.GoName }}) Scan(v interface{}) error {
+	return scanComposite(v, {{ names (print "&" (short $c.GoName) ".") $c.Fields }})
+}
+
+{{ $nullName := (printf "%s%s" "Null" $c.GoName) -}}
+{{- $nullShort := (short $nullName) -}}
+// {{ $nullName }} represents a null '{{ $c.SQLName }}' composite type for schema '{{ $c.Schema }}'.
+type {{ $nullName }} struct {
+	{{ $c.GoName }} {{ $c.GoName }}
+	// Valid is true if [{{ $c.GoName }}] is not null.
+	Valid bool
+}
+
+// Value satisfies the [driver.Valuer] interface.
+func ({{ $nullShort }} {{ $nullName }}) Value() (driver.Value, error) {
+	if !{{ $nullShort }}.Valid {
+		return nil, nil
+	}
+	return {{ $nullShort }}.{{ $c.GoName }}.Value()
+}
+
+// Scan satisfies the [sql.Scanner] interface.
+func ({{ $nullShort }} *{{ $nullName }}) Scan(v interface{}) error {
+	if v == nil {
+		{{ $nullShort }}.{{ $c.GoName }}, {{ $nullShort }}.Valid = {{ $c.GoName }}{}, false
+		return nil
+	}
+	err := {{ $nullShort }}.{{ $c.GoName }}.Scan(v)
+	{{ $nullShort }}.Valid = err == nil
+	return err
+}
+{{ end }}
+
 {{ define "foreignkey" }}
 {{- $k := .Data -}}
 // {{ func_name_context $k }} returns the {{ $k.RefTable }} associated with the [{{ $k.Table.GoName }}]'s ({{ names "" $k.Fields }}).
@@ -112,9 +174,110 @@ func (err ErrInvalid{{ $e.GoName }}) Error() string {
 {{- end }}
 {{ end }}
 
+{{ define "reverse_foreignkey" }}
+{{- $k := .Data -}}
+// {{ func_name_context $k }} returns the [{{ $k.Index.Table.GoName }}] rows referring to the [{{ $k.RefTable.GoName }}]'s ({{ names "" $k.RefFields }}).
+//
+// Generated from foreign key '{{ $k.SQLName }}'.
+{{ recv_context $k.RefTable $k }} {
+{{- if $k.Index.Func }}
+	return {{ reverse_key_context $k }}
+{{- else }}
+	// query
+	{{ sqlstr "index" $k.Index }}
+	// run
+	logf(sqlstr, {{ reverse_params $k }})
+{{- if pgx }}
+	rows, err := {{ db "Query" (reverse_params $k) }}
+	if err != nil {
+		return nil, logerror(err)
+	}
+	// process
+	res, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*{{ $k.Index.Table.GoName }}, error) {
+		{{ short $k.Index.Table }} := {{ $k.Index.Table.GoName }}{
+		{{- if $k.Index.Table.PrimaryKeys }}
+			_exists: true,
+		{{ end -}}
+		}
+		// scan
+		if err := row.Scan({{ names_ignore (print "&" (short $k.Index.Table) ".") $k.Index.Table }}); err != nil {
+			return nil, err
+		}
+		return &{{ short $k.Index.Table }}, nil
+	})
+	if err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+{{- else }}
+	rows, err := {{ db "Query" (reverse_params $k) }}
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer rows.Close()
+	// process
+	var res []*{{ $k.Index.Table.GoName }}
+	for rows.Next() {
+		{{ short $k.Index.Table }} := {{ $k.Index.Table.GoName }}{
+		{{- if $k.Index.Table.PrimaryKeys }}
+			_exists: true,
+		{{ end -}}
+		}
+		// scan
+		if err := rows.Scan({{ names_ignore (print "&" (short $k.Index.Table) ".") $k.Index.Table }}); err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &{{ short $k.Index.Table }})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+{{- end }}
+{{- end }}
+}
+{{- if context_both }}
+
+// {{ func_name $k }} returns the [{{ $k.Index.Table.GoName }}] rows referring to the [{{ $k.RefTable.GoName }}]'s ({{ names "" $k.RefFields }}).
+//
+// Generated from foreign key '{{ $k.SQLName }}'.
+{{ recv $k.RefTable $k }} {
+	return {{ foreign_key $k }}
+}
+{{- end }}
+{{ end }}
+
+{{ define "refresh" }}
+{{- $r := .Data -}}
+// {{ func_name_context $r }} refreshes the '{{ schema $r.Table }}' materialized view.
+// Refreshing concurrently does not lock out concurrent selects, but requires a
+// unique index on the view.
+{{ func_context $r }} {
+	// query
+	sqlstr := `REFRESH MATERIALIZED VIEW `
+	if concurrently {
+		sqlstr += `CONCURRENTLY `
+	}
+	sqlstr += `{{ schema $r.Table }}`
+	// run
+	logf(sqlstr)
+	if _, err := {{ db "Exec" }}; err != nil {
+		return logerror(err)
+	}
+	return nil
+}
+{{- if context_both }}
+
+// {{ func_name $r }} refreshes the '{{ schema $r.Table }}' materialized view.
+{{ func $r }} {
+	return {{ func_name_context $r }}(context.Background(), db, concurrently)
+}
+{{- end }}
+{{ end }}
+
 {{ define "index" }}
 {{- $i := .Data -}}
-// {{ func_name_context $i }} retrieves a row from '{{ schema $i.Table.SQLName }}' as a [{{ $i.Table.GoName }}].
+// {{ func_name_context $i }} retrieves a row from '{{ schema $i.Table }}' as a [{{ $i.Table.GoName }}].
 //
 // Generated from index '{{ $i.SQLName }}'.
 {{ func_context $i }} {
@@ -132,6 +295,28 @@ func (err ErrInvalid{{ $e.GoName }}) Error() string {
 		return nil, logerror(err)
 	}
 	return &{{ short $i.Table }}, nil
+{{- else if pgx }}
+	rows, err := {{ db "Query" $i }}
+	if err != nil {
+		return nil, logerror(err)
+	}
+	// process
+	res, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*{{ $i.Table.GoName }}, error) {
+		{{ short $i.Table }} := {{ $i.Table.GoName }}{
+		{{- if $i.Table.PrimaryKeys }}
+			_exists: true,
+		{{ end -}}
+		}
+		// scan
+		if err := row.Scan({{ names_ignore (print "&" (short $i.Table) ".")  $i.Table }}); err != nil {
+			return nil, err
+		}
+		return &{{ short $i.Table }}, nil
+	})
+	if err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
 {{- else }}
 	rows, err := {{ db "Query" $i }}
 	if err != nil {
@@ -160,7 +345,7 @@ func (err ErrInvalid{{ $e.GoName }}) Error() string {
 }
 
 {{ if context_both -}}
-// {{ func_name $i }} retrieves a row from '{{ schema $i.Table.SQLName }}' as a [{{ $i.Table.GoName }}].
+// {{ func_name $i }} retrieves a row from '{{ schema $i.Table }}' as a [{{ $i.Table.GoName }}].
 //
 // Generated from index '{{ $i.SQLName }}'.
 {{ func $i }} {
@@ -180,7 +365,7 @@ func (err ErrInvalid{{ $e.GoName }}) Error() string {
 	// with out parameters
 	return {{ zero $p.Returns }}, fmt.Errorf("unsupported")
 {{- else }}
-	// call {{ schema $p.SQLName }}
+	// call {{ schema $p }}
 	{{ sqlstr "proc" $p }}
 	// run
 {{- if not $p.Void }}
@@ -224,7 +409,7 @@ func (err ErrInvalid{{ $e.GoName }}) Error() string {
 {{- if $t.Comment -}}
 // {{ $t.Comment | eval $t.GoName }}
 {{- else -}}
-// {{ $t.GoName }} represents a row from '{{ schema $t.SQLName }}'.
+// {{ $t.GoName }} represents a row from '{{ schema $t }}'.
 {{- end }}
 type {{ $t.GoName }} struct {
 {{ range $t.Fields -}}
@@ -256,21 +441,42 @@ func ({{ short $t }} *{{ $t.GoName }}) Deleted() bool {
 	case {{ short $t }}._deleted: // deleted
 		return logerror(&ErrInsertFailed{ErrMarkedForDeletion})
 	}
-{{ if $t.Manual -}}
+{{ if default_fields $t -}}
+	// insert (zero values of fields having a default are omitted, and the
+	// values set by the database are returned)
+	{{ sqlstr_defaults $t }}
+	// run
+	logf(sqlstr, args...)
+{{ if driver "oracle" -}}
+	if _, err := {{ db "Exec" "args..." }}; err != nil {
+		return logerror(err)
+	}
+{{- else -}}
+	if err := {{ db "QueryRow" "args..." }}.Scan({{ names (print "&" (short $t) ".") (returning "insert_defaults" $t) }}); err != nil {
+		return logerror(err)
+	}
+{{- end }}
+{{- else if $t.Manual -}}
 	// insert (manual)
 	{{ sqlstr "insert_manual" $t }}
 	// run
 	{{ logf $t }}
+{{ if returning "insert_manual" $t -}}
+	if err := {{ db_prefix "QueryRow" false $t }}.Scan({{ names (print "&" (short $t) ".") (returning "insert_manual" $t) }}); err != nil {
+		return logerror(err)
+	}
+{{- else -}}
 	if _, err := {{ db_prefix "Exec" false $t }}; err != nil {
 		return logerror(err)
 	}
+{{- end }}
 {{- else -}}
 	// insert (primary key generated and returned by database)
 	{{ sqlstr "insert" $t }}
 	// run
 	{{ logf $t $t.PrimaryKeys }}
-{{ if (driver "postgres") -}}
-	if err := {{ db_prefix "QueryRow" true $t }}.Scan(&{{ short $t }}.{{ (index $t.PrimaryKeys 0).GoName }}); err != nil {
+{{ if returning "insert" $t -}}
+	if err := {{ db_prefix "QueryRow" true $t }}.Scan({{ names (print "&" (short $t) ".") (returning "insert" $t) }}); err != nil {
 		return logerror(err)
 	}
 {{- else if (driver "sqlserver") -}}
@@ -305,7 +511,7 @@ func ({{ short $t }} *{{ $t.GoName }}) Deleted() bool {
 		return logerror(err)
 	}
 {{- end -}}
-{{ if not (driver "postgres") -}}
+{{ if not (returning "insert" $t) -}}
 	// set primary key
 	{{ short $t }}.{{ (index $t.PrimaryKeys 0).GoName }} = {{ (index $t.PrimaryKeys 0).Type }}(id)
 {{- end }}
@@ -322,8 +528,141 @@ func ({{ short $t }} *{{ $t.GoName }}) Deleted() bool {
 }
 {{- end }}
 
+{{ if and (driver "postgres" "mysql" "sqlite3" "duckdb") (ne (insert_count $t.Manual $t) 0) -}}
+{{- $s := short $t -}}
+{{- $name := print "InsertMany" (plural $t.GoName) -}}
+{{ if default_fields $t -}}
+// {{ func_name_context $name }} inserts multiple [{{ $t.GoName }}] to the database, one at a time.
+{{- else -}}
+// {{ func_name_context $name }} inserts multiple [{{ $t.GoName }}] to the database, in batches
+// limited by the maximum number of query parameters.
+{{- end }}
+func {{ func_name_context $name }}({{ if context }}ctx context.Context, {{ end }}db DB, {{ $s }}s []*{{ $t.GoName }}) error {
+	for _, {{ $s }} := range {{ $s }}s {
+		switch {
+		case {{ $s }}._exists: // already exists
+			return logerror(&ErrInsertFailed{ErrAlreadyExists})
+		case {{ $s }}._deleted: // deleted
+			return logerror(&ErrInsertFailed{ErrMarkedForDeletion})
+		}
+	}
+{{ if default_fields $t -}}
+	// insert each row, as the inserted columns depend on the zero values of
+	// fields having a default
+	for _, {{ $s }} := range {{ $s }}s {
+		if err := {{ $s }}.{{ func_name_context "Insert" }}({{ if context }}ctx, {{ end }}db); err != nil {
+			return err
+		}
+	}
+{{- else if and $t.Manual copy -}}
+	// insert (manual) using copy
+	logf("COPY {{ schema $t }} FROM STDIN")
+	if _, err := db.CopyFrom({{ if context }}ctx{{ else }}context.Background(){{ end }}, {{ copy_table $t }}, pgx.CopyFromSlice(len({{ $s }}s), func(i int) ([]interface{}, error) {
+		{{ $s }} := {{ $s }}s[i]
+		return []interface{}{ {{- args_many true $t -}} }, nil
+	})); err != nil {
+		return logerror(err)
+	}
+	// set exists
+	for _, {{ $s }} := range {{ $s }}s {
+		{{ $s }}._exists = true
+	}
+{{- else -}}
+{{- $ret := "insert" -}}
+{{- if $t.Manual }}{{ $ret = "insert_manual" }}{{ end -}}
+	ranges, err := batches(len({{ $s }}s), {{ insert_count $t.Manual $t }})
+	if err != nil {
+		return logerror(&ErrInsertFailed{err})
+	}
+	for _, b := range ranges {
+		batch := {{ $s }}s[b[0]:b[1]]
+{{- if $t.Manual }}
+		// insert (manual)
+		{{ sqlstr_many "insert_manual" $t }}
+{{- else }}
+		// insert (primary key generated and returned by database)
+		{{ sqlstr_many "insert" $t }}
+{{- end }}
+		args := make([]interface{}, 0, len(batch)*{{ insert_count $t.Manual $t }})
+		for _, {{ $s }} := range batch {
+			args = append(args, {{ args_many $t.Manual $t }})
+		}
+		// run
+		logf(sqlstr, args...)
+{{ if returning $ret $t -}}
+		rows, err := {{ db "Query" "args..." }}
+		if err != nil {
+			return logerror(err)
+		}
+		// set returned fields and exists
+		var n int
+		for ; rows.Next(); n++ {
+			if n == len(batch) {
+				rows.Close()
+				return logerror(&ErrInsertFailed{ErrRowCount})
+			}
+			{{ $s }} := batch[n]
+			if err := rows.Scan({{ names (print "&" $s ".") (returning $ret $t) }}); err != nil {
+				rows.Close()
+				return logerror(err)
+			}
+			{{ $s }}._exists = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return logerror(err)
+		}
+		if n != len(batch) {
+			return logerror(&ErrInsertFailed{ErrRowCount})
+		}
+{{- else if $t.Manual -}}
+		if _, err := {{ db "Exec" "args..." }}; err != nil {
+			return logerror(err)
+		}
+		// set exists
+		for _, {{ $s }} := range batch {
+			{{ $s }}._exists = true
+		}
+{{- else -}}
+		res, err := {{ db "Exec" "args..." }}
+		if err != nil {
+			return logerror(err)
+		}
+		// retrieve id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return logerror(err)
+		}
+{{- if driver "sqlite3" }}
+		// sqlite3 returns the id of the last row
+		id -= int64(len(batch) - 1)
+{{- end }}
+		// set primary keys and exists, as the ids of a multi-row insert are
+		// consecutive{{ if driver "mysql" }} (only when auto_increment_increment is 1){{ end }}
+		for _, {{ $s }} := range batch {
+			{{ $s }}.{{ (index $t.PrimaryKeys 0).GoName }} = {{ (index $t.PrimaryKeys 0).Type }}(id)
+			{{ $s }}._exists = true
+			id++
+		}
+{{- end }}
+	}
+{{- end }}
+	return nil
+}
+
+{{ if context_both -}}
+// {{ $name }} inserts multiple [{{ $t.GoName }}] to the database, in batches
+// limited by the maximum number of query parameters.
+func {{ $name }}(db DB, {{ $s }}s []*{{ $t.GoName }}) error {
+	return {{ $name }}Context(context.Background(), db, {{ $s }}s)
+}
+{{- end }}
+{{- else if not (driver "postgres" "mysql" "sqlite3" "duckdb") -}}
+// ------ NOTE: InsertMany statements omitted due to lack of multi-row insert support ------
+{{- end }}
+
 
-{{ if eq (len $t.Fields) (len $t.PrimaryKeys) -}}
+{{ if eq (insert_count true $t) (len $t.PrimaryKeys) -}}
 // ------ NOTE: Update statements omitted due to lack of fields other than primary key ------
 {{- else -}}
 // {{ func_name_context "Update" }} updates a [{{ $t.GoName }}] in the database.
@@ -338,9 +677,15 @@ func ({{ short $t }} *{{ $t.GoName }}) Deleted() bool {
 	{{ sqlstr "update" $t }}
 	// run
 	{{ logf_update $t }}
+{{ if returning "update" $t -}}
+	if err := {{ db_update "QueryRow" $t }}.Scan({{ names (print "&" (short $t) ".") (returning "update" $t) }}); err != nil {
+		return logerror(err)
+	}
+{{- else -}}
 	if _, err := {{ db_update "Exec" $t }}; err != nil {
 		return logerror(err)
 	}
+{{- end }}
 	return nil
 }
 
@@ -379,9 +724,15 @@ func ({{ short $t }} *{{ $t.GoName }}) Deleted() bool {
 	{{ sqlstr "upsert" $t }}
 	// run
 	{{ logf $t }}
+{{ if returning "upsert" $t -}}
+	if err := {{ db_prefix "QueryRow" false $t }}.Scan({{ names (print "&" (short $t) ".") (returning "upsert" $t) }}); err != nil {
+		return logerror(err)
+	}
+{{- else -}}
 	if _, err := {{ db_prefix "Exec" false $t }}; err != nil {
 		return logerror(err)
 	}
+{{- end }}
 	// set exists
 	{{ short $t }}._exists = true
 	return nil
@@ -393,6 +744,80 @@ func ({{ short $t }} *{{ $t.GoName }}) Deleted() bool {
 	return {{ short $t }}.UpsertContext(context.Background(), db)
 }
 {{- end -}}
+
+{{ if driver "postgres" "mysql" "sqlite3" "duckdb" }}
+{{ $s := short $t -}}
+{{- $name := print "UpsertMany" (plural $t.GoName) -}}
+// {{ func_name_context $name }} performs an upsert for multiple [{{ $t.GoName }}], in
+// batches limited by the maximum number of query parameters.
+func {{ func_name_context $name }}({{ if context }}ctx context.Context, {{ end }}db DB, {{ $s }}s []*{{ $t.GoName }}) error {
+	for _, {{ $s }} := range {{ $s }}s {
+		switch {
+		case {{ $s }}._deleted: // deleted
+			return logerror(&ErrUpsertFailed{ErrMarkedForDeletion})
+		}
+	}
+	ranges, err := batches(len({{ $s }}s), {{ insert_count true $t }})
+	if err != nil {
+		return logerror(&ErrUpsertFailed{err})
+	}
+	for _, b := range ranges {
+		batch := {{ $s }}s[b[0]:b[1]]
+		// upsert
+		{{ sqlstr_many "upsert" $t }}
+		args := make([]interface{}, 0, len(batch)*{{ insert_count true $t }})
+		for _, {{ $s }} := range batch {
+			args = append(args, {{ args_many true $t }})
+		}
+		// run
+		logf(sqlstr, args...)
+{{ if returning "upsert" $t -}}
+		rows, err := {{ db "Query" "args..." }}
+		if err != nil {
+			return logerror(err)
+		}
+		// set returned fields and exists
+		var n int
+		for ; rows.Next(); n++ {
+			if n == len(batch) {
+				rows.Close()
+				return logerror(&ErrUpsertFailed{ErrRowCount})
+			}
+			{{ $s }} := batch[n]
+			if err := rows.Scan({{ names (print "&" $s ".") (returning "upsert" $t) }}); err != nil {
+				rows.Close()
+				return logerror(err)
+			}
+			{{ $s }}._exists = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return logerror(err)
+		}
+		if n != len(batch) {
+			return logerror(&ErrUpsertFailed{ErrRowCount})
+		}
+{{- else -}}
+		if _, err := {{ db "Exec" "args..." }}; err != nil {
+			return logerror(err)
+		}
+		// set exists
+		for _, {{ $s }} := range batch {
+			{{ $s }}._exists = true
+		}
+{{- end }}
+	}
+	return nil
+}
+
+{{ if context_both -}}
+// {{ $name }} performs an upsert for multiple [{{ $t.GoName }}], in
+// batches limited by the maximum number of query parameters.
+func {{ $name }}(db DB, {{ $s }}s []*{{ $t.GoName }}) error {
+	return {{ $name }}Context(context.Background(), db, {{ $s }}s)
+}
+{{- end -}}
+{{- end -}}
 {{- end }}
 
 // {{ func_name_context "Delete" }} deletes the [{{ $t.GoName }}] from the database.
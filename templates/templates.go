@@ -5,6 +5,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -22,7 +23,7 @@ import (
 // Set holds a set of templates and handles generating files for a target
 // files.
 //
-// Set should not be used more than once to output its contents.
+// Reset must be called before using a Set to output its contents again.
 type Set struct {
 	symbols  map[string]map[string]reflect.Value
 	initfunc string
@@ -359,6 +360,27 @@ func (ts *Set) Dump(out string) {
 	}
 }
 
+// Write writes the generated files to w.
+func (ts *Set) Write(w io.Writer) {
+	var files []string
+	for file := range ts.files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if _, err := w.Write(ts.files[file].Buf.Bytes()); err != nil {
+			ts.files[file].Err = append(ts.files[file].Err, err)
+		}
+	}
+}
+
+// Reset clears the generated files and errors of the set.
+func (ts *Set) Reset() {
+	ts.files = make(map[string]*EmittedTemplate)
+	ts.post = make(map[string][]byte)
+	ts.err = nil
+}
+
 // Errors returns any collected errors.
 func (set *Set) Errors() []error {
 	var files []string
@@ -391,17 +413,9 @@ type Target struct {
 // Uses the template set's symbols, init func name, and declared tags.
 func (ts *Set) NewTemplate(ctx context.Context, target string, src fs.FS, unrestricted bool) (*Target, error) {
 	// build interpreter for custom funcs
-	i := interp.New(interp.Options{
-		GoPath:               ".",
-		BuildTags:            ts.tags,
-		SourcecodeFilesystem: sourceFS{path: "src/main/vendor/" + target, fs: src},
-		Unrestricted:         unrestricted,
-	})
-	// add symbols
-	if ts.symbols != nil {
-		if err := i.Use(ts.symbols); err != nil {
-			return nil, fmt.Errorf("%s: could not add xo internal symbols to yaegi: %w", target, err)
-		}
+	i, err := ts.newInterp(target, src, unrestricted)
+	if err != nil {
+		return nil, err
 	}
 	// import
 	if _, err := i.Eval(fmt.Sprintf("import (xotpl %q)", target)); err != nil {
@@ -436,6 +450,42 @@ func (ts *Set) NewTemplate(ctx context.Context, target string, src fs.FS, unrest
 	}, nil
 }
 
+// AddLoader adds a loader from the provided fs. Creates a
+// github.com/traefik/yaegi interpreter and imports the loader package, whose
+// init func is expected to register its loader with
+// github.com/xo/xo/loader.Register. See the existing loaders for
+// implementation examples.
+//
+// Uses the template set's symbols and declared tags.
+func (ts *Set) AddLoader(ctx context.Context, src fs.FS, unrestricted bool) error {
+	i, err := ts.newInterp("xoloader", src, unrestricted)
+	if err != nil {
+		return err
+	}
+	if _, err := i.Eval(`import _ "xoloader"`); err != nil {
+		return fmt.Errorf("loader: unable to import package: %w", err)
+	}
+	return nil
+}
+
+// newInterp creates a github.com/traefik/yaegi interpreter for the package in
+// src, using the template set's symbols and declared tags.
+func (ts *Set) newInterp(name string, src fs.FS, unrestricted bool) (*interp.Interpreter, error) {
+	i := interp.New(interp.Options{
+		GoPath:               ".",
+		BuildTags:            ts.tags,
+		SourcecodeFilesystem: sourceFS{path: "src/main/vendor/" + name, fs: src},
+		Unrestricted:         unrestricted,
+	})
+	// add symbols
+	if ts.symbols != nil {
+		if err := i.Use(ts.symbols); err != nil {
+			return nil, fmt.Errorf("%s: could not add xo internal symbols to yaegi: %w", name, err)
+		}
+	}
+	return i, nil
+}
+
 // Flags returns the dynamic flags for the template.
 func (target *Target) Flags() []xo.FlagSet {
 	var flags []xo.FlagSet
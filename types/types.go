@@ -35,6 +35,7 @@ type Query struct {
 	Params       []Field  `json:"params,omitempty"`
 	Query        []string `json:"query,omitempty"`
 	Comments     []string `json:"comments,omitempty"`
+	File         string   `json:"file,omitempty"` // source query file, if any
 }
 
 // MarshalYAML satisfies the yaml.Marshaler interface.
@@ -46,12 +47,14 @@ func (q Query) MarshalYAML() (interface{}, error) {
 
 // Schema is a SQL schema.
 type Schema struct {
-	Driver string  `json:"type,omitempty"`
-	Name   string  `json:"name,omitempty"`
-	Enums  []Enum  `json:"enums,omitempty"`
-	Procs  []Proc  `json:"procs,omitempty"`
-	Tables []Table `json:"tables,omitempty"`
-	Views  []Table `json:"views,omitempty"`
+	Driver     string      `json:"type,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Enums      []Enum      `json:"enums,omitempty"`
+	Domains    []Domain    `json:"domains,omitempty"`
+	Composites []Composite `json:"composites,omitempty"`
+	Procs      []Proc      `json:"procs,omitempty"`
+	Tables     []Table     `json:"tables,omitempty"`
+	Views      []Table     `json:"views,omitempty"`
 }
 
 // EnumByName returns a enum by its name.
@@ -70,6 +73,22 @@ type Enum struct {
 	Values []Field `json:"values,omitempty"`
 }
 
+// Domain is a domain type.
+type Domain struct {
+	Name    string  `json:"name,omitempty"`
+	Type    Type    `json:"datatype,omitempty"` // base type, not nullable when the domain is NOT NULL
+	Default string  `json:"default,omitempty"`
+	Checks  []Check `json:"checks,omitempty"`
+	Comment string  `json:"comment,omitempty"`
+}
+
+// Composite is a composite (row) type.
+type Composite struct {
+	Name       string  `json:"name,omitempty"`
+	Attributes []Field `json:"attributes,omitempty"`
+	Comment    string  `json:"comment,omitempty"`
+}
+
 // Proc is a stored procedure.
 type Proc struct {
 	ID         string  `json:"-"`
@@ -90,14 +109,17 @@ func (p Proc) MarshalYAML() (interface{}, error) {
 
 // Table is a table or view.
 type Table struct {
-	Type        string       `json:"type,omitempty"` // 'table' or 'view'
-	Name        string       `json:"name,omitempty"`
-	Columns     []Field      `json:"columns,omitempty"`
-	PrimaryKeys []Field      `json:"primary_keys,omitempty"`
-	Indexes     []Index      `json:"indexes,omitempty"`
-	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"`
-	Manual      bool         `json:"manual,omitempty"`
-	Definition  string       `json:"definition,omitempty"` // empty for tables
+	Type        string            `json:"type,omitempty"` // 'table', 'partitioned table', 'foreign table', 'view', or 'materialized view'
+	Name        string            `json:"name,omitempty"`
+	Columns     []Field           `json:"columns,omitempty"`
+	PrimaryKeys []Field           `json:"primary_keys,omitempty"`
+	Indexes     []Index           `json:"indexes,omitempty"`
+	ForeignKeys []ForeignKey      `json:"foreign_keys,omitempty"`
+	Checks      []Check           `json:"checks,omitempty"`
+	Manual      bool              `json:"manual,omitempty"`
+	Definition  string            `json:"definition,omitempty"` // view definition, partition key, or foreign server and options
+	Comment     string            `json:"comment,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"` // xo:<name>[=<value>] comment annotations
 }
 
 // MarshalYAML satisfies the yaml.Marshaler interface.
@@ -109,34 +131,48 @@ func (t Table) MarshalYAML() (interface{}, error) {
 
 // Index is a index.
 type Index struct {
-	Name      string  `json:"name,omitempty"`
-	Fields    []Field `json:"fields,omitempty"`
-	IsUnique  bool    `json:"is_unique,omitempty"`
-	IsPrimary bool    `json:"is_primary,omitempty"`
-	Func      string  `json:"-"`
+	Name      string   `json:"name,omitempty"`
+	Fields    []Field  `json:"fields,omitempty"`
+	Exprs     []string `json:"exprs,omitempty"`     // expression of each field, empty for plain column references
+	Predicate string   `json:"predicate,omitempty"` // WHERE predicate of a partial index
+	IsUnique  bool     `json:"is_unique,omitempty"`
+	IsPrimary bool     `json:"is_primary,omitempty"`
+	Func      string   `json:"-"`
 }
 
 // ForeignKey is a foreign key.
 type ForeignKey struct {
 	Name      string  `json:"name,omitempty"`       // constraint name
 	Fields    []Field `json:"column,omitempty"`     // column that has the key on it
+	RefSchema string  `json:"ref_schema,omitempty"` // schema of the ref table, when not the same schema
 	RefTable  string  `json:"ref_table,omitempty"`  // table the foreign key refers to
+	RefName   string  `json:"-"`                    // name annotated on the ref table, if any
 	RefFields []Field `json:"ref_column,omitempty"` // column in ref table the index refers to
 	Func      string  `json:"-"`                    // foreign key func name (based on fkey mode)
 	RefFunc   string  `json:"-"`                    // func name from ref index
+	RevFunc   string  `json:"-"`                    // reverse foreign key func name on the ref table (based on fkey mode)
+}
+
+// Check is a check constraint.
+type Check struct {
+	Name string `json:"name,omitempty"` // constraint name
+	Expr string `json:"expr,omitempty"` // check expression
 }
 
 // Field is a column, index, enum value, or stored procedure parameter.
 type Field struct {
-	Name        string `json:"name,omitempty"`
-	Type        Type   `json:"datatype,omitempty"`
-	Default     string `json:"default,omitempty"`
-	IsPrimary   bool   `json:"is_primary,omitempty"`
-	IsSequence  bool   `json:"is_sequence,omitempty"`
-	ConstValue  *int   `json:"const_value,omitempty"`
-	Interpolate bool   `json:"interpolate,omitempty"`
-	Join        bool   `json:"join,omitempty"`
-	Comment     string `json:"comment,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Type        Type              `json:"datatype,omitempty"`
+	Default     string            `json:"default,omitempty"` // default value, or expression when generated
+	IsPrimary   bool              `json:"is_primary,omitempty"`
+	IsSequence  bool              `json:"is_sequence,omitempty"`
+	IsGenerated bool              `json:"is_generated,omitempty"` // generated (computed) column
+	ConstValue  *int              `json:"const_value,omitempty"`
+	Interpolate bool              `json:"interpolate,omitempty"`
+	Join        bool              `json:"join,omitempty"`
+	Inferred    bool              `json:"inferred,omitempty"` // database type inferred for a query param
+	Comment     string            `json:"comment,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"` // xo:<name>[=<value>] comment annotations
 }
 
 // Type holds information for a database type.
@@ -159,26 +195,27 @@ type Type struct {
 //		type(precision)
 //		type(precision, scale)
 //	 type(precision, scale) unsigned
-//		timestamp(n) with [local] time zone (oracle only)
+//		time[stamp](n) with[out] [local] time zone
 //
 // The returned type is stripped of precision and scale.
 func ParseType(typ, driver string) (Type, error) {
-	// special case for oracle timestamp(n) with [local] time zone
-	if m := oracleTimestampRE.FindStringSubmatch(typ); driver == "oracle" && m != nil {
-		prec, err := strconv.Atoi(m[1])
+	// extract is array
+	isArray := false
+	if strings.HasSuffix(typ, "[]") {
+		typ, isArray = typ[:len(typ)-len("[]")], true
+	}
+	// special case for time(n)/timestamp(n) with[out] [local] time zone
+	if m := timeZoneRE.FindStringSubmatch(strings.ToLower(typ)); m != nil {
+		prec, err := strconv.Atoi(m[2])
 		if err != nil {
 			return Type{}, fmt.Errorf("could not parse precision: %w", err)
 		}
 		return Type{
-			Type: "timestamp " + m[2],
-			Prec: prec,
+			Type:    m[1] + " " + m[3],
+			Prec:    prec,
+			IsArray: isArray,
 		}, nil
 	}
-	// extract is array
-	isArray := false
-	if strings.HasSuffix(typ, "[]") {
-		typ, isArray = typ[:len(typ)-len("[]")], true
-	}
 	// extract unsigned
 	unsigned := false
 	if strings.HasSuffix(typ, " unsigned") {
@@ -202,6 +239,14 @@ func ParseType(typ, driver string) (Type, error) {
 		}
 		typ = typ[:m[0]]
 	}
+	// special case for duckdb nested types, keeping the case of struct field
+	// names and enum values
+	if i := strings.IndexByte(typ, '('); driver == "duckdb" && i != -1 {
+		return Type{
+			Type:    strings.ToLower(strings.TrimSpace(typ[:i])) + typ[i:],
+			IsArray: isArray,
+		}, nil
+	}
 	return Type{
 		Type:     strings.ToLower(strings.TrimSpace(typ)),
 		Prec:     prec,
@@ -211,9 +256,9 @@ func ParseType(typ, driver string) (Type, error) {
 	}, nil
 }
 
-// oracleTimestampRE is the regexp that matches "timestamp(precision) with [local]
-// time zone" definitions in oracle databases.
-var oracleTimestampRE = regexp.MustCompile(`^timestamp\((\d)\) (with(?: local)? time zone)$`)
+// timeZoneRE is the regexp that matches "time[stamp](precision) with[out]
+// [local] time zone" definitions.
+var timeZoneRE = regexp.MustCompile(`^(time|timestamp)\((\d+)\) (with(?:out)?(?: local)? time zone)$`)
 
 // precRE is the regexp that matches "(precision[,scale])" definitions in a
 // database.